@@ -2,6 +2,7 @@ package bindata
 
 import (
 	"embed"
+	"path"
 )
 
 //go:embed assets/*
@@ -22,3 +23,20 @@ func MustAsset(name string) []byte {
 
 	return data
 }
+
+// AssetNames returns the names of the files in the named asset directory.
+func AssetNames(dir string) ([]string, error) {
+	entries, err := f.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		names = append(names, path.Join(dir, entry.Name()))
+	}
+	return names, nil
+}
@@ -2,20 +2,31 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
 
+	"github.com/openshift/lws-operator/pkg/cmd/cmdutil"
+	"github.com/openshift/lws-operator/pkg/cmd/diff"
+	"github.com/openshift/lws-operator/pkg/cmd/mustgather"
 	"github.com/openshift/lws-operator/pkg/cmd/operator"
+	"github.com/openshift/lws-operator/pkg/cmd/preflight"
+	"github.com/openshift/lws-operator/pkg/cmd/render"
+	"github.com/openshift/lws-operator/pkg/cmd/status"
 )
 
 func main() {
 	command := NewLWSOperatorCommand(context.Background())
 	if err := command.Execute(); err != nil {
-		_, err := fmt.Fprintf(os.Stderr, "%v\n", err)
-		if err != nil {
-			fmt.Printf("Unable to print err to stderr: %v", err)
+		_, printErr := fmt.Fprintf(os.Stderr, "%v\n", err)
+		if printErr != nil {
+			fmt.Printf("Unable to print err to stderr: %v", printErr)
+		}
+		var exitErr *cmdutil.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.Code)
 		}
 		os.Exit(1)
 	}
@@ -35,5 +46,10 @@ func NewLWSOperatorCommand(ctx context.Context) *cobra.Command {
 	}
 
 	cmd.AddCommand(operator.NewOperator(ctx))
+	cmd.AddCommand(render.NewRender())
+	cmd.AddCommand(mustgather.NewMustGather(ctx))
+	cmd.AddCommand(preflight.NewPreflight(ctx))
+	cmd.AddCommand(diff.NewDiff(ctx))
+	cmd.AddCommand(status.NewStatus(ctx))
 	return cmd
 }
@@ -0,0 +1,20 @@
+// Package deploy embeds the RBAC manifests of the operator, so its permissions can be checked against
+// the rules it is installed with.
+package deploy
+
+import _ "embed"
+
+var (
+	// OperatorClusterRole is the ClusterRole of the operator ServiceAccount.
+	//go:embed 04_00_clusterrole.yaml
+	OperatorClusterRole []byte
+
+	// OperatorRole is the Role of the operator ServiceAccount in the operator namespace.
+	//go:embed 03_00_role.yaml
+	OperatorRole []byte
+
+	// OperandNamespaceClusterRole is the ClusterRole the operator binds to itself in a dedicated operand
+	// namespace.
+	//go:embed 04_02_operand_namespace_clusterrole.yaml
+	OperandNamespaceClusterRole []byte
+)
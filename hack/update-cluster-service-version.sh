@@ -32,3 +32,6 @@ yq -i '.spec.install.spec.permissions[0].rules = load("'"${LWS_DEPLOY_DIR}"'/03_
 yq -i '.spec.install.spec.permissions[0].rules += load("'"${LWS_DEPLOY_DIR}"'/02_03_operand_role.yaml").rules' "${CLUSTER_SERVICE_VERSION_FILE}"
 
 yq -i '.spec.install.spec.deployments[0].spec = load("'"${LWS_DEPLOY_DIR}"'/05_deployment.yaml").spec' "${CLUSTER_SERVICE_VERSION_FILE}"
+
+# bundled next to the CSV, since OLM only creates the ClusterRoles of the clusterPermissions
+cp "${LWS_DEPLOY_DIR}/04_02_operand_namespace_clusterrole.yaml" "${SCRIPT_ROOT}/manifests/openshift-lws-operator-operand-namespace_rbac.authorization.k8s.io_v1_clusterrole.yaml"
@@ -4,6 +4,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	configv1 "github.com/openshift/api/config/v1"
 	operatorv1 "github.com/openshift/api/operator/v1"
 )
 
@@ -43,6 +44,249 @@ type LeaderWorkerSetOperatorSpec struct {
 	//
 	// +optional
 	NodePlacement *NodePlacement `json:"nodePlacement,omitempty"`
+
+	// operandNamespace is the namespace the lws-controller-manager and its namespaced resources are
+	// deployed to.
+	//
+	// If unset, the operand is deployed to the namespace the operator runs in.
+	//
+	// The operator creates the namespace if needed and labels it with the restricted pod security
+	// level. When the value changes, the operator restarts, deploys the operand to the new namespace
+	// and deletes the resources it created in the previous one. The previous namespace itself is kept.
+	//
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +optional
+	OperandNamespace string `json:"operandNamespace,omitempty"`
+
+	// operand configures the lws-controller-manager image and the policy it is validated against.
+	//
+	// +optional
+	Operand *OperandSpec `json:"operand,omitempty"`
+
+	// operandLogging configures the format of the lws-controller-manager logs. The verbosity is
+	// configured by logLevel.
+	//
+	// If unset, the operand logs in its default format.
+	//
+	// +optional
+	OperandLogging *OperandLogging `json:"operandLogging,omitempty"`
+
+	// debug raises the verbosity of the lws-controller-manager, and optionally of the operator, until
+	// expiresAt. When the window ends the previous log levels are restored and a DebugWindowEnded
+	// event is recorded; the debug block can then be removed.
+	//
+	// +optional
+	Debug *DebugSpec `json:"debug,omitempty"`
+
+	// overrides take individual operand objects out of management, e.g. to hotfix the validating
+	// webhook configuration, while the operator keeps reconciling everything else. Unmanaged objects
+	// are neither applied nor deleted by the operator. Active overrides are reported in the
+	// UnsupportedOverrides condition.
+	//
+	// +kubebuilder:validation:MaxItems=64
+	// +listType=atomic
+	// +optional
+	Overrides []ComponentOverride `json:"overrides,omitempty"`
+
+	// leaderWorkerSetMetrics limits the cardinality of the lws_leaderworkerset_* metrics the operator
+	// exposes for every LeaderWorkerSet.
+	//
+	// If unset, the defaults of the fields apply.
+	//
+	// +optional
+	LeaderWorkerSetMetrics *LeaderWorkerSetMetrics `json:"leaderWorkerSetMetrics,omitempty"`
+}
+
+// LeaderWorkerSetMetrics limits the series of the lws_leaderworkerset_* metrics.
+type LeaderWorkerSetMetrics struct {
+	// maxLeaderWorkerSets is the maximum number of LeaderWorkerSets metrics are exposed for. When the
+	// cluster has more, the first ones by namespace and name are exposed and the others are counted in
+	// lws_leaderworkerset_metrics_dropped. 0 disables the metrics.
+	//
+	// If unset, defaults to 500.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=5000
+	// +optional
+	MaxLeaderWorkerSets *int32 `json:"maxLeaderWorkerSets,omitempty"`
+
+	// maxGroupsPerLeaderWorkerSet is the maximum number of groups of a LeaderWorkerSet whose restarts
+	// are exposed, starting from group index 0. 0 disables lws_leaderworkerset_group_restarts and the
+	// watch of the LeaderWorkerSet pods it is computed from.
+	//
+	// If unset, defaults to 16.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=256
+	// +optional
+	MaxGroupsPerLeaderWorkerSet *int32 `json:"maxGroupsPerLeaderWorkerSet,omitempty"`
+}
+
+// ComponentOverride allows overriding the operator's behavior for a single operand object.
+type ComponentOverride struct {
+	// group is the API group of the object, empty for the core API group.
+	//
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// kind is the kind of the object, e.g. ValidatingWebhookConfiguration.
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	// +required
+	Kind string `json:"kind"`
+
+	// namespace is the namespace of the object. If empty, the override matches the object in any
+	// namespace, including cluster-scoped objects.
+	//
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// name is the name of the object.
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	// +required
+	Name string `json:"name"`
+
+	// unmanaged stops the operator from applying or deleting the object.
+	//
+	// +required
+	Unmanaged bool `json:"unmanaged"`
+}
+
+// DebugSpec is a time-boxed debug window.
+type DebugSpec struct {
+	// level is the log level of the lws-controller-manager during the window. It replaces logLevel.
+	//
+	// +kubebuilder:validation:Enum=Debug;Trace;TraceAll
+	// +required
+	Level operatorv1.LogLevel `json:"level"`
+
+	// expiresAt is the end of the debug window.
+	//
+	// +required
+	ExpiresAt metav1.Time `json:"expiresAt"`
+
+	// includeOperator also raises the log level of the operator to level during the window. It
+	// replaces operatorLogLevel.
+	//
+	// +optional
+	IncludeOperator bool `json:"includeOperator,omitempty"`
+
+	// pprof serves the Go profiling endpoints of the lws-controller-manager on 127.0.0.1:8082 during
+	// the window, reachable with oc port-forward.
+	//
+	// +optional
+	Pprof bool `json:"pprof,omitempty"`
+}
+
+// OperandLogEncoder is the log encoder of the operand.
+// +kubebuilder:validation:Enum=JSON;Console
+type OperandLogEncoder string
+
+const (
+	OperandLogEncoderJSON    OperandLogEncoder = "JSON"
+	OperandLogEncoderConsole OperandLogEncoder = "Console"
+)
+
+// OperandStacktraceLevel is the minimum level at which the operand logs stack traces.
+// +kubebuilder:validation:Enum=Info;Error;Panic
+type OperandStacktraceLevel string
+
+const (
+	OperandStacktraceLevelInfo  OperandStacktraceLevel = "Info"
+	OperandStacktraceLevelError OperandStacktraceLevel = "Error"
+	OperandStacktraceLevelPanic OperandStacktraceLevel = "Panic"
+)
+
+// OperandTimeEncoding is the format of the timestamps of the operand logs.
+// +kubebuilder:validation:Enum=Epoch;Millis;Nano;ISO8601;RFC3339;RFC3339Nano
+type OperandTimeEncoding string
+
+const (
+	OperandTimeEncodingEpoch       OperandTimeEncoding = "Epoch"
+	OperandTimeEncodingMillis      OperandTimeEncoding = "Millis"
+	OperandTimeEncodingNano        OperandTimeEncoding = "Nano"
+	OperandTimeEncodingISO8601     OperandTimeEncoding = "ISO8601"
+	OperandTimeEncodingRFC3339     OperandTimeEncoding = "RFC3339"
+	OperandTimeEncodingRFC3339Nano OperandTimeEncoding = "RFC3339Nano"
+)
+
+// OperandLogging configures the log format of the lws-controller-manager. Omitted fields keep the
+// defaults of the operand.
+//
+// +kubebuilder:validation:XValidation:rule="!has(self.timeEncoding) || !(self.timeEncoding in ['Epoch', 'Millis', 'Nano']) || !has(self.encoder) || self.encoder == 'JSON'",message="numeric time encodings are only supported by the JSON encoder"
+type OperandLogging struct {
+	// encoder is the log format, JSON for log pipelines or Console for humans.
+	//
+	// +optional
+	Encoder OperandLogEncoder `json:"encoder,omitempty"`
+
+	// stacktraceLevel is the minimum level at which stack traces are logged.
+	//
+	// +optional
+	StacktraceLevel OperandStacktraceLevel `json:"stacktraceLevel,omitempty"`
+
+	// timeEncoding is the format of the log timestamps. The numeric encodings Epoch, Millis and Nano
+	// can only be used with the JSON encoder.
+	//
+	// +optional
+	TimeEncoding OperandTimeEncoding `json:"timeEncoding,omitempty"`
+}
+
+// OperandSpec configures the lws-controller-manager image.
+type OperandSpec struct {
+	// image is the lws-controller-manager image, e.g. a hotfix image provided by support.
+	//
+	// If unset, the image the operator was released with is used.
+	//
+	// The image must be referenced by digest, e.g. quay.io/org/lws@sha256:<digest>, unless
+	// allowTagReference is set.
+	//
+	// +kubebuilder:validation:MaxLength=512
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// allowTagReference allows image to be referenced by tag. A tag can be moved to another image, so
+	// the operand might run an image that was not reviewed.
+	//
+	// +optional
+	AllowTagReference bool `json:"allowTagReference,omitempty"`
+
+	// allowedRegistries restricts the registries the operand image may be pulled from, including the
+	// image the operator was released with. An entry matches a registry host, e.g. registry.redhat.io,
+	// or a repository prefix, e.g. quay.io/org.
+	//
+	// If empty, images from any registry are allowed.
+	//
+	// +kubebuilder:validation:MaxItems=32
+	// +kubebuilder:validation:items:MaxLength=253
+	// +listType=set
+	// +optional
+	AllowedRegistries []string `json:"allowedRegistries,omitempty"`
+
+	// imagePullSecrets are secrets in the operand namespace used to pull the operand image, e.g. from
+	// a mirror registry that requires credentials. They are added to the lws-controller-manager
+	// ServiceAccount and pods, and the pods are rolled when their content changes.
+	//
+	// +kubebuilder:validation:MaxItems=16
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	// additionalTrustBundle references a ConfigMap in the operand namespace with PEM encoded CA
+	// certificates in the ca-bundle.crt key, which the lws-controller-manager trusts in addition to
+	// the system CAs. The pods are rolled when its content changes.
+	//
+	// Image pulls are performed by the nodes, so a registry CA must also be trusted by the nodes.
+	//
+	// +optional
+	AdditionalTrustBundle *corev1.LocalObjectReference `json:"additionalTrustBundle,omitempty"`
 }
 
 // NodePlacement describes node scheduling configuration for lws-controller-manager pods.
@@ -69,6 +313,106 @@ type NodePlacement struct {
 // LeaderWorkerSetOperatorStatus defines the observed state of LeaderWorkerSetOperator
 type LeaderWorkerSetOperatorStatus struct {
 	operatorv1.OperatorStatus `json:",inline"`
+
+	// operandImage is the image the lws-controller-manager Deployment was last applied with.
+	//
+	// +optional
+	OperandImage string `json:"operandImage,omitempty"`
+
+	// operandImageDigest is the digest of the image the lws-controller-manager runs, taken from
+	// operandImage or, for tag references, from the running pods.
+	//
+	// +optional
+	OperandImageDigest string `json:"operandImageDigest,omitempty"`
+
+	// operandNamespace is the namespace the operand objects were last applied to and garbage collected
+	// in. After spec.operandNamespace changes, the operator deletes the objects it left in this namespace
+	// and then updates it.
+	//
+	// +optional
+	OperandNamespace string `json:"operandNamespace,omitempty"`
+
+	// relatedObjects lists the objects the operator manages: the operator configuration, the operator and
+	// operand namespaces and every operand object that is not taken out of management by spec.overrides.
+	// It is used by oc adm inspect and support tooling to find the objects to collect.
+	//
+	// +optional
+	// +listType=atomic
+	RelatedObjects []configv1.ObjectReference `json:"relatedObjects,omitempty"`
+
+	// workloads summarizes the LeaderWorkerSets and DisaggregatedSets of all namespaces. It is updated
+	// at most every 30 seconds and is unset until the operand CRDs are served.
+	//
+	// +optional
+	Workloads *WorkloadInventory `json:"workloads,omitempty"`
+}
+
+// WorkloadInventory summarizes the workloads of the operand APIs.
+type WorkloadInventory struct {
+	// leaderWorkerSets counts the LeaderWorkerSets, including the ones created for DisaggregatedSets.
+	//
+	// +required
+	LeaderWorkerSets WorkloadCounts `json:"leaderWorkerSets"`
+
+	// disaggregatedSets counts the DisaggregatedSets.
+	//
+	// +required
+	DisaggregatedSets WorkloadCounts `json:"disaggregatedSets"`
+
+	// groups is the number of replica groups of all LeaderWorkerSets.
+	//
+	// +required
+	Groups int32 `json:"groups"`
+
+	// pods is the number of pods of all LeaderWorkerSet groups, the leaders included.
+	//
+	// +required
+	Pods int32 `json:"pods"`
+
+	// namespaces counts the workloads by namespace, for the namespaces with the most workloads.
+	//
+	// +kubebuilder:validation:MaxItems=100
+	// +listType=map
+	// +listMapKey=namespace
+	// +optional
+	Namespaces []NamespaceWorkloadCounts `json:"namespaces,omitempty"`
+}
+
+// WorkloadCounts counts the workloads of a kind by health.
+type WorkloadCounts struct {
+	// total is the number of workloads.
+	//
+	// +required
+	Total int32 `json:"total"`
+
+	// notAvailable is the number of workloads whose Available condition is not True.
+	//
+	// +required
+	NotAvailable int32 `json:"notAvailable"`
+
+	// progressing is the number of workloads whose Progressing condition is True.
+	//
+	// +required
+	Progressing int32 `json:"progressing"`
+}
+
+// NamespaceWorkloadCounts counts the workloads of a namespace.
+type NamespaceWorkloadCounts struct {
+	// namespace is the name of the namespace.
+	//
+	// +kubebuilder:validation:MaxLength=63
+	// +required
+	Namespace string `json:"namespace"`
+
+	// leaderWorkerSets is the number of LeaderWorkerSets in the namespace.
+	//
+	// +required
+	LeaderWorkerSets int32 `json:"leaderWorkerSets"`
+
+	// disaggregatedSets is the number of DisaggregatedSets in the namespace.
+	//
+	// +required
+	DisaggregatedSets int32 `json:"disaggregatedSets"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -20,10 +20,70 @@ limitations under the License.
 package v1
 
 import (
+	configv1 "github.com/openshift/api/config/v1"
 	corev1 "k8s.io/api/core/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentOverride) DeepCopyInto(out *ComponentOverride) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentOverride.
+func (in *ComponentOverride) DeepCopy() *ComponentOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DebugSpec) DeepCopyInto(out *DebugSpec) {
+	*out = *in
+	in.ExpiresAt.DeepCopyInto(&out.ExpiresAt)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DebugSpec.
+func (in *DebugSpec) DeepCopy() *DebugSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DebugSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LeaderWorkerSetMetrics) DeepCopyInto(out *LeaderWorkerSetMetrics) {
+	*out = *in
+	if in.MaxLeaderWorkerSets != nil {
+		in, out := &in.MaxLeaderWorkerSets, &out.MaxLeaderWorkerSets
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxGroupsPerLeaderWorkerSet != nil {
+		in, out := &in.MaxGroupsPerLeaderWorkerSet, &out.MaxGroupsPerLeaderWorkerSet
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LeaderWorkerSetMetrics.
+func (in *LeaderWorkerSetMetrics) DeepCopy() *LeaderWorkerSetMetrics {
+	if in == nil {
+		return nil
+	}
+	out := new(LeaderWorkerSetMetrics)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LeaderWorkerSetOperator) DeepCopyInto(out *LeaderWorkerSetOperator) {
 	*out = *in
@@ -94,6 +154,31 @@ func (in *LeaderWorkerSetOperatorSpec) DeepCopyInto(out *LeaderWorkerSetOperator
 		*out = new(NodePlacement)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Operand != nil {
+		in, out := &in.Operand, &out.Operand
+		*out = new(OperandSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OperandLogging != nil {
+		in, out := &in.OperandLogging, &out.OperandLogging
+		*out = new(OperandLogging)
+		**out = **in
+	}
+	if in.Debug != nil {
+		in, out := &in.Debug, &out.Debug
+		*out = new(DebugSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]ComponentOverride, len(*in))
+		copy(*out, *in)
+	}
+	if in.LeaderWorkerSetMetrics != nil {
+		in, out := &in.LeaderWorkerSetMetrics, &out.LeaderWorkerSetMetrics
+		*out = new(LeaderWorkerSetMetrics)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -111,6 +196,16 @@ func (in *LeaderWorkerSetOperatorSpec) DeepCopy() *LeaderWorkerSetOperatorSpec {
 func (in *LeaderWorkerSetOperatorStatus) DeepCopyInto(out *LeaderWorkerSetOperatorStatus) {
 	*out = *in
 	in.OperatorStatus.DeepCopyInto(&out.OperatorStatus)
+	if in.RelatedObjects != nil {
+		in, out := &in.RelatedObjects, &out.RelatedObjects
+		*out = make([]configv1.ObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Workloads != nil {
+		in, out := &in.Workloads, &out.Workloads
+		*out = new(WorkloadInventory)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -124,6 +219,22 @@ func (in *LeaderWorkerSetOperatorStatus) DeepCopy() *LeaderWorkerSetOperatorStat
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceWorkloadCounts) DeepCopyInto(out *NamespaceWorkloadCounts) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceWorkloadCounts.
+func (in *NamespaceWorkloadCounts) DeepCopy() *NamespaceWorkloadCounts {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceWorkloadCounts)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodePlacement) DeepCopyInto(out *NodePlacement) {
 	*out = *in
@@ -153,3 +264,89 @@ func (in *NodePlacement) DeepCopy() *NodePlacement {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandLogging) DeepCopyInto(out *OperandLogging) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandLogging.
+func (in *OperandLogging) DeepCopy() *OperandLogging {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandLogging)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandSpec) DeepCopyInto(out *OperandSpec) {
+	*out = *in
+	if in.AllowedRegistries != nil {
+		in, out := &in.AllowedRegistries, &out.AllowedRegistries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.AdditionalTrustBundle != nil {
+		in, out := &in.AdditionalTrustBundle, &out.AdditionalTrustBundle
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandSpec.
+func (in *OperandSpec) DeepCopy() *OperandSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadCounts) DeepCopyInto(out *WorkloadCounts) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadCounts.
+func (in *WorkloadCounts) DeepCopy() *WorkloadCounts {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadCounts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadInventory) DeepCopyInto(out *WorkloadInventory) {
+	*out = *in
+	out.LeaderWorkerSets = in.LeaderWorkerSets
+	out.DisaggregatedSets = in.DisaggregatedSets
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]NamespaceWorkloadCounts, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadInventory.
+func (in *WorkloadInventory) DeepCopy() *WorkloadInventory {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadInventory)
+	in.DeepCopyInto(out)
+	return out
+}
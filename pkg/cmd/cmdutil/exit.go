@@ -0,0 +1,16 @@
+package cmdutil
+
+// ExitError makes the lws-operator command exit with Code instead of 1, for subcommands whose exit code
+// tells apart the outcomes, e.g. differences from errors in diff.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
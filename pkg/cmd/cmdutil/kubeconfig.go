@@ -0,0 +1,26 @@
+package cmdutil
+
+import (
+	"github.com/spf13/pflag"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// KubeconfigFlags selects the cluster of the subcommands that talk to the API server directly,
+// outside of the controllercmd framework of the operator subcommand.
+type KubeconfigFlags struct {
+	Kubeconfig string
+	Context    string
+}
+
+func (f *KubeconfigFlags) AddFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&f.Kubeconfig, "kubeconfig", f.Kubeconfig, "path to the kubeconfig file, the in-cluster configuration or KUBECONFIG if empty")
+	flags.StringVar(&f.Context, "context", f.Context, "kubeconfig context to use")
+}
+
+// RESTConfig loads the client configuration with the usual kubectl precedence.
+func (f *KubeconfigFlags) RESTConfig() (*rest.Config, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = f.Kubeconfig
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{CurrentContext: f.Context}).ClientConfig()
+}
@@ -0,0 +1,41 @@
+package cmdutil
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"sigs.k8s.io/yaml"
+
+	leaderworkersetapiv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+	"github.com/openshift/lws-operator/pkg/operator/operatorclient"
+)
+
+// ReadOperatorConfig reads a LeaderWorkerSetOperator from a file, or stdin for -, rejecting unknown fields so typos are not
+// silently dropped from the rendered manifests.
+func ReadOperatorConfig(file string) (*leaderworkersetapiv1.LeaderWorkerSetOperator, error) {
+	config := &leaderworkersetapiv1.LeaderWorkerSetOperator{}
+	config.Name = operatorclient.OperatorConfigName
+	if file == "" {
+		return config, nil
+	}
+
+	var content []byte
+	var err error
+	if file == "-" {
+		content, err = io.ReadAll(os.Stdin)
+	} else {
+		content, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.UnmarshalStrict(bytes.TrimSpace(content), config); err != nil {
+		return nil, fmt.Errorf("unable to decode %s: %w", file, err)
+	}
+	if config.Kind != "" && config.Kind != "LeaderWorkerSetOperator" {
+		return nil, fmt.Errorf("%s contains a %s, expected a LeaderWorkerSetOperator", file, config.Kind)
+	}
+	return config, nil
+}
@@ -0,0 +1,256 @@
+package diff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/restmapper"
+
+	leaderworkersetapiv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+	"github.com/openshift/lws-operator/pkg/cmd/cmdutil"
+	operatorconfigclient "github.com/openshift/lws-operator/pkg/generated/clientset/versioned"
+	"github.com/openshift/lws-operator/pkg/operator"
+	"github.com/openshift/lws-operator/pkg/operator/operatorclient"
+)
+
+const (
+	// exitCodeDrift and exitCodeError are the exit codes of kubectl diff: objects differ, or the
+	// comparison could not run.
+	exitCodeDrift = 1
+	exitCodeError = 2
+
+	// operatorDeploymentName is the operator Deployment the operand image is read from.
+	operatorDeploymentName = "openshift-lws-operator"
+	operandImageEnv        = "RELATED_IMAGE_OPERAND_IMAGE"
+)
+
+type diffOptions struct {
+	kubeconfig        cmdutil.KubeconfigFlags
+	configFile        string
+	operatorNamespace string
+	operandImage      string
+}
+
+func NewDiff(ctx context.Context) *cobra.Command {
+	o := &diffOptions{
+		operatorNamespace: operator.DefaultOperatorNamespace,
+	}
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare the operand objects in the cluster with the manifests the operator applies",
+		Long: `Render the operand manifests as the operator applies them and print a unified diff for every live
+object that differs, e.g. after a hand edit. Fields populated by the API server, status and the CA bundles
+injected by cert-manager are ignored.
+
+The LeaderWorkerSetOperator of the cluster is rendered, or the one in --config to preview a change. The
+operand image is read from the operator Deployment unless --operand-image is set. The manifests carry the
+version of this binary in their labels, use the binary of the installed operator to find hand edits.
+
+Like kubectl diff, the command exits 0 when every object matches, 1 when an object differs or is missing,
+and 2 when the comparison could not run, e.g. because the cluster is unreachable.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.run(ctx, cmd.OutOrStdout(), cmd.ErrOrStderr())
+			var exitErr *cmdutil.ExitError
+			if err != nil && !errors.As(err, &exitErr) {
+				return &cmdutil.ExitError{Code: exitCodeError, Err: err}
+			}
+			return err
+		},
+	}
+	cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return &cmdutil.ExitError{Code: exitCodeError, Err: err}
+	})
+	o.kubeconfig.AddFlags(cmd.Flags())
+	cmd.Flags().StringVarP(&o.configFile, "config", "f", o.configFile, "LeaderWorkerSetOperator YAML file to render, the one of the cluster if empty")
+	cmd.Flags().StringVar(&o.operatorNamespace, "namespace", o.operatorNamespace, "namespace of the operator")
+	cmd.Flags().StringVar(&o.operandImage, "operand-image", o.operandImage, "operand image the operator is released with, read from the operator Deployment if empty")
+	return cmd
+}
+
+func (o *diffOptions) run(ctx context.Context, out, errOut io.Writer) error {
+	config, err := o.kubeconfig.RESTConfig()
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	operatorConfigClient, err := operatorconfigclient.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	operatorConfig, err := readOperatorConfig(ctx, operatorConfigClient, o.configFile)
+	if err != nil {
+		return err
+	}
+	operandImage := o.operandImage
+	if operandImage == "" {
+		if operandImage, err = o.installedOperandImage(ctx, kubeClient); err != nil {
+			return err
+		}
+	}
+	kubernetesPlatform, err := operator.IsKubernetesPlatform(kubeClient.Discovery())
+	if err != nil {
+		return err
+	}
+	monitoringAvailable, err := operator.IsMonitoringAvailable(kubeClient.Discovery())
+	if err != nil {
+		return err
+	}
+
+	d := &differ{
+		kubeClient:    kubeClient,
+		dynamicClient: dynamicClient,
+		mapper:        restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(kubeClient.Discovery())),
+		out:           out,
+		errOut:        errOut,
+	}
+	drifted, err := d.diff(ctx, operator.RenderOptions{
+		Operator:            operatorConfig,
+		OperatorNamespace:   o.operatorNamespace,
+		OperandImage:        operandImage,
+		Kubernetes:          kubernetesPlatform,
+		MonitoringAvailable: monitoringAvailable,
+		Now:                 time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	if drifted > 0 {
+		return &cmdutil.ExitError{Code: exitCodeDrift, Err: fmt.Errorf("%d operand objects differ from the desired state", drifted)}
+	}
+	return nil
+}
+
+// readOperatorConfig returns the LeaderWorkerSetOperator of the cluster, or the one in configFile with
+// the UID of the cluster one, which the owner references of the operand objects point to.
+func readOperatorConfig(ctx context.Context, client operatorconfigclient.Interface, configFile string) (*leaderworkersetapiv1.LeaderWorkerSetOperator, error) {
+	clusterConfig, err := client.OpenShiftOperatorV1().LeaderWorkerSetOperators().Get(ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if configFile == "" {
+		if err != nil {
+			return nil, fmt.Errorf("unable to get the LeaderWorkerSetOperator: %w", err)
+		}
+		return clusterConfig, nil
+	}
+	// nothing was applied for a LeaderWorkerSetOperator that does not exist yet
+	if apierrors.IsNotFound(err) {
+		return cmdutil.ReadOperatorConfig(configFile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the LeaderWorkerSetOperator: %w", err)
+	}
+
+	operatorConfig, err := cmdutil.ReadOperatorConfig(configFile)
+	if err != nil {
+		return nil, err
+	}
+	operatorConfig.UID = clusterConfig.UID
+	return operatorConfig, nil
+}
+
+// installedOperandImage reads the operand image the installed operator was released with.
+func (o *diffOptions) installedOperandImage(ctx context.Context, kubeClient kubernetes.Interface) (string, error) {
+	deployment, err := kubeClient.AppsV1().Deployments(o.operatorNamespace).Get(ctx, operatorDeploymentName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("unable to read the operand image from the operator Deployment, set --operand-image: %w", err)
+	}
+	if image := operandImageOf(deployment); image != "" {
+		return image, nil
+	}
+	return "", fmt.Errorf("the operator Deployment %s/%s has no %s, set --operand-image", o.operatorNamespace, operatorDeploymentName, operandImageEnv)
+}
+
+func operandImageOf(deployment *appsv1.Deployment) string {
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		for _, env := range container.Env {
+			if env.Name == operandImageEnv {
+				return env.Value
+			}
+		}
+	}
+	return ""
+}
+
+type differ struct {
+	kubeClient    kubernetes.Interface
+	dynamicClient dynamic.Interface
+	mapper        meta.RESTMapper
+	out, errOut   io.Writer
+}
+
+// diff writes the unified diffs of the operand objects that differ from the rendered manifests and
+// returns their number.
+func (d *differ) diff(ctx context.Context, options operator.RenderOptions) (int, error) {
+	annotations, err := operator.LivePodTemplateAnnotations(ctx, d.kubeClient, options)
+	if err != nil {
+		return 0, err
+	}
+	options.PodTemplateAnnotations = annotations
+	manifests, err := operator.RenderManifests(options)
+	if err != nil {
+		return 0, err
+	}
+
+	drifted := 0
+	for _, manifest := range manifests {
+		desired := manifest.Object
+		gvk := desired.GroupVersionKind()
+		name := fmt.Sprintf("%s/%s", gvk.Kind, desired.GetName())
+		if desired.GetNamespace() != "" {
+			name = fmt.Sprintf("%s/%s/%s", gvk.Kind, desired.GetNamespace(), desired.GetName())
+		}
+		mapping, err := d.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if meta.IsNoMatchError(err) {
+			// e.g. cert-manager is not installed yet, the reconciler is degraded until it is
+			fmt.Fprintf(d.errOut, "Skipping %s, %s is not served\n", name, gvk.GroupVersion())
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		var comparableLive *unstructured.Unstructured
+		live, err := d.dynamicClient.Resource(mapping.Resource).Namespace(desired.GetNamespace()).Get(ctx, desired.GetName(), metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+		case err != nil:
+			return 0, err
+		default:
+			if comparableLive, err = comparableObject(desired, live); err != nil {
+				return 0, err
+			}
+		}
+		diff, err := unifiedDiff(name, comparableLive, desired)
+		if err != nil {
+			return 0, err
+		}
+		if diff == "" {
+			continue
+		}
+		drifted++
+		if _, err := fmt.Fprint(d.out, diff); err != nil {
+			return 0, err
+		}
+	}
+	return drifted, nil
+}
@@ -0,0 +1,223 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+)
+
+// ignoredManagers write fields of the operand objects that are neither managed by the operator nor
+// hand edits, e.g. the revision annotation of the Deployment controller.
+var ignoredManagers = sets.New("kube-controller-manager")
+
+// injectedFields are written by cert-manager cainjector into the objects annotated with
+// cert-manager.io/inject-ca-from, they are ignored on both sides.
+var injectedFields = [][]string{
+	{"webhooks", "*", "clientConfig", "caBundle"},
+	{"spec", "conversion", "webhook", "clientConfig", "caBundle"},
+}
+
+// comparableObject returns the fields of the live object that the desired object sets or that any field
+// manager other than the API server owns. Fields defaulted by the API server, status and metadata
+// populated by the server have no manager and are dropped, while fields added by hand edits are kept and
+// show up as drift.
+func comparableObject(desired, live *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	owned := map[string]interface{}{}
+	for _, entry := range live.GetManagedFields() {
+		if entry.Subresource != "" || ignoredManagers.Has(entry.Manager) || entry.FieldsV1 == nil {
+			continue
+		}
+		fields := map[string]interface{}{}
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &fields); err != nil {
+			return nil, fmt.Errorf("unable to decode the managed fields of %s: %w", entry.Manager, err)
+		}
+		mergeFields(owned, fields)
+	}
+
+	obj := live.DeepCopy()
+	unstructured.RemoveNestedField(obj.Object, "status")
+	obj.SetManagedFields(nil)
+	obj.Object = prune(obj.Object, desired.Object, owned).(map[string]interface{})
+	removeInjectedFields(obj)
+	return obj, nil
+}
+
+// mergeFields adds the fields of a managed fields entry to the fields owned by any manager.
+func mergeFields(owned, fields map[string]interface{}) {
+	for key, value := range fields {
+		children, _ := value.(map[string]interface{})
+		existing, ok := owned[key].(map[string]interface{})
+		if !ok {
+			owned[key] = children
+			continue
+		}
+		// an empty set marks the whole field as owned, e.g. an atomic list
+		if len(existing) == 0 || len(children) == 0 {
+			owned[key] = map[string]interface{}{}
+			continue
+		}
+		mergeFields(existing, children)
+	}
+}
+
+// prune keeps the fields of live that are set in desired or owned, the managed fields format of owned
+// is documented in sigs.k8s.io/structured-merge-diff/fieldpath.
+func prune(live, desired interface{}, owned interface{}) interface{} {
+	ownedFields, isSet := owned.(map[string]interface{})
+	if isSet && len(ownedFields) == 0 {
+		return live
+	}
+
+	switch l := live.(type) {
+	case map[string]interface{}:
+		d, _ := desired.(map[string]interface{})
+		out := map[string]interface{}{}
+		for key, value := range l {
+			desiredValue, inDesired := d[key]
+			ownedValue, isOwned := ownedFields["f:"+key]
+			if !inDesired && !isOwned {
+				continue
+			}
+			out[key] = prune(value, desiredValue, ownedValue)
+		}
+		return out
+	case []interface{}:
+		d, _ := desired.([]interface{})
+		out := make([]interface{}, 0, len(l))
+		for i, value := range l {
+			desiredValue, inDesired := desiredElement(value, i, d)
+			ownedValue, isOwned := ownedElement(value, i, ownedFields)
+			if !inDesired && !isOwned {
+				continue
+			}
+			out = append(out, prune(value, desiredValue, ownedValue))
+		}
+		return out
+	default:
+		return live
+	}
+}
+
+// desiredElement finds the desired list element of a live one, by name for lists of objects and by
+// position otherwise.
+func desiredElement(value interface{}, index int, desired []interface{}) (interface{}, bool) {
+	if element, ok := value.(map[string]interface{}); ok {
+		if name, ok := element["name"]; ok {
+			for _, desiredValue := range desired {
+				if desiredElement, ok := desiredValue.(map[string]interface{}); ok && desiredElement["name"] == name {
+					return desiredValue, true
+				}
+			}
+			return nil, false
+		}
+	}
+	if index < len(desired) {
+		return desired[index], true
+	}
+	return nil, false
+}
+
+// ownedElement finds the owned fields of a list element, identified by its key fields, its value or its
+// position.
+func ownedElement(value interface{}, index int, owned map[string]interface{}) (interface{}, bool) {
+	for key, fields := range owned {
+		switch {
+		case strings.HasPrefix(key, "k:"):
+			keyFields := map[string]interface{}{}
+			element, ok := value.(map[string]interface{})
+			if !ok || json.Unmarshal([]byte(key[2:]), &keyFields) != nil {
+				continue
+			}
+			matches := true
+			for name, keyValue := range keyFields {
+				if !jsonEqual(element[name], keyValue) {
+					matches = false
+					break
+				}
+			}
+			if matches {
+				return fields, true
+			}
+		case strings.HasPrefix(key, "v:"):
+			var setValue interface{}
+			if json.Unmarshal([]byte(key[2:]), &setValue) == nil && jsonEqual(value, setValue) {
+				return fields, true
+			}
+		case strings.HasPrefix(key, "i:"):
+			if i, err := strconv.Atoi(key[2:]); err == nil && i == index {
+				return fields, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// jsonEqual compares values decoded from the API, holding int64, with values decoded from JSON, holding
+// float64.
+func jsonEqual(a, b interface{}) bool {
+	aJSON, aErr := json.Marshal(a)
+	bJSON, bErr := json.Marshal(b)
+	return aErr == nil && bErr == nil && string(aJSON) == string(bJSON)
+}
+
+func removeInjectedFields(obj *unstructured.Unstructured) {
+	for _, path := range injectedFields {
+		removeField(obj.Object, path)
+	}
+}
+
+func removeField(obj interface{}, path []string) {
+	if len(path) == 0 {
+		return
+	}
+	if path[0] == "*" {
+		items, _ := obj.([]interface{})
+		for _, item := range items {
+			removeField(item, path[1:])
+		}
+		return
+	}
+	fields, ok := obj.(map[string]interface{})
+	if !ok {
+		return
+	}
+	if len(path) == 1 {
+		delete(fields, path[0])
+		return
+	}
+	removeField(fields[path[0]], path[1:])
+}
+
+// unifiedDiff returns the unified diff of the YAML of the live and the desired object, empty if they are
+// equal. live is nil for objects that do not exist.
+func unifiedDiff(name string, live, desired *unstructured.Unstructured) (string, error) {
+	var liveYAML []byte
+	if live != nil {
+		var err error
+		if liveYAML, err = yaml.Marshal(live.Object); err != nil {
+			return "", err
+		}
+	}
+	desired = desired.DeepCopy()
+	removeInjectedFields(desired)
+	desiredYAML, err := yaml.Marshal(desired.Object)
+	if err != nil {
+		return "", err
+	}
+	if string(liveYAML) == string(desiredYAML) {
+		return "", nil
+	}
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(liveYAML)),
+		B:        difflib.SplitLines(string(desiredYAML)),
+		FromFile: "live/" + name,
+		ToFile:   "desired/" + name,
+		Context:  3,
+	})
+}
@@ -0,0 +1,305 @@
+package diff
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	leaderworkersetapiv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+	"github.com/openshift/lws-operator/pkg/cmd/cmdutil"
+	operatorconfigfake "github.com/openshift/lws-operator/pkg/generated/clientset/versioned/fake"
+	"github.com/openshift/lws-operator/pkg/operator"
+	"github.com/openshift/lws-operator/pkg/operator/operatorclient"
+)
+
+// applied returns a rendered manifest as the API server stores it after the operator applied it.
+func applied(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	live := obj.DeepCopy()
+	live.SetUID("5a3b")
+	live.SetResourceVersion("7")
+	live.SetCreationTimestamp(metav1.Now())
+	live.SetManagedFields([]metav1.ManagedFieldsEntry{{
+		Manager:    "lws-operator-" + strings.ToLower(obj.GetKind()),
+		Operation:  metav1.ManagedFieldsOperationApply,
+		FieldsType: "FieldsV1",
+		FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:labels":{}}}`)},
+	}})
+	return live
+}
+
+func TestDiff(t *testing.T) {
+	operatorConfig := &leaderworkersetapiv1.LeaderWorkerSetOperator{}
+	operatorConfig.Name = operatorclient.OperatorConfigName
+	kubeClient := kubefake.NewClientset(
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: operator.DefaultOperatorNamespace, Name: operator.WebhookCertificateSecretName, ResourceVersion: "42"}},
+	)
+	options := operator.RenderOptions{
+		Operator:          operatorConfig,
+		OperatorNamespace: operator.DefaultOperatorNamespace,
+		OperandImage:      "quay.io/example/lws:v1",
+		Now:               time.Now(),
+	}
+	annotations, err := operator.LivePodTemplateAnnotations(context.Background(), kubeClient, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if annotations["secrets/"+operator.WebhookCertificateSecretName] != "42" {
+		t.Fatalf("expected the resource version of the webhook certificate, got %v", annotations)
+	}
+	rendered := options
+	rendered.PodTemplateAnnotations = annotations
+	manifests, err := operator.RenderManifests(rendered)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	var objects []runtime.Object
+	var missing string
+	for _, manifest := range manifests {
+		obj := manifest.Object
+		gvk := obj.GroupVersionKind()
+		scope := meta.RESTScopeNamespace
+		if obj.GetNamespace() == "" {
+			scope = meta.RESTScopeRoot
+		}
+		mapper.Add(gvk, scope)
+
+		live := applied(obj)
+		switch gvk.Kind {
+		case "Deployment":
+			// defaulted by the API server
+			_ = unstructured.SetNestedField(live.Object, int64(600), "spec", "progressDeadlineSeconds")
+			_ = unstructured.SetNestedField(live.Object, int64(1), "status", "replicas")
+		case "ValidatingWebhookConfiguration", "MutatingWebhookConfiguration":
+			webhooks, _, _ := unstructured.NestedSlice(live.Object, "webhooks")
+			for _, webhook := range webhooks {
+				_ = unstructured.SetNestedField(webhook.(map[string]interface{}), "Y2E=", "clientConfig", "caBundle")
+			}
+			_ = unstructured.SetNestedSlice(live.Object, webhooks, "webhooks")
+		case "Service":
+			if obj.GetName() != "lws-webhook-service" {
+				break
+			}
+			// a hand edit
+			labels := live.GetLabels()
+			labels["example.com/team"] = "ml"
+			live.SetLabels(labels)
+			live.SetManagedFields(append(live.GetManagedFields(), metav1.ManagedFieldsEntry{
+				Manager:    "kubectl-edit",
+				Operation:  metav1.ManagedFieldsOperationUpdate,
+				FieldsType: "FieldsV1",
+				FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:labels":{"f:example.com/team":{}}}}`)},
+			}))
+		case "ClusterRoleBinding":
+			if missing == "" {
+				missing = obj.GetName()
+				continue
+			}
+		}
+		objects = append(objects, live)
+	}
+
+	out := &bytes.Buffer{}
+	errOut := &bytes.Buffer{}
+	d := &differ{
+		kubeClient:    kubeClient,
+		dynamicClient: dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), objects...),
+		mapper:        mapper,
+		out:           out,
+		errOut:        errOut,
+	}
+	drifted, err := d.diff(context.Background(), options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if drifted != 2 {
+		t.Errorf("expected the edited Service and the missing ClusterRoleBinding to differ, got %d:\n%s", drifted, out)
+	}
+	diff := out.String()
+	if !strings.Contains(diff, "--- live/Service/openshift-lws-operator/lws-webhook-service") || !strings.Contains(diff, "-    example.com/team: ml") {
+		t.Errorf("expected the hand edited Service label in the diff:\n%s", diff)
+	}
+	if !strings.Contains(diff, "+++ desired/ClusterRoleBinding/"+missing) {
+		t.Errorf("expected the missing ClusterRoleBinding in the diff:\n%s", diff)
+	}
+	for _, ignored := range []string{"progressDeadlineSeconds", "caBundle", "resourceVersion", "status"} {
+		if strings.Contains(diff, ignored) {
+			t.Errorf("expected %s to be ignored:\n%s", ignored, diff)
+		}
+	}
+	// the monitoring API is not served, so the monitoring group is not rendered
+	if strings.Contains(diff+errOut.String(), "ServiceMonitor") {
+		t.Errorf("expected the ServiceMonitor not to be rendered:\n%s%s", diff, errOut)
+	}
+}
+
+func TestDiffOperandNamespaceRoleBinding(t *testing.T) {
+	operatorConfig := &leaderworkersetapiv1.LeaderWorkerSetOperator{}
+	operatorConfig.Name = operatorclient.OperatorConfigName
+	operatorConfig.Spec.OperandNamespace = "lws-system"
+	options := operator.RenderOptions{
+		Operator:          operatorConfig,
+		OperatorNamespace: operator.DefaultOperatorNamespace,
+		OperandImage:      "quay.io/example/lws:v1",
+		Now:               time.Now(),
+	}
+	manifests, err := operator.RenderManifests(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	var objects []*unstructured.Unstructured
+	roleBinding := -1
+	for _, manifest := range manifests {
+		obj := manifest.Object
+		scope := meta.RESTScopeNamespace
+		if obj.GetNamespace() == "" {
+			scope = meta.RESTScopeRoot
+		}
+		mapper.Add(obj.GroupVersionKind(), scope)
+		if obj.GetKind() == "RoleBinding" && obj.GetNamespace() == "lws-system" && manifest.Group == "Namespace" {
+			roleBinding = len(objects)
+		}
+		objects = append(objects, applied(obj))
+	}
+	if roleBinding < 0 {
+		t.Fatalf("expected the RoleBinding of the operand namespace to be rendered")
+	}
+	name := "RoleBinding/lws-system/" + objects[roleBinding].GetName()
+
+	for _, tc := range []struct {
+		name     string
+		live     func(*unstructured.Unstructured) *unstructured.Unstructured
+		expected string
+	}{
+		{
+			name:     "deleted",
+			live:     func(*unstructured.Unstructured) *unstructured.Unstructured { return nil },
+			expected: "+++ desired/" + name,
+		},
+		{
+			name: "hand edited",
+			live: func(live *unstructured.Unstructured) *unstructured.Unstructured {
+				live = live.DeepCopy()
+				_ = unstructured.SetNestedSlice(live.Object, []interface{}{
+					map[string]interface{}{"kind": "ServiceAccount", "name": "lws-operator", "namespace": "lws-system"},
+				}, "subjects")
+				return live
+			},
+			expected: "+  namespace: openshift-lws-operator",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var live []runtime.Object
+			for i, obj := range objects {
+				if i == roleBinding {
+					if edited := tc.live(obj); edited != nil {
+						live = append(live, edited)
+					}
+					continue
+				}
+				live = append(live, obj)
+			}
+			out := &bytes.Buffer{}
+			d := &differ{
+				kubeClient:    kubefake.NewClientset(),
+				dynamicClient: dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), live...),
+				mapper:        mapper,
+				out:           out,
+				errOut:        &bytes.Buffer{},
+			}
+			drifted, err := d.diff(context.Background(), options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if drifted != 1 || !strings.Contains(out.String(), tc.expected) {
+				t.Errorf("expected %s to differ with %q, got %d objects:\n%s", name, tc.expected, drifted, out)
+			}
+		})
+	}
+}
+
+func TestReadOperatorConfigFromFile(t *testing.T) {
+	clusterConfig := &leaderworkersetapiv1.LeaderWorkerSetOperator{}
+	clusterConfig.Name = operatorclient.OperatorConfigName
+	clusterConfig.UID = "8c1f"
+	configFile := filepath.Join(t.TempDir(), "cluster.yaml")
+	content := "apiVersion: operator.openshift.io/v1\nkind: LeaderWorkerSetOperator\nmetadata:\n  name: cluster\nspec:\n  logLevel: Debug\n"
+	if err := os.WriteFile(configFile, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	operatorConfig, err := readOperatorConfig(context.Background(), operatorconfigfake.NewClientset(clusterConfig), configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if operatorConfig.Spec.LogLevel != "Debug" {
+		t.Errorf("expected the spec of the file, got %v", operatorConfig.Spec)
+	}
+	manifests, err := operator.RenderManifests(operator.RenderOptions{
+		Operator:          operatorConfig,
+		OperatorNamespace: operator.DefaultOperatorNamespace,
+		OperandImage:      "quay.io/example/lws:v1",
+		Now:               time.Now(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	owned := 0
+	for _, manifest := range manifests {
+		for _, ownerReference := range manifest.Object.GetOwnerReferences() {
+			owned++
+			if ownerReference.UID != clusterConfig.UID {
+				t.Errorf("expected %s/%s to be owned by UID %s, got %q", manifest.Object.GetKind(), manifest.Object.GetName(), clusterConfig.UID, ownerReference.UID)
+			}
+		}
+	}
+	if owned == 0 {
+		t.Errorf("expected the manifests to be owned by the LeaderWorkerSetOperator")
+	}
+
+	// a LeaderWorkerSetOperator that is not created yet
+	operatorConfig, err = readOperatorConfig(context.Background(), operatorconfigfake.NewClientset(), configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if operatorConfig.UID != "" || operatorConfig.Spec.LogLevel != "Debug" {
+		t.Errorf("expected the file as is, got %v", operatorConfig)
+	}
+}
+
+func TestDiffExitCodes(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		args []string
+	}{
+		{name: "invalid flag", args: []string{"--no-such-flag"}},
+		{name: "unreachable cluster", args: []string{"--kubeconfig", filepath.Join(t.TempDir(), "missing")}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := NewDiff(context.Background())
+			cmd.SetArgs(tc.args)
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetErr(&bytes.Buffer{})
+			var exitErr *cmdutil.ExitError
+			if err := cmd.Execute(); !errors.As(err, &exitErr) || exitErr.Code != exitCodeError {
+				t.Errorf("expected exit code %d, got %v", exitCodeError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package mustgather
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openshift/lws-operator/pkg/cmd/cmdutil"
+	"github.com/openshift/lws-operator/pkg/operator"
+)
+
+type mustGatherOptions struct {
+	kubeconfig        cmdutil.KubeconfigFlags
+	destDir           string
+	operatorNamespace string
+	eventsSince       time.Duration
+}
+
+func NewMustGather(ctx context.Context) *cobra.Command {
+	o := &mustGatherOptions{
+		destDir:           "must-gather",
+		operatorNamespace: operator.DefaultOperatorNamespace,
+		eventsSince:       6 * time.Hour,
+	}
+	cmd := &cobra.Command{
+		Use:   "must-gather",
+		Short: "Collect the LeaderWorkerSet Operator data needed by support cases",
+		Long: `Collect the operator configuration, the operand objects, pods and logs, the webhook configurations,
+CRDs, cert-manager objects, recent events and the LeaderWorkerSets and DisaggregatedSets of all namespaces,
+in the directory layout of oc adm inspect. The values of Secrets are redacted.
+
+The operator image runs it as the gather script of oc adm must-gather --image=<operator image>.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.run(ctx)
+		},
+	}
+	o.kubeconfig.AddFlags(cmd.Flags())
+	cmd.Flags().StringVar(&o.destDir, "dest-dir", o.destDir, "directory to write the collected data to")
+	cmd.Flags().StringVar(&o.operatorNamespace, "namespace", o.operatorNamespace, "namespace of the operator")
+	cmd.Flags().DurationVar(&o.eventsSince, "events-since", o.eventsSince, "age of the oldest events collected")
+	return cmd
+}
+
+func (o *mustGatherOptions) run(ctx context.Context) error {
+	config, err := o.kubeconfig.RESTConfig()
+	if err != nil {
+		return err
+	}
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(o.destDir, 0o755); err != nil {
+		return err
+	}
+
+	g := &gatherer{
+		dynamicClient:     dynamicClient,
+		kubeClient:        kubeClient,
+		destDir:           o.destDir,
+		operatorNamespace: o.operatorNamespace,
+		eventsSince:       o.eventsSince,
+		now:               time.Now,
+	}
+	if err := g.gather(ctx); err != nil {
+		return err
+	}
+	// the gathering is best effort, the data collected so far is still useful
+	if len(g.errs) > 0 {
+		fmt.Fprintf(os.Stderr, "Some data could not be collected, see %s/gather-errors.log\n", o.destDir)
+	}
+	return nil
+}
@@ -0,0 +1,316 @@
+package mustgather
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/lws-operator/pkg/operator"
+	"github.com/openshift/lws-operator/pkg/operator/operatorclient"
+)
+
+// redactedValue replaces the values of gathered Secrets.
+const redactedValue = "REDACTED"
+
+var (
+	operatorConfigGVR   = schema.GroupVersionResource{Group: "operator.openshift.io", Version: "v1", Resource: "leaderworkersetoperators"}
+	namespacesGVR       = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+	podsGVR             = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	secretsGVR          = schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	leaderWorkerSetsGVR = schema.GroupVersionResource{Group: "leaderworkerset.x-k8s.io", Version: "v1", Resource: "leaderworkersets"}
+)
+
+// gatherTarget is a resource collected by the must-gather.
+type gatherTarget struct {
+	gvr schema.GroupVersionResource
+	// names restricts a cluster-scoped target to the given objects.
+	names []string
+	// labelSelector restricts the target to the objects applied by the operator.
+	labelSelector string
+}
+
+// clusterTargets are the cluster-scoped objects of the operator and the operand.
+var clusterTargets = []gatherTarget{
+	{gvr: operatorConfigGVR, names: []string{operatorclient.OperatorConfigName}},
+	{
+		gvr: schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"},
+		names: []string{
+			"leaderworkersetoperators.operator.openshift.io",
+			"leaderworkersets.leaderworkerset.x-k8s.io",
+			"disaggregatedsets.disaggregatedset.x-k8s.io",
+			"disaggregatedsetrolescalers.disaggregatedset.x-k8s.io",
+		},
+	},
+	{gvr: schema.GroupVersionResource{Group: "admissionregistration.k8s.io", Version: "v1", Resource: "mutatingwebhookconfigurations"}, labelSelector: operator.ManagedBySelector},
+	{gvr: schema.GroupVersionResource{Group: "admissionregistration.k8s.io", Version: "v1", Resource: "validatingwebhookconfigurations"}, labelSelector: operator.ManagedBySelector},
+	{gvr: schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"}, labelSelector: operator.ManagedBySelector},
+	{gvr: schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterrolebindings"}, labelSelector: operator.ManagedBySelector},
+}
+
+// namespaceTargets are collected from the operator and the operand namespace. Resources that are not
+// served, e.g. without cert-manager or OLM, are skipped.
+var namespaceTargets = []gatherTarget{
+	{gvr: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}},
+	{gvr: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"}},
+	{gvr: podsGVR},
+	{gvr: schema.GroupVersionResource{Version: "v1", Resource: "services"}},
+	{gvr: schema.GroupVersionResource{Version: "v1", Resource: "serviceaccounts"}},
+	{gvr: schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}},
+	{gvr: secretsGVR},
+	{gvr: schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "roles"}},
+	{gvr: schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "rolebindings"}},
+	// the leader election Leases of the operator and the operand
+	{gvr: schema.GroupVersionResource{Group: "coordination.k8s.io", Version: "v1", Resource: "leases"}},
+	{gvr: schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "issuers"}},
+	{gvr: schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}},
+	{gvr: schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificaterequests"}},
+	{gvr: schema.GroupVersionResource{Group: "monitoring.coreos.com", Version: "v1", Resource: "servicemonitors"}},
+	{gvr: schema.GroupVersionResource{Group: "operators.coreos.com", Version: "v1alpha1", Resource: "clusterserviceversions"}},
+	{gvr: schema.GroupVersionResource{Group: "operators.coreos.com", Version: "v1alpha1", Resource: "subscriptions"}},
+	{gvr: schema.GroupVersionResource{Group: "operators.coreos.com", Version: "v1alpha1", Resource: "installplans"}},
+}
+
+// workloadTargets are the user workloads of the operand, collected from all namespaces.
+var workloadTargets = []gatherTarget{
+	{gvr: leaderWorkerSetsGVR},
+	{gvr: schema.GroupVersionResource{Group: "disaggregatedset.x-k8s.io", Version: "v1", Resource: "disaggregatedsets"}},
+	{gvr: schema.GroupVersionResource{Group: "disaggregatedset.x-k8s.io", Version: "v1", Resource: "disaggregatedsetrolescalers"}},
+}
+
+// gatherer writes the collected objects in the directory layout of oc adm inspect:
+// cluster-scoped-resources/<group>/<resource>/<name>.yaml, namespaces/<namespace>/<group>/<resource>.yaml
+// and namespaces/<namespace>/pods/<pod>/<container>/<container>/logs/{current,previous}.log.
+type gatherer struct {
+	dynamicClient     dynamic.Interface
+	kubeClient        kubernetes.Interface
+	destDir           string
+	operatorNamespace string
+	// eventsSince is the age of the oldest events collected.
+	eventsSince time.Duration
+	now         func() time.Time
+	// errs are reported at the end, a failure to collect one resource does not stop the gathering.
+	errs []error
+}
+
+func (g *gatherer) gather(ctx context.Context) error {
+	namespaces := sets.New(g.operatorNamespace)
+	config, err := g.dynamicClient.Resource(operatorConfigGVR).Get(ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		if operandNamespace, _, _ := unstructured.NestedString(config.Object, "spec", "operandNamespace"); operandNamespace != "" {
+			namespaces.Insert(operandNamespace)
+		}
+	case !apierrors.IsNotFound(err):
+		g.errs = append(g.errs, fmt.Errorf("unable to get the operator configuration: %w", err))
+	}
+
+	for _, target := range clusterTargets {
+		g.gatherClusterTarget(ctx, target)
+	}
+	for _, namespace := range sets.List(namespaces) {
+		g.gatherClusterTarget(ctx, gatherTarget{gvr: namespacesGVR, names: []string{namespace}})
+		for _, target := range namespaceTargets {
+			g.gatherNamespaceTarget(ctx, target, namespace)
+		}
+		g.gatherEvents(ctx, namespace)
+		g.gatherPodLogs(ctx, namespace)
+	}
+	for _, target := range workloadTargets {
+		g.gatherNamespaceTarget(ctx, target, metav1.NamespaceAll)
+	}
+
+	if len(g.errs) > 0 {
+		messages := make([]string, 0, len(g.errs))
+		for _, err := range g.errs {
+			messages = append(messages, err.Error())
+		}
+		sort.Strings(messages)
+		return os.WriteFile(filepath.Join(g.destDir, "gather-errors.log"), []byte(strings.Join(messages, "\n")+"\n"), 0o644)
+	}
+	return nil
+}
+
+func (g *gatherer) gatherClusterTarget(ctx context.Context, target gatherTarget) {
+	var objects []unstructured.Unstructured
+	if len(target.names) > 0 {
+		for _, name := range target.names {
+			obj, err := g.dynamicClient.Resource(target.gvr).Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				g.recordError(target.gvr, err)
+				continue
+			}
+			objects = append(objects, *obj)
+		}
+	} else {
+		list, err := g.dynamicClient.Resource(target.gvr).List(ctx, metav1.ListOptions{LabelSelector: target.labelSelector})
+		if err != nil {
+			g.recordError(target.gvr, err)
+			return
+		}
+		objects = list.Items
+	}
+
+	for i := range objects {
+		path := filepath.Join(g.destDir, "cluster-scoped-resources", groupDir(target.gvr), target.gvr.Resource, objects[i].GetName()+".yaml")
+		g.writeYAML(path, sanitize(&objects[i]).Object)
+	}
+}
+
+// gatherNamespaceTarget writes one list per namespace, all namespaces with metav1.NamespaceAll.
+func (g *gatherer) gatherNamespaceTarget(ctx context.Context, target gatherTarget, namespace string) {
+	list, err := g.dynamicClient.Resource(target.gvr).Namespace(namespace).List(ctx, metav1.ListOptions{LabelSelector: target.labelSelector})
+	if err != nil {
+		g.recordError(target.gvr, err)
+		return
+	}
+
+	byNamespace := map[string][]interface{}{}
+	for i := range list.Items {
+		obj := sanitize(&list.Items[i])
+		if target.gvr == secretsGVR {
+			redactSecret(obj)
+		}
+		byNamespace[obj.GetNamespace()] = append(byNamespace[obj.GetNamespace()], obj.Object)
+	}
+	for ns, items := range byNamespace {
+		path := filepath.Join(g.destDir, "namespaces", ns, groupDir(target.gvr), target.gvr.Resource+".yaml")
+		g.writeYAML(path, map[string]interface{}{"apiVersion": "v1", "kind": "List", "items": items})
+	}
+}
+
+// gatherEvents collects the events of the last eventsSince.
+func (g *gatherer) gatherEvents(ctx context.Context, namespace string) {
+	events, err := g.kubeClient.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		g.errs = append(g.errs, fmt.Errorf("unable to list events in %s: %w", namespace, err))
+		return
+	}
+	cutoff := g.now().Add(-g.eventsSince)
+	recent := &corev1.EventList{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "EventList"}}
+	for _, event := range events.Items {
+		if eventTime(event).After(cutoff) {
+			event.ManagedFields = nil
+			recent.Items = append(recent.Items, event)
+		}
+	}
+	sort.SliceStable(recent.Items, func(i, j int) bool {
+		return eventTime(recent.Items[i]).Before(eventTime(recent.Items[j]))
+	})
+	g.writeYAML(filepath.Join(g.destDir, "namespaces", namespace, "core", "events.yaml"), recent)
+}
+
+func eventTime(event corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+// gatherPodLogs collects the current logs of every container, and the logs of the previous instance
+// of containers that restarted.
+func (g *gatherer) gatherPodLogs(ctx context.Context, namespace string) {
+	pods, err := g.kubeClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		g.errs = append(g.errs, fmt.Errorf("unable to list pods in %s: %w", namespace, err))
+		return
+	}
+	for _, pod := range pods.Items {
+		statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+		for _, status := range statuses {
+			dir := filepath.Join(g.destDir, "namespaces", namespace, "pods", pod.Name, status.Name, status.Name, "logs")
+			g.writeLogs(ctx, pod, status.Name, false, filepath.Join(dir, "current.log"))
+			if status.RestartCount > 0 {
+				g.writeLogs(ctx, pod, status.Name, true, filepath.Join(dir, "previous.log"))
+			}
+		}
+	}
+}
+
+func (g *gatherer) writeLogs(ctx context.Context, pod corev1.Pod, container string, previous bool, path string) {
+	logs, err := g.kubeClient.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{Container: container, Previous: previous}).DoRaw(ctx)
+	if err != nil {
+		g.errs = append(g.errs, fmt.Errorf("unable to get logs of %s/%s container %s: %w", pod.Namespace, pod.Name, container, err))
+		return
+	}
+	g.writeFile(path, logs)
+}
+
+// recordError skips resources that are not served, e.g. cert-manager resources without cert-manager.
+func (g *gatherer) recordError(gvr schema.GroupVersionResource, err error) {
+	if apierrors.IsNotFound(err) {
+		klog.V(2).Infof("Skipping %s: %v", gvr.GroupResource(), err)
+		return
+	}
+	g.errs = append(g.errs, fmt.Errorf("unable to gather %s: %w", gvr.GroupResource(), err))
+}
+
+func (g *gatherer) writeYAML(path string, obj interface{}) {
+	content, err := yaml.Marshal(obj)
+	if err != nil {
+		g.errs = append(g.errs, fmt.Errorf("unable to encode %s: %w", path, err))
+		return
+	}
+	g.writeFile(path, content)
+}
+
+func (g *gatherer) writeFile(path string, content []byte) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		g.errs = append(g.errs, err)
+		return
+	}
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		g.errs = append(g.errs, err)
+	}
+}
+
+// sanitize drops the managed fields, which make up most of the objects without helping support.
+func sanitize(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	obj.SetManagedFields(nil)
+	return obj
+}
+
+// redactSecret keeps the keys and type of a Secret so missing keys can be diagnosed, and drops the
+// values, including the ones a client-side apply recorded in the annotations.
+func redactSecret(secret *unstructured.Unstructured) {
+	for _, field := range []string{"data", "stringData"} {
+		values, found, _ := unstructured.NestedMap(secret.Object, field)
+		if !found {
+			continue
+		}
+		for key := range values {
+			values[key] = redactedValue
+		}
+		_ = unstructured.SetNestedMap(secret.Object, values, field)
+	}
+	annotations := secret.GetAnnotations()
+	if _, ok := annotations[corev1.LastAppliedConfigAnnotation]; ok {
+		annotations[corev1.LastAppliedConfigAnnotation] = redactedValue
+		secret.SetAnnotations(annotations)
+	}
+}
+
+// groupDir is the directory of an API group, core for the legacy group.
+func groupDir(gvr schema.GroupVersionResource) string {
+	if gvr.Group == "" {
+		return "core"
+	}
+	return gvr.Group
+}
@@ -0,0 +1,114 @@
+package mustgather
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openshift/lws-operator/pkg/operator"
+)
+
+func newObject(gvr schema.GroupVersionResource, kind, namespace, name string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(gvr.GroupVersion().String())
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	return obj
+}
+
+func TestGather(t *testing.T) {
+	now := time.Now()
+	config := newObject(operatorConfigGVR, "LeaderWorkerSetOperator", "", "cluster")
+	_ = unstructured.SetNestedField(config.Object, "lws-system", "spec", "operandNamespace")
+	secret := newObject(secretsGVR, "Secret", "lws-system", "webhook-server-cert")
+	_ = unstructured.SetNestedMap(secret.Object, map[string]interface{}{"tls.key": "a2V5"}, "data")
+	unmanagedRole := newObject(schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"}, "ClusterRole", "", "admin")
+	managedRole := newObject(schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"}, "ClusterRole", "", "lws-manager-role")
+	managedRole.SetLabels(map[string]string{"leaderworkerset.operator.openshift.io/managed-by": "lws-operator"})
+	managedRole.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: "lws-operator-rbac"}})
+
+	listKinds := map[schema.GroupVersionResource]string{}
+	for _, targets := range [][]gatherTarget{clusterTargets, namespaceTargets, workloadTargets} {
+		for _, target := range targets {
+			listKinds[target.gvr] = "List"
+		}
+	}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds,
+		config,
+		secret,
+		unmanagedRole,
+		managedRole,
+		newObject(leaderWorkerSetsGVR, "LeaderWorkerSet", "team-a", "vllm"),
+		newObject(leaderWorkerSetsGVR, "LeaderWorkerSet", "team-b", "sglang"),
+	)
+	kubeClient := kubefake.NewClientset(
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "lws-system", Name: "lws-controller-manager-1"},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "manager", RestartCount: 1},
+			}},
+		},
+		&corev1.Event{ObjectMeta: metav1.ObjectMeta{Namespace: operator.DefaultOperatorNamespace, Name: "recent"}, LastTimestamp: metav1.NewTime(now.Add(-time.Minute))},
+		&corev1.Event{ObjectMeta: metav1.ObjectMeta{Namespace: operator.DefaultOperatorNamespace, Name: "old"}, LastTimestamp: metav1.NewTime(now.Add(-48 * time.Hour))},
+	)
+
+	g := &gatherer{
+		dynamicClient:     dynamicClient,
+		kubeClient:        kubeClient,
+		destDir:           t.TempDir(),
+		operatorNamespace: operator.DefaultOperatorNamespace,
+		eventsSince:       time.Hour,
+		now:               func() time.Time { return now },
+	}
+	if err := g.gather(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(g.errs) > 0 {
+		t.Fatalf("unexpected errors: %v", g.errs)
+	}
+
+	read := func(path string) string {
+		t.Helper()
+		content, err := os.ReadFile(filepath.Join(g.destDir, path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(content)
+	}
+	exists := func(path string) bool {
+		_, err := os.Stat(filepath.Join(g.destDir, path))
+		return err == nil
+	}
+
+	read("cluster-scoped-resources/operator.openshift.io/leaderworkersetoperators/cluster.yaml")
+	if role := read("cluster-scoped-resources/rbac.authorization.k8s.io/clusterroles/lws-manager-role.yaml"); strings.Contains(role, "managedFields") {
+		t.Errorf("expected the managed fields to be dropped:\n%s", role)
+	}
+	if exists("cluster-scoped-resources/rbac.authorization.k8s.io/clusterroles/admin.yaml") {
+		t.Errorf("expected only the ClusterRoles of the operator to be gathered")
+	}
+	if secrets := read("namespaces/lws-system/core/secrets.yaml"); strings.Contains(secrets, "a2V5") || !strings.Contains(secrets, "tls.key: "+redactedValue) {
+		t.Errorf("expected the secret values to be redacted:\n%s", secrets)
+	}
+	for _, logs := range []string{"current.log", "previous.log"} {
+		read("namespaces/lws-system/pods/lws-controller-manager-1/manager/manager/logs/" + logs)
+	}
+	if events := read("namespaces/openshift-lws-operator/core/events.yaml"); !strings.Contains(events, "name: recent") || strings.Contains(events, "name: old") {
+		t.Errorf("expected only the recent events:\n%s", events)
+	}
+	for _, namespace := range []string{"team-a", "team-b"} {
+		read("namespaces/" + namespace + "/leaderworkerset.x-k8s.io/leaderworkersets.yaml")
+	}
+}
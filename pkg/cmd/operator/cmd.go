@@ -2,6 +2,9 @@ package operator
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"strconv"
 
 	"github.com/spf13/cobra"
 	"k8s.io/utils/clock"
@@ -11,12 +14,32 @@ import (
 	"github.com/openshift/lws-operator/pkg/version"
 )
 
+// createDefaultConfigEnv sets the default of --create-default-config, e.g. through the config.env of
+// an OLM Subscription.
+const createDefaultConfigEnv = "CREATE_DEFAULT_CONFIG"
+
 func NewOperator(ctx context.Context) *cobra.Command {
-	cmd := controllercmd.
-		NewControllerCommandConfig("openshift-lws-operator", version.Get(), operator.RunOperator, clock.RealClock{}).
+	options := operator.Options{CreateDefaultConfig: true}
+	envValue, envSet := os.LookupEnv(createDefaultConfigEnv)
+	var envErr error
+	if envSet {
+		options.CreateDefaultConfig, envErr = strconv.ParseBool(envValue)
+	}
+
+	var cmd *cobra.Command
+	cmd = controllercmd.
+		NewControllerCommandConfig("openshift-lws-operator", version.Get(), func(ctx context.Context, cc *controllercmd.ControllerContext) error {
+			if envErr != nil && !cmd.Flags().Changed("create-default-config") {
+				return fmt.Errorf("invalid %s %q: %w", createDefaultConfigEnv, envValue, envErr)
+			}
+			return operator.RunOperator(ctx, cc, options)
+		}, clock.RealClock{}).
+		WithTopologyDetector(operator.PlatformTopologyDetector{}).
 		NewCommandWithContext(ctx)
 	cmd.Use = "operator"
 	cmd.Short = "Start the Cluster LeaderWorkerSet Operator"
+	cmd.Flags().BoolVar(&options.CreateDefaultConfig, "create-default-config", options.CreateDefaultConfig,
+		fmt.Sprintf("create the cluster LeaderWorkerSetOperator with the default configuration if it does not exist, defaults to the %s environment variable", createDefaultConfigEnv))
 
 	return cmd
 }
@@ -0,0 +1,443 @@
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
+
+	"github.com/openshift/lws-operator/deploy"
+	leaderworkersetapiv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+	"github.com/openshift/lws-operator/pkg/operator"
+)
+
+// checkStatus is the outcome of a preflight check.
+type checkStatus string
+
+const (
+	statusPass checkStatus = "Pass"
+	// statusWarn reports a degraded but working installation, e.g. without monitoring.
+	statusWarn checkStatus = "Warn"
+	// statusFail reports a prerequisite without which the operator reports Degraded.
+	statusFail checkStatus = "Fail"
+)
+
+// checkResult is the machine-readable result of a check.
+type checkResult struct {
+	Name    string      `json:"name"`
+	Status  checkStatus `json:"status"`
+	Message string      `json:"message"`
+}
+
+// report is the machine-readable output of the preflight subcommand.
+type report struct {
+	// Passed is false when any check failed.
+	Passed bool          `json:"passed"`
+	Checks []checkResult `json:"checks"`
+}
+
+// operandGroups are the API groups served by the operand CRDs and webhooks.
+var operandGroups = sets.New("leaderworkerset.x-k8s.io", "disaggregatedset.x-k8s.io")
+
+// managedBySelector selects the objects applied by the operator.
+var managedBySelector = func() labels.Selector {
+	selector, err := labels.Parse(operator.ManagedBySelector)
+	if err != nil {
+		panic(err)
+	}
+	return selector
+}()
+
+// operatorServiceAccount is the ServiceAccount of the operator deployment.
+const operatorServiceAccount = "openshift-lws-operator"
+
+// requiredPermission is a permission the operator needs to reconcile the operand.
+type requiredPermission struct {
+	group, resource, name, verb string
+	// namespaced permissions are checked in the operator namespace.
+	namespaced bool
+}
+
+// requiredPermissions are the rules of deploy/04_00_clusterrole.yaml, checked cluster-wide, and of
+// deploy/03_00_role.yaml, checked in the operator namespace, with one permission per verb.
+var requiredPermissions = func() []requiredPermission {
+	permissions := rulePermissions(resourceread.ReadClusterRoleV1OrDie(deploy.OperatorClusterRole).Rules, false)
+	return append(permissions, rulePermissions(resourceread.ReadRoleV1OrDie(deploy.OperatorRole).Rules, true)...)
+}()
+
+func rulePermissions(rules []rbacv1.PolicyRule, namespaced bool) []requiredPermission {
+	var permissions []requiredPermission
+	for _, rule := range rules {
+		names := rule.ResourceNames
+		if len(names) == 0 {
+			names = []string{""}
+		}
+		for _, group := range rule.APIGroups {
+			for _, resource := range rule.Resources {
+				for _, name := range names {
+					for _, verb := range rule.Verbs {
+						permissions = append(permissions, requiredPermission{group: group, resource: resource, name: name, verb: verb, namespaced: namespaced})
+					}
+				}
+			}
+		}
+	}
+	return permissions
+}
+
+func (p requiredPermission) String() string {
+	resource := schema.GroupResource{Group: p.group, Resource: p.resource}.String()
+	if p.name != "" {
+		resource += "/" + p.name
+	}
+	return fmt.Sprintf("%s %s", p.verb, resource)
+}
+
+// operandNamespaceClusterRole is the ClusterRole the operator binds to itself in a dedicated operand
+// namespace, deploy/04_02_operand_namespace_clusterrole.yaml.
+var operandNamespaceClusterRole = resourceread.ReadClusterRoleV1OrDie(deploy.OperandNamespaceClusterRole).Name
+
+// preflight checks a cluster for the prerequisites of the operator, for the operator CR in the cluster
+// or the one about to be applied.
+type preflight struct {
+	kubeClient        kubernetes.Interface
+	dynamicClient     dynamic.Interface
+	discoveryClient   discovery.DiscoveryInterface
+	operatorNamespace string
+	operator          *leaderworkersetapiv1.LeaderWorkerSetOperator
+}
+
+func (p *preflight) run(ctx context.Context) report {
+	checks := []func(context.Context) checkResult{
+		p.checkCertManager,
+		p.checkMonitoring,
+		p.checkNodePlacement,
+		p.checkConflictingCRDs,
+		p.checkConflictingWebhooks,
+		p.checkRBAC,
+	}
+	r := report{Passed: true}
+	for _, check := range checks {
+		result := check(ctx)
+		if result.Status == statusFail {
+			r.Passed = false
+		}
+		r.Checks = append(r.Checks, result)
+	}
+	return r
+}
+
+func (p *preflight) operandNamespace() string {
+	if p.operator != nil && p.operator.Spec.OperandNamespace != "" {
+		return p.operator.Spec.OperandNamespace
+	}
+	return p.operatorNamespace
+}
+
+func (p *preflight) checkCertManager(context.Context) checkResult {
+	result := checkResult{Name: "CertManager"}
+	missing, err := p.missingKinds("cert-manager.io/v1", "Issuer", "Certificate")
+	switch {
+	case err != nil:
+		result.Status, result.Message = statusFail, err.Error()
+	case len(missing) > 0:
+		result.Status, result.Message = statusFail, fmt.Sprintf("cert-manager.io/v1 does not serve %s, install cert-manager, e.g. the cert-manager Operator for Red Hat OpenShift", strings.Join(missing, ", "))
+	default:
+		result.Status, result.Message = statusPass, "cert-manager Issuer and Certificate APIs are served"
+	}
+	return result
+}
+
+func (p *preflight) checkMonitoring(context.Context) checkResult {
+	result := checkResult{Name: "Monitoring"}
+	missing, err := p.missingKinds("monitoring.coreos.com/v1", "ServiceMonitor")
+	switch {
+	case err != nil:
+		result.Status, result.Message = statusFail, err.Error()
+	case len(missing) > 0:
+		result.Status, result.Message = statusWarn, "the Prometheus Operator ServiceMonitor API is not served, the operand metrics will not be scraped"
+	default:
+		result.Status, result.Message = statusPass, "the Prometheus Operator ServiceMonitor API is served"
+	}
+	return result
+}
+
+// missingKinds returns the kinds not served by a group version.
+func (p *preflight) missingKinds(groupVersion string, kinds ...string) ([]string, error) {
+	resources, err := p.discoveryClient.ServerResourcesForGroupVersion(groupVersion)
+	if apierrors.IsNotFound(err) {
+		return kinds, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to discover %s: %w", groupVersion, err)
+	}
+	served := sets.New[string]()
+	for _, resource := range resources.APIResources {
+		served.Insert(resource.Kind)
+	}
+	var missing []string
+	for _, kind := range kinds {
+		if !served.Has(kind) {
+			missing = append(missing, kind)
+		}
+	}
+	return missing, nil
+}
+
+// checkNodePlacement checks that the operand pods can be scheduled on at least one node. The
+// nodeSelector and tolerations of spec.nodePlacement replace the ones of the operand Deployment.
+func (p *preflight) checkNodePlacement(ctx context.Context) checkResult {
+	result := checkResult{Name: "NodePlacement"}
+	var nodeSelector map[string]string
+	var tolerations []corev1.Toleration
+	if p.operator != nil && p.operator.Spec.NodePlacement != nil {
+		nodeSelector = p.operator.Spec.NodePlacement.NodeSelector
+		tolerations = p.operator.Spec.NodePlacement.Tolerations
+	}
+
+	nodes, err := p.kubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		result.Status, result.Message = statusFail, fmt.Sprintf("unable to list nodes: %v", err)
+		return result
+	}
+	var matching []string
+	for i := range nodes.Items {
+		if schedulable(&nodes.Items[i], nodeSelector, tolerations) {
+			matching = append(matching, nodes.Items[i].Name)
+		}
+	}
+	if len(matching) == 0 {
+		result.Status, result.Message = statusFail, "no ready and schedulable node matches the nodeSelector and tolerates the taints of the operand pods, check spec.nodePlacement"
+		return result
+	}
+	sort.Strings(matching)
+	result.Status, result.Message = statusPass, fmt.Sprintf("%d nodes can run the operand pods, e.g. %s", len(matching), matching[0])
+	return result
+}
+
+func schedulable(node *corev1.Node, nodeSelector map[string]string, tolerations []corev1.Toleration) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+	ready := false
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			ready = condition.Status == corev1.ConditionTrue
+		}
+	}
+	if !ready {
+		return false
+	}
+	for key, value := range nodeSelector {
+		if actual, ok := node.Labels[key]; !ok || actual != value {
+			return false
+		}
+	}
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		tolerated := false
+		for j := range tolerations {
+			if tolerations[j].ToleratesTaint(klog.Background(), taint, false) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return false
+		}
+	}
+	return true
+}
+
+// checkConflictingCRDs finds CRDs of the operand groups that were not applied by the operator, e.g.
+// by an upstream LWS installation, which would be taken over or conflict with its field managers.
+func (p *preflight) checkConflictingCRDs(ctx context.Context) checkResult {
+	result := checkResult{Name: "ConflictingCRDs"}
+	manifestNames, err := operator.ManifestNames("CustomResourceDefinition")
+	if err != nil {
+		result.Status, result.Message = statusFail, err.Error()
+		return result
+	}
+	crds, err := p.dynamicClient.Resource(schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		result.Status, result.Message = statusFail, fmt.Sprintf("unable to list CRDs: %v", err)
+		return result
+	}
+	var conflicts []string
+	for _, crd := range crds.Items {
+		group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+		if operandGroups.Has(group) && !appliedByOperator(&crd, manifestNames) {
+			conflicts = append(conflicts, crd.GetName())
+		}
+	}
+	return conflictResult(result, "CRDs", conflicts)
+}
+
+// checkConflictingWebhooks finds webhook configurations intercepting the operand groups that were not
+// applied by the operator, e.g. left behind by an upstream LWS installation.
+func (p *preflight) checkConflictingWebhooks(ctx context.Context) checkResult {
+	result := checkResult{Name: "ConflictingWebhooks"}
+	var conflicts []string
+	for kind, resource := range map[string]string{
+		"MutatingWebhookConfiguration":   "mutatingwebhookconfigurations",
+		"ValidatingWebhookConfiguration": "validatingwebhookconfigurations",
+	} {
+		manifestNames, err := operator.ManifestNames(kind)
+		if err != nil {
+			result.Status, result.Message = statusFail, err.Error()
+			return result
+		}
+		webhookConfigurations, err := p.dynamicClient.Resource(schema.GroupVersionResource{Group: "admissionregistration.k8s.io", Version: "v1", Resource: resource}).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			result.Status, result.Message = statusFail, fmt.Sprintf("unable to list %s: %v", resource, err)
+			return result
+		}
+		for _, webhookConfiguration := range webhookConfigurations.Items {
+			if !appliedByOperator(&webhookConfiguration, manifestNames) && interceptsOperandGroups(&webhookConfiguration) {
+				conflicts = append(conflicts, resource+"/"+webhookConfiguration.GetName())
+			}
+		}
+	}
+	return conflictResult(result, "webhook configurations", conflicts)
+}
+
+// appliedByOperator matches the objects the operator applies. Releases before the managed-by label
+// applied them without it, with an owner reference to the operator CR or with the name of an embedded
+// manifest and the field manager of the operator. The name alone is not enough: an upstream LWS
+// installation uses the same names.
+func appliedByOperator(obj *unstructured.Unstructured, manifestNames []string) bool {
+	if managedBySelector.Matches(labels.Set(obj.GetLabels())) {
+		return true
+	}
+	for _, ownerReference := range obj.GetOwnerReferences() {
+		gv, err := schema.ParseGroupVersion(ownerReference.APIVersion)
+		if err == nil && gv.Group == leaderworkersetapiv1.SchemeGroupVersion.Group && ownerReference.Kind == "LeaderWorkerSetOperator" {
+			return true
+		}
+	}
+	if !slices.Contains(manifestNames, obj.GetName()) {
+		return false
+	}
+	for _, managedFields := range obj.GetManagedFields() {
+		if operator.IsOperatorFieldManager(managedFields.Manager) {
+			return true
+		}
+	}
+	return false
+}
+
+func interceptsOperandGroups(webhookConfiguration *unstructured.Unstructured) bool {
+	webhooks, _, _ := unstructured.NestedSlice(webhookConfiguration.Object, "webhooks")
+	for _, webhook := range webhooks {
+		rules, _, _ := unstructured.NestedSlice(webhook.(map[string]interface{}), "rules")
+		for _, rule := range rules {
+			groups, _, _ := unstructured.NestedStringSlice(rule.(map[string]interface{}), "apiGroups")
+			for _, group := range groups {
+				if operandGroups.Has(group) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func conflictResult(result checkResult, what string, conflicts []string) checkResult {
+	if len(conflicts) == 0 {
+		result.Status, result.Message = statusPass, fmt.Sprintf("no %s of the LeaderWorkerSet APIs are managed outside of the operator", what)
+		return result
+	}
+	sort.Strings(conflicts)
+	result.Status = statusFail
+	result.Message = fmt.Sprintf("%s of the LeaderWorkerSet APIs are not managed by the operator, uninstall the upstream LWS controller first: %s", what, strings.Join(conflicts, ", "))
+	return result
+}
+
+// checkRBAC asks the API server whether the operator ServiceAccount holds the permissions of its
+// ClusterRole and Role, and whether the ClusterRole it binds in a dedicated operand namespace exists.
+func (p *preflight) checkRBAC(ctx context.Context) checkResult {
+	result := checkResult{Name: "RBAC"}
+	_, err := p.kubeClient.CoreV1().ServiceAccounts(p.operatorNamespace).Get(ctx, operatorServiceAccount, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		result.Status, result.Message = statusFail, fmt.Sprintf("the operator ServiceAccount %s/%s does not exist, its permissions can only be checked once the operator is installed", p.operatorNamespace, operatorServiceAccount)
+		return result
+	}
+	if err != nil {
+		result.Status, result.Message = statusFail, fmt.Sprintf("unable to get the operator ServiceAccount: %v", err)
+		return result
+	}
+
+	user := fmt.Sprintf("system:serviceaccount:%s:%s", p.operatorNamespace, operatorServiceAccount)
+	var missing []string
+	for _, permission := range requiredPermissions {
+		resource, subresource, _ := strings.Cut(permission.resource, "/")
+		attributes := &authorizationv1.ResourceAttributes{
+			Group:       permission.group,
+			Resource:    resource,
+			Subresource: subresource,
+			Name:        permission.name,
+			Verb:        permission.verb,
+		}
+		if permission.namespaced {
+			attributes.Namespace = p.operatorNamespace
+		}
+		review, err := p.kubeClient.AuthorizationV1().SubjectAccessReviews().Create(ctx, &authorizationv1.SubjectAccessReview{
+			Spec: authorizationv1.SubjectAccessReviewSpec{
+				User:               user,
+				Groups:             []string{"system:serviceaccounts", "system:serviceaccounts:" + p.operatorNamespace},
+				ResourceAttributes: attributes,
+			},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			if apierrors.IsForbidden(err) {
+				result.Status, result.Message = statusWarn, "not allowed to create SubjectAccessReviews, the permissions of the operator were not checked"
+				return result
+			}
+			result.Status, result.Message = statusFail, fmt.Sprintf("unable to review the permissions of the operator: %v", err)
+			return result
+		}
+		if !review.Status.Allowed {
+			missing = append(missing, permission.String())
+		}
+	}
+
+	if operandNamespace := p.operandNamespace(); operandNamespace != p.operatorNamespace {
+		_, err := p.kubeClient.RbacV1().ClusterRoles().Get(ctx, operandNamespaceClusterRole, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			missing = append(missing, fmt.Sprintf("ClusterRole %s to bind in %s", operandNamespaceClusterRole, operandNamespace))
+		case apierrors.IsForbidden(err):
+			// the ClusterRole is only reported missing when it can be read
+		case err != nil:
+			result.Status, result.Message = statusFail, fmt.Sprintf("unable to get the ClusterRole %s: %v", operandNamespaceClusterRole, err)
+			return result
+		}
+	}
+
+	if len(missing) > 0 {
+		result.Status, result.Message = statusFail, fmt.Sprintf("%s is missing permissions: %s", user, strings.Join(missing, ", "))
+		return result
+	}
+	result.Status, result.Message = statusPass, fmt.Sprintf("%s holds the permissions of the operator", user)
+	return result
+}
@@ -0,0 +1,220 @@
+package preflight
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	leaderworkersetapiv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+	"github.com/openshift/lws-operator/pkg/operator"
+)
+
+func newNode(name string, labels map[string]string, taints ...corev1.Taint) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Spec:       corev1.NodeSpec{Taints: taints},
+		Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+			{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+		}},
+	}
+}
+
+func newCRD(name, group string, managed bool) *unstructured.Unstructured {
+	crd := &unstructured.Unstructured{}
+	crd.SetAPIVersion("apiextensions.k8s.io/v1")
+	crd.SetKind("CustomResourceDefinition")
+	crd.SetName(name)
+	if managed {
+		crd.SetLabels(map[string]string{"leaderworkerset.operator.openshift.io/managed-by": "lws-operator"})
+	}
+	_ = unstructured.SetNestedField(crd.Object, group, "spec", "group")
+	return crd
+}
+
+func newWebhookConfiguration(kind, name, group string) *unstructured.Unstructured {
+	webhookConfiguration := &unstructured.Unstructured{}
+	webhookConfiguration.SetAPIVersion("admissionregistration.k8s.io/v1")
+	webhookConfiguration.SetKind(kind)
+	webhookConfiguration.SetName(name)
+	_ = unstructured.SetNestedSlice(webhookConfiguration.Object, []interface{}{
+		map[string]interface{}{
+			"name":  "webhook." + group,
+			"rules": []interface{}{map[string]interface{}{"apiGroups": []interface{}{group}}},
+		},
+	}, "webhooks")
+	return webhookConfiguration
+}
+
+func allowAll(action clienttesting.Action) (bool, runtime.Object, error) {
+	review := action.(clienttesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+	review.Status.Allowed = true
+	return true, review, nil
+}
+
+func newPreflight(kubeClient *kubefake.Clientset, objects ...runtime.Object) *preflight {
+	listKinds := map[schema.GroupVersionResource]string{
+		{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}:               "CustomResourceDefinitionList",
+		{Group: "admissionregistration.k8s.io", Version: "v1", Resource: "mutatingwebhookconfigurations"}:   "MutatingWebhookConfigurationList",
+		{Group: "admissionregistration.k8s.io", Version: "v1", Resource: "validatingwebhookconfigurations"}: "ValidatingWebhookConfigurationList",
+	}
+	return &preflight{
+		kubeClient:        kubeClient,
+		dynamicClient:     dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, objects...),
+		discoveryClient:   kubeClient.Discovery(),
+		operatorNamespace: operator.DefaultOperatorNamespace,
+		operator:          &leaderworkersetapiv1.LeaderWorkerSetOperator{},
+	}
+}
+
+func resultOf(t *testing.T, r report, name string) checkResult {
+	t.Helper()
+	for _, result := range r.Checks {
+		if result.Name == name {
+			return result
+		}
+	}
+	t.Fatalf("no %s check in %v", name, r.Checks)
+	return checkResult{}
+}
+
+func TestPreflight(t *testing.T) {
+	kubeClient := kubefake.NewClientset(
+		newNode("worker-0", map[string]string{"node-role.kubernetes.io/worker": ""}),
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: operator.DefaultOperatorNamespace, Name: operatorServiceAccount}},
+	)
+	kubeClient.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{
+		{GroupVersion: "cert-manager.io/v1", APIResources: []metav1.APIResource{{Kind: "Issuer"}, {Kind: "Certificate"}}},
+		{GroupVersion: "monitoring.coreos.com/v1", APIResources: []metav1.APIResource{{Kind: "ServiceMonitor"}}},
+	}
+	kubeClient.PrependReactor("create", "subjectaccessreviews", allowAll)
+
+	// applied by previous releases without the managed-by label
+	ownedCRD := newCRD("disaggregatedsets.disaggregatedset.x-k8s.io", "disaggregatedset.x-k8s.io", false)
+	ownedCRD.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "operator.openshift.io/v1", Kind: "LeaderWorkerSetOperator", Name: "cluster", UID: "uid"}})
+	legacyWebhookConfiguration := newWebhookConfiguration("MutatingWebhookConfiguration", "lws-mutating-webhook-configuration", "leaderworkerset.x-k8s.io")
+	legacyWebhookConfiguration.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: "lws-operator", Operation: metav1.ManagedFieldsOperationUpdate}})
+
+	p := newPreflight(kubeClient,
+		newCRD("leaderworkersets.leaderworkerset.x-k8s.io", "leaderworkerset.x-k8s.io", true),
+		ownedCRD,
+		legacyWebhookConfiguration,
+		newWebhookConfiguration("ValidatingWebhookConfiguration", "cert-manager-webhook", "cert-manager.io"),
+	)
+
+	r := p.run(context.Background())
+	if !r.Passed {
+		t.Fatalf("expected the checks to pass: %v", r.Checks)
+	}
+	for _, result := range r.Checks {
+		if result.Status != statusPass {
+			t.Errorf("expected %s to pass: %s", result.Name, result.Message)
+		}
+	}
+}
+
+func TestPreflightFailures(t *testing.T) {
+	kubeClient := kubefake.NewClientset(
+		newNode("worker-0", map[string]string{"node-role.kubernetes.io/worker": ""}),
+		newNode("gpu-0", map[string]string{"node-role.kubernetes.io/infra": ""}, corev1.Taint{Key: "nvidia.com/gpu", Effect: corev1.TaintEffectNoSchedule}),
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: operator.DefaultOperatorNamespace, Name: operatorServiceAccount}},
+	)
+	kubeClient.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{
+		{GroupVersion: "cert-manager.io/v1", APIResources: []metav1.APIResource{{Kind: "Certificate"}}},
+	}
+	var leaseNamespaces []string
+	kubeClient.PrependReactor("create", "subjectaccessreviews", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		review := action.(clienttesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		attributes := review.Spec.ResourceAttributes
+		if attributes.Resource == "leases" && attributes.Verb == "update" {
+			leaseNamespaces = append(leaseNamespaces, attributes.Namespace)
+		}
+		review.Status.Allowed = attributes.Resource != "leases" || attributes.Verb != "update"
+		return true, review, nil
+	})
+	p := newPreflight(kubeClient,
+		newCRD("leaderworkersets.leaderworkerset.x-k8s.io", "leaderworkerset.x-k8s.io", false),
+		newWebhookConfiguration("MutatingWebhookConfiguration", "lws-mutating-webhook-configuration", "leaderworkerset.x-k8s.io"),
+	)
+	p.operator.Spec.OperandNamespace = "lws-operand"
+	p.operator.Spec.NodePlacement = &leaderworkersetapiv1.NodePlacement{NodeSelector: map[string]string{"node-role.kubernetes.io/infra": ""}}
+
+	r := p.run(context.Background())
+	if r.Passed {
+		t.Fatalf("expected the checks to fail")
+	}
+	for name, expected := range map[string]checkStatus{
+		"CertManager":         statusFail,
+		"Monitoring":          statusWarn,
+		"NodePlacement":       statusFail,
+		"ConflictingCRDs":     statusFail,
+		"ConflictingWebhooks": statusFail,
+		"RBAC":                statusFail,
+	} {
+		if result := resultOf(t, r, name); result.Status != expected {
+			t.Errorf("expected %s to be %s, got %s: %s", name, expected, result.Status, result.Message)
+		}
+	}
+	if message := resultOf(t, r, "CertManager").Message; !strings.Contains(message, "Issuer") || strings.Contains(message, "Certificate,") {
+		t.Errorf("expected only the Issuer API to be missing: %s", message)
+	}
+	if message := resultOf(t, r, "RBAC").Message; !strings.Contains(message, "update leases.coordination.k8s.io") {
+		t.Errorf("expected the missing lease permission to be reported: %s", message)
+	} else if !strings.Contains(message, "ClusterRole openshift-lws-operator-operand-namespace") {
+		t.Errorf("expected the missing operand namespace ClusterRole to be reported: %s", message)
+	}
+	// the operator holds the lease in its own namespace, whatever the operand namespace
+	if len(leaseNamespaces) == 0 {
+		t.Errorf("expected the lease permissions to be checked")
+	}
+	for _, namespace := range leaseNamespaces {
+		if namespace != operator.DefaultOperatorNamespace {
+			t.Errorf("expected the lease permissions to be checked in %s, got %q", operator.DefaultOperatorNamespace, namespace)
+		}
+	}
+
+	// the infra node is selected once its taint is tolerated
+	p.operator.Spec.NodePlacement.Tolerations = []corev1.Toleration{{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists}}
+	if result := p.checkNodePlacement(context.Background()); result.Status != statusPass || !strings.Contains(result.Message, "gpu-0") {
+		t.Errorf("expected gpu-0 to run the operand pods: %s", result.Message)
+	}
+}
+
+func TestRBACWithoutServiceAccount(t *testing.T) {
+	kubeClient := kubefake.NewClientset()
+	kubeClient.PrependReactor("create", "subjectaccessreviews", allowAll)
+	p := newPreflight(kubeClient)
+
+	if result := p.checkRBAC(context.Background()); result.Status != statusFail {
+		t.Errorf("expected the check to fail without the operator ServiceAccount, got %s: %s", result.Status, result.Message)
+	}
+}
+
+func TestRequiredPermissions(t *testing.T) {
+	permissions := sets.New[requiredPermission](requiredPermissions...)
+	for _, permission := range []requiredPermission{
+		{group: "", resource: "events", verb: "create"},
+		{group: "config.openshift.io", resource: "infrastructures", verb: "get"},
+		{group: "rbac.authorization.k8s.io", resource: "clusterroles", name: "openshift-lws-operator-operand-namespace", verb: "bind"},
+		{group: "", resource: "services", verb: "patch", namespaced: true},
+		{group: "", resource: "serviceaccounts", verb: "create", namespaced: true},
+		{group: "", resource: "configmaps", verb: "update", namespaced: true},
+		{group: "leaderworkerset.x-k8s.io", resource: "leaderworkersets", verb: "create", namespaced: true},
+		{group: "coordination.k8s.io", resource: "leases", verb: "update", namespaced: true},
+	} {
+		if !permissions.Has(permission) {
+			t.Errorf("expected %s to be required", permission)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package preflight
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	leaderworkersetapiv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+	"github.com/openshift/lws-operator/pkg/cmd/cmdutil"
+	operatorconfigclient "github.com/openshift/lws-operator/pkg/generated/clientset/versioned"
+	"github.com/openshift/lws-operator/pkg/operator"
+	"github.com/openshift/lws-operator/pkg/operator/operatorclient"
+)
+
+type preflightOptions struct {
+	kubeconfig        cmdutil.KubeconfigFlags
+	configFile        string
+	operatorNamespace string
+	output            string
+}
+
+func NewPreflight(ctx context.Context) *cobra.Command {
+	o := &preflightOptions{
+		operatorNamespace: operator.DefaultOperatorNamespace,
+		output:            "table",
+	}
+	cmd := &cobra.Command{
+		Use:   "preflight",
+		Short: "Check that a cluster meets the prerequisites of the LeaderWorkerSet Operator",
+		Long: `Check that a cluster meets the prerequisites of the LeaderWorkerSet Operator: the cert-manager and
+Prometheus Operator APIs, a node the operand pods can be scheduled on with spec.nodePlacement, CRDs and
+webhooks of the LeaderWorkerSet APIs installed outside of the operator, and the permissions of the
+operator ServiceAccount.
+
+The LeaderWorkerSetOperator of the cluster is checked, or the one in --config before it is applied. The
+command exits non-zero when a check fails.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.run(ctx, cmd.OutOrStdout())
+		},
+	}
+	o.kubeconfig.AddFlags(cmd.Flags())
+	cmd.Flags().StringVarP(&o.configFile, "config", "f", o.configFile, "LeaderWorkerSetOperator YAML file to check, the one of the cluster if empty")
+	cmd.Flags().StringVar(&o.operatorNamespace, "namespace", o.operatorNamespace, "namespace of the operator")
+	cmd.Flags().StringVarP(&o.output, "output", "o", o.output, "output format, table or json")
+	return cmd
+}
+
+func (o *preflightOptions) run(ctx context.Context, out io.Writer) error {
+	if o.output != "table" && o.output != "json" {
+		return fmt.Errorf("--output must be table or json, got %q", o.output)
+	}
+	config, err := o.kubeconfig.RESTConfig()
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	operatorConfigClient, err := operatorconfigclient.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	operatorConfig, err := cmdutil.ReadOperatorConfig(o.configFile)
+	if err != nil {
+		return err
+	}
+	if o.configFile == "" {
+		operatorConfig, err = clusterOperatorConfig(ctx, operatorConfigClient)
+		if err != nil {
+			return err
+		}
+	}
+
+	p := &preflight{
+		kubeClient:        kubeClient,
+		dynamicClient:     dynamicClient,
+		discoveryClient:   kubeClient.Discovery(),
+		operatorNamespace: o.operatorNamespace,
+		operator:          operatorConfig,
+	}
+	r := p.run(ctx)
+	if err := writeReport(out, o.output, r); err != nil {
+		return err
+	}
+	if !r.Passed {
+		return errors.New("preflight checks failed")
+	}
+	return nil
+}
+
+// clusterOperatorConfig returns the LeaderWorkerSetOperator of the cluster, or the default one before it
+// is created.
+func clusterOperatorConfig(ctx context.Context, client operatorconfigclient.Interface) (*leaderworkersetapiv1.LeaderWorkerSetOperator, error) {
+	config, err := client.OpenShiftOperatorV1().LeaderWorkerSetOperators().Get(ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+	// the CRD is not installed before the operator either
+	if apierrors.IsNotFound(err) {
+		return cmdutil.ReadOperatorConfig("")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the LeaderWorkerSetOperator: %w", err)
+	}
+	return config, nil
+}
+
+func writeReport(out io.Writer, format string, r report) error {
+	if format == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(r)
+	}
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "CHECK\tSTATUS\tMESSAGE")
+	for _, check := range r.Checks {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", check.Name, check.Status, check.Message)
+	}
+	return w.Flush()
+}
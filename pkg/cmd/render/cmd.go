@@ -0,0 +1,103 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/lws-operator/pkg/cmd/cmdutil"
+	"github.com/openshift/lws-operator/pkg/operator"
+)
+
+type renderOptions struct {
+	configFile   string
+	namespace    string
+	operandImage string
+	platform     string
+	monitoring   bool
+	outputDir    string
+}
+
+func NewRender() *cobra.Command {
+	o := &renderOptions{
+		namespace:    operator.DefaultOperatorNamespace,
+		operandImage: os.Getenv("RELATED_IMAGE_OPERAND_IMAGE"),
+		platform:     "OpenShift",
+		monitoring:   true,
+	}
+	cmd := &cobra.Command{
+		Use:   "render",
+		Short: "Render the operand manifests the operator applies for a LeaderWorkerSetOperator",
+		Long: `Render the operand manifests the operator applies for a LeaderWorkerSetOperator, without a cluster.
+
+The manifests are written in apply order, to stdout or to one file per manifest in --output-dir. The
+resource versions and content hashes the operator records in the operand pod template annotations are
+read from the cluster and are not rendered.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.run(cmd.OutOrStdout())
+		},
+	}
+	cmd.Flags().StringVarP(&o.configFile, "config", "f", o.configFile, "LeaderWorkerSetOperator YAML file, the default cluster configuration if empty")
+	cmd.Flags().StringVar(&o.namespace, "namespace", o.namespace, "namespace of the operator, the operand namespace unless spec.operandNamespace is set")
+	cmd.Flags().StringVar(&o.operandImage, "operand-image", o.operandImage, "operand image the operator is released with, defaults to RELATED_IMAGE_OPERAND_IMAGE")
+	cmd.Flags().StringVar(&o.platform, "platform", o.platform, "platform to render for, OpenShift or Kubernetes")
+	cmd.Flags().BoolVar(&o.monitoring, "monitoring", o.monitoring, "render the monitoring resources, which the operator only applies when the Prometheus Operator API is served")
+	cmd.Flags().StringVarP(&o.outputDir, "output-dir", "o", o.outputDir, "directory to write the manifests to, stdout if empty")
+	return cmd
+}
+
+func (o *renderOptions) run(out io.Writer) error {
+	if o.platform != "OpenShift" && o.platform != "Kubernetes" {
+		return fmt.Errorf("--platform must be OpenShift or Kubernetes, got %q", o.platform)
+	}
+	config, err := cmdutil.ReadOperatorConfig(o.configFile)
+	if err != nil {
+		return err
+	}
+
+	manifests, err := operator.RenderManifests(operator.RenderOptions{
+		Operator:            config,
+		OperatorNamespace:   o.namespace,
+		OperandImage:        o.operandImage,
+		Kubernetes:          o.platform == "Kubernetes",
+		MonitoringAvailable: o.monitoring,
+		Now:                 time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if o.outputDir != "" {
+		if err := os.MkdirAll(o.outputDir, 0o755); err != nil {
+			return err
+		}
+	}
+	for i, manifest := range manifests {
+		content, err := yaml.Marshal(manifest.Object.Object)
+		if err != nil {
+			return err
+		}
+		if o.outputDir == "" {
+			if _, err := fmt.Fprintf(out, "---\n# %s\n%s", manifest.Group, content); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(o.outputDir, manifestFileName(i, manifest)), content, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// manifestFileName keeps the apply order when the files are listed, e.g. 001_rbac_clusterrole_lws-manager-role.yaml.
+func manifestFileName(index int, manifest operator.RenderedManifest) string {
+	return strings.ToLower(fmt.Sprintf("%03d_%s_%s_%s.yaml", index, manifest.Group, manifest.Object.GetKind(), manifest.Object.GetName()))
+}
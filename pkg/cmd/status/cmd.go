@@ -0,0 +1,143 @@
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openshift/lws-operator/pkg/cmd/cmdutil"
+	operatorconfigclient "github.com/openshift/lws-operator/pkg/generated/clientset/versioned"
+	"github.com/openshift/lws-operator/pkg/operator"
+)
+
+type statusOptions struct {
+	kubeconfig        cmdutil.KubeconfigFlags
+	operatorNamespace string
+	output            string
+}
+
+func NewStatus(ctx context.Context) *cobra.Command {
+	o := &statusOptions{
+		operatorNamespace: operator.DefaultOperatorNamespace,
+		output:            "table",
+	}
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Summarize the health of the LeaderWorkerSet Operator and its operand",
+		Long: `Summarize the conditions of the LeaderWorkerSetOperator, the replicas and the leader of the operand,
+the expiry of its certificates, the CA bundles of its webhooks, the served and stored versions of its CRDs
+and the number of LeaderWorkerSets and DisaggregatedSets by phase.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.run(ctx, cmd.OutOrStdout())
+		},
+	}
+	o.kubeconfig.AddFlags(cmd.Flags())
+	cmd.Flags().StringVar(&o.operatorNamespace, "namespace", o.operatorNamespace, "namespace of the operator")
+	cmd.Flags().StringVarP(&o.output, "output", "o", o.output, "output format, table or json")
+	return cmd
+}
+
+func (o *statusOptions) run(ctx context.Context, out io.Writer) error {
+	if o.output != "table" && o.output != "json" {
+		return fmt.Errorf("--output must be table or json, got %q", o.output)
+	}
+	config, err := o.kubeconfig.RESTConfig()
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	operatorConfigClient, err := operatorconfigclient.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	c := &collector{
+		kubeClient:           kubeClient,
+		dynamicClient:        dynamicClient,
+		operatorConfigClient: operatorConfigClient,
+		operatorNamespace:    o.operatorNamespace,
+	}
+	s, err := c.collect(ctx)
+	if err != nil {
+		return err
+	}
+	if o.output == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(s)
+	}
+	return writeTable(out, s, time.Now())
+}
+
+func writeTable(out io.Writer, s *summary, now time.Time) error {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "Management state:\t%s\n", s.ManagementState)
+	fmt.Fprintf(w, "Operand namespace:\t%s\n", s.Operand.Namespace)
+	fmt.Fprintf(w, "Operand image:\t%s\n", s.Operand.Image)
+	fmt.Fprintf(w, "Operand replicas:\t%d desired, %d ready, %d updated, %d available\n", s.Operand.Replicas, s.Operand.ReadyReplicas, s.Operand.UpdatedReplicas, s.Operand.AvailableReplicas)
+	leader := s.Operand.Leader
+	if leader == "" {
+		leader = "<none>"
+	}
+	fmt.Fprintf(w, "Operand leader:\t%s\n", leader)
+
+	fmt.Fprintln(w, "\nCONDITION\tSTATUS\tREASON\tAGE\tMESSAGE")
+	for _, condition := range s.Conditions {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", condition.Type, condition.Status, condition.Reason, age(now, condition.LastTransitionTime), condition.Message)
+	}
+
+	fmt.Fprintln(w, "\nCERTIFICATE\tREADY\tEXPIRES\tRENEWS")
+	for _, certificate := range s.Certificates {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", certificate.Name, certificate.Ready, until(now, certificate.NotAfter), until(now, certificate.RenewalTime))
+	}
+
+	fmt.Fprintln(w, "\nWEBHOOK\tCONFIGURATION\tCA BUNDLE\tCA EXPIRES")
+	for _, webhook := range s.Webhooks {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", webhook.Webhook, webhook.Configuration, webhook.CABundle, until(now, webhook.NotAfter))
+	}
+
+	fmt.Fprintln(w, "\nCRD\tSERVED\tSTORAGE\tSTORED")
+	for _, crd := range s.CRDs {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", crd.Name, strings.Join(crd.ServedVersions, ","), crd.StorageVersion, strings.Join(crd.StoredVersions, ","))
+	}
+
+	fmt.Fprintln(w, "\nKIND\tPHASE\tCOUNT")
+	for _, workload := range s.Workloads {
+		fmt.Fprintf(w, "%s\t%s\t%d\n", workload.Kind, workload.Phase, workload.Count)
+	}
+	return w.Flush()
+}
+
+func age(now, t time.Time) string {
+	if t.IsZero() {
+		return "<unknown>"
+	}
+	return now.Sub(t).Round(time.Second).String()
+}
+
+func until(now time.Time, t *time.Time) string {
+	if t == nil {
+		return "<unknown>"
+	}
+	if t.Before(now) {
+		return fmt.Sprintf("%s (%s ago)", t.Format(time.RFC3339), now.Sub(*t).Round(time.Minute))
+	}
+	return fmt.Sprintf("%s (in %s)", t.Format(time.RFC3339), t.Sub(now).Round(time.Minute))
+}
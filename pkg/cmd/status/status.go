@@ -0,0 +1,352 @@
+package status
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	operatorconfigclient "github.com/openshift/lws-operator/pkg/generated/clientset/versioned"
+	"github.com/openshift/lws-operator/pkg/operator"
+	"github.com/openshift/lws-operator/pkg/operator/operatorclient"
+)
+
+var (
+	certificatesGVR                    = schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+	customResourceDefinitionsGVR       = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+	mutatingWebhookConfigurationsGVR   = schema.GroupVersionResource{Group: "admissionregistration.k8s.io", Version: "v1", Resource: "mutatingwebhookconfigurations"}
+	validatingWebhookConfigurationsGVR = schema.GroupVersionResource{Group: "admissionregistration.k8s.io", Version: "v1", Resource: "validatingwebhookconfigurations"}
+)
+
+// workloads are the operand APIs whose objects are counted by phase.
+var workloads = []struct {
+	kind string
+	gvr  schema.GroupVersionResource
+}{
+	{kind: "LeaderWorkerSet", gvr: schema.GroupVersionResource{Group: "leaderworkerset.x-k8s.io", Version: "v1", Resource: "leaderworkersets"}},
+	{kind: "DisaggregatedSet", gvr: schema.GroupVersionResource{Group: "disaggregatedset.x-k8s.io", Version: "v1", Resource: "disaggregatedsets"}},
+}
+
+// summary is the machine-readable output of the status subcommand.
+type summary struct {
+	ManagementState string              `json:"managementState"`
+	Conditions      []conditionStatus   `json:"conditions"`
+	Operand         operandStatus       `json:"operand"`
+	Certificates    []certificateStatus `json:"certificates"`
+	Webhooks        []webhookStatus     `json:"webhooks"`
+	CRDs            []crdStatus         `json:"crds"`
+	Workloads       []workloadCount     `json:"workloads"`
+}
+
+type conditionStatus struct {
+	Type               string    `json:"type"`
+	Status             string    `json:"status"`
+	Reason             string    `json:"reason,omitempty"`
+	Message            string    `json:"message,omitempty"`
+	LastTransitionTime time.Time `json:"lastTransitionTime"`
+}
+
+type operandStatus struct {
+	Namespace         string `json:"namespace"`
+	Image             string `json:"image,omitempty"`
+	Replicas          int32  `json:"replicas"`
+	ReadyReplicas     int32  `json:"readyReplicas"`
+	UpdatedReplicas   int32  `json:"updatedReplicas"`
+	AvailableReplicas int32  `json:"availableReplicas"`
+	// Leader is the operand pod holding the leader election Lease, empty without a leader.
+	Leader string `json:"leader,omitempty"`
+}
+
+type certificateStatus struct {
+	Name        string     `json:"name"`
+	Ready       string     `json:"ready"`
+	NotAfter    *time.Time `json:"notAfter,omitempty"`
+	RenewalTime *time.Time `json:"renewalTime,omitempty"`
+}
+
+// caBundle states of a webhook.
+const (
+	caBundleInjected = "Injected"
+	caBundleMissing  = "Missing"
+	caBundleInvalid  = "Invalid"
+	// caBundleStale is a bundle that does not contain the CA of the webhook serving certificate, e.g.
+	// until cainjector catches up with a rotation.
+	caBundleStale = "Stale"
+)
+
+type webhookStatus struct {
+	Configuration string     `json:"configuration"`
+	Webhook       string     `json:"webhook"`
+	CABundle      string     `json:"caBundle"`
+	NotAfter      *time.Time `json:"notAfter,omitempty"`
+}
+
+type crdStatus struct {
+	Name           string   `json:"name"`
+	ServedVersions []string `json:"servedVersions"`
+	StorageVersion string   `json:"storageVersion"`
+	StoredVersions []string `json:"storedVersions"`
+}
+
+type workloadCount struct {
+	Kind  string `json:"kind"`
+	Phase string `json:"phase"`
+	Count int    `json:"count"`
+}
+
+// phase summarizes the conditions of a LeaderWorkerSet or DisaggregatedSet, which have no phase field.
+func phase(obj *unstructured.Unstructured) string {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	status := map[string]string{}
+	for _, condition := range conditions {
+		c, _ := condition.(map[string]interface{})
+		conditionType, _, _ := unstructured.NestedString(c, "type")
+		conditionStatus, _, _ := unstructured.NestedString(c, "status")
+		status[conditionType] = conditionStatus
+	}
+	for _, conditionType := range []string{"UpdateInProgress", "Available", "Progressing"} {
+		if status[conditionType] == string(metav1.ConditionTrue) {
+			return conditionType
+		}
+	}
+	if len(status) == 0 {
+		return "Pending"
+	}
+	return "NotAvailable"
+}
+
+type collector struct {
+	kubeClient           kubernetes.Interface
+	dynamicClient        dynamic.Interface
+	operatorConfigClient operatorconfigclient.Interface
+	operatorNamespace    string
+}
+
+func (c *collector) collect(ctx context.Context) (*summary, error) {
+	operatorConfig, err := c.operatorConfigClient.OpenShiftOperatorV1().LeaderWorkerSetOperators().Get(ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the LeaderWorkerSetOperator: %w", err)
+	}
+	s := &summary{
+		ManagementState: string(operatorConfig.Spec.ManagementState),
+		Operand: operandStatus{
+			Namespace: operator.OperandNamespace(operatorConfig, c.operatorNamespace),
+			Image:     operatorConfig.Status.OperandImage,
+		},
+	}
+	for _, condition := range operatorConfig.Status.Conditions {
+		s.Conditions = append(s.Conditions, conditionStatus{
+			Type:               condition.Type,
+			Status:             string(condition.Status),
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+			LastTransitionTime: condition.LastTransitionTime.Time,
+		})
+	}
+	sort.Slice(s.Conditions, func(i, j int) bool { return s.Conditions[i].Type < s.Conditions[j].Type })
+
+	for _, collect := range []func(context.Context, *summary) error{
+		c.collectOperand,
+		c.collectCertificates,
+		c.collectWebhooks,
+		c.collectCRDs,
+		c.collectWorkloads,
+	} {
+		if err := collect(ctx, s); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func (c *collector) collectOperand(ctx context.Context, s *summary) error {
+	namespace := s.Operand.Namespace
+	deployment, err := c.kubeClient.AppsV1().Deployments(namespace).Get(ctx, operator.OperandDeploymentName, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return err
+	default:
+		if deployment.Spec.Replicas != nil {
+			s.Operand.Replicas = *deployment.Spec.Replicas
+		}
+		s.Operand.ReadyReplicas = deployment.Status.ReadyReplicas
+		s.Operand.UpdatedReplicas = deployment.Status.UpdatedReplicas
+		s.Operand.AvailableReplicas = deployment.Status.AvailableReplicas
+	}
+
+	// the leader election ID is part of the operand configuration, the Lease is found by its holder,
+	// <pod name>_<uuid>
+	leases, err := c.kubeClient.CoordinationV1().Leases(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, lease := range leases.Items {
+		if lease.Spec.HolderIdentity == nil || !strings.HasPrefix(*lease.Spec.HolderIdentity, operator.OperandDeploymentName+"-") {
+			continue
+		}
+		s.Operand.Leader, _, _ = strings.Cut(*lease.Spec.HolderIdentity, "_")
+	}
+	return nil
+}
+
+func (c *collector) collectCertificates(ctx context.Context, s *summary) error {
+	certificates, err := c.dynamicClient.Resource(certificatesGVR).Namespace(s.Operand.Namespace).List(ctx, metav1.ListOptions{LabelSelector: operator.ManagedBySelector})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, certificate := range certificates.Items {
+		status := certificateStatus{
+			Name:        certificate.GetName(),
+			Ready:       conditionOf(&certificate, "Ready"),
+			NotAfter:    timeOf(&certificate, "status", "notAfter"),
+			RenewalTime: timeOf(&certificate, "status", "renewalTime"),
+		}
+		s.Certificates = append(s.Certificates, status)
+	}
+	sort.Slice(s.Certificates, func(i, j int) bool { return s.Certificates[i].Name < s.Certificates[j].Name })
+	return nil
+}
+
+func (c *collector) collectWebhooks(ctx context.Context, s *summary) error {
+	// the webhook configurations are injected with the CA of the webhook serving certificate
+	var servingCA []byte
+	secret, err := c.kubeClient.CoreV1().Secrets(s.Operand.Namespace).Get(ctx, operator.WebhookCertificateSecretName, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return err
+	default:
+		servingCA = bytes.TrimSpace(secret.Data["ca.crt"])
+	}
+
+	for _, gvr := range []schema.GroupVersionResource{mutatingWebhookConfigurationsGVR, validatingWebhookConfigurationsGVR} {
+		configurations, err := c.dynamicClient.Resource(gvr).List(ctx, metav1.ListOptions{LabelSelector: operator.ManagedBySelector})
+		if err != nil {
+			return err
+		}
+		for _, configuration := range configurations.Items {
+			webhooks, _, _ := unstructured.NestedSlice(configuration.Object, "webhooks")
+			for _, webhook := range webhooks {
+				w, _ := webhook.(map[string]interface{})
+				name, _, _ := unstructured.NestedString(w, "name")
+				caBundle, _, _ := unstructured.NestedString(w, "clientConfig", "caBundle")
+				status := webhookStatus{Configuration: configuration.GetName(), Webhook: name}
+				status.CABundle, status.NotAfter = caBundleState(caBundle, servingCA)
+				s.Webhooks = append(s.Webhooks, status)
+			}
+		}
+	}
+	return nil
+}
+
+// caBundleState checks the base64 caBundle of a webhook, as read from an unstructured object.
+func caBundleState(caBundle string, servingCA []byte) (string, *time.Time) {
+	if caBundle == "" {
+		return caBundleMissing, nil
+	}
+	bundle, err := base64.StdEncoding.DecodeString(caBundle)
+	if err != nil {
+		return caBundleInvalid, nil
+	}
+	block, _ := pem.Decode(bundle)
+	if block == nil {
+		return caBundleInvalid, nil
+	}
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return caBundleInvalid, nil
+	}
+	notAfter := certificate.NotAfter
+	if len(servingCA) > 0 && !bytes.Contains(bundle, servingCA) {
+		return caBundleStale, &notAfter
+	}
+	return caBundleInjected, &notAfter
+}
+
+func (c *collector) collectCRDs(ctx context.Context, s *summary) error {
+	crds, err := c.dynamicClient.Resource(customResourceDefinitionsGVR).List(ctx, metav1.ListOptions{LabelSelector: operator.ManagedBySelector})
+	if err != nil {
+		return err
+	}
+	for _, crd := range crds.Items {
+		status := crdStatus{Name: crd.GetName()}
+		versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+		for _, version := range versions {
+			v, _ := version.(map[string]interface{})
+			name, _, _ := unstructured.NestedString(v, "name")
+			if served, _, _ := unstructured.NestedBool(v, "served"); served {
+				status.ServedVersions = append(status.ServedVersions, name)
+			}
+			if storage, _, _ := unstructured.NestedBool(v, "storage"); storage {
+				status.StorageVersion = name
+			}
+		}
+		status.StoredVersions, _, _ = unstructured.NestedStringSlice(crd.Object, "status", "storedVersions")
+		s.CRDs = append(s.CRDs, status)
+	}
+	sort.Slice(s.CRDs, func(i, j int) bool { return s.CRDs[i].Name < s.CRDs[j].Name })
+	return nil
+}
+
+func (c *collector) collectWorkloads(ctx context.Context, s *summary) error {
+	for _, workload := range workloads {
+		list, err := c.dynamicClient.Resource(workload.gvr).List(ctx, metav1.ListOptions{})
+		// the CRD is not installed yet
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		counts := map[string]int{}
+		for i := range list.Items {
+			counts[phase(&list.Items[i])]++
+		}
+		phases := make([]string, 0, len(counts))
+		for p := range counts {
+			phases = append(phases, p)
+		}
+		sort.Strings(phases)
+		for _, p := range phases {
+			s.Workloads = append(s.Workloads, workloadCount{Kind: workload.kind, Phase: p, Count: counts[p]})
+		}
+	}
+	return nil
+}
+
+func conditionOf(obj *unstructured.Unstructured, conditionType string) string {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, condition := range conditions {
+		c, _ := condition.(map[string]interface{})
+		if t, _, _ := unstructured.NestedString(c, "type"); t == conditionType {
+			status, _, _ := unstructured.NestedString(c, "status")
+			return status
+		}
+	}
+	return string(metav1.ConditionUnknown)
+}
+
+func timeOf(obj *unstructured.Unstructured, fields ...string) *time.Time {
+	value, _, _ := unstructured.NestedString(obj.Object, fields...)
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,224 @@
+package status
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	leaderworkersetapiv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+	operatorconfigfake "github.com/openshift/lws-operator/pkg/generated/clientset/versioned/fake"
+	"github.com/openshift/lws-operator/pkg/operator"
+)
+
+var managedLabels = map[string]string{"leaderworkerset.operator.openshift.io/managed-by": "lws-operator"}
+
+func newCA(t *testing.T, notAfter time.Time) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "lws-serving-cert"},
+		NotBefore:    notAfter.Add(-90 * 24 * time.Hour),
+		NotAfter:     notAfter,
+		IsCA:         true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func newUnstructured(apiVersion, kind, namespace, name string, labels map[string]string, fields map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: fields}
+	if obj.Object == nil {
+		obj.Object = map[string]interface{}{}
+	}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	obj.SetLabels(labels)
+	return obj
+}
+
+func newWorkload(kind, namespace, name string, conditions ...string) *unstructured.Unstructured {
+	var statusConditions []interface{}
+	for _, condition := range conditions {
+		conditionType, status, _ := strings.Cut(condition, "=")
+		statusConditions = append(statusConditions, map[string]interface{}{"type": conditionType, "status": status})
+	}
+	apiVersion := "leaderworkerset.x-k8s.io/v1"
+	if kind == "DisaggregatedSet" {
+		apiVersion = "disaggregatedset.x-k8s.io/v1"
+	}
+	return newUnstructured(apiVersion, kind, namespace, name, nil, map[string]interface{}{
+		"status": map[string]interface{}{"conditions": statusConditions},
+	})
+}
+
+func TestCollect(t *testing.T) {
+	now := time.Now()
+	notAfter := now.Add(60 * 24 * time.Hour).Truncate(time.Second).UTC()
+	servingCA := newCA(t, notAfter)
+	rotatedCA := newCA(t, notAfter.Add(time.Hour))
+
+	operatorConfig := &leaderworkersetapiv1.LeaderWorkerSetOperator{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec: leaderworkersetapiv1.LeaderWorkerSetOperatorSpec{
+			OperatorSpec: operatorv1.OperatorSpec{ManagementState: operatorv1.Managed},
+		},
+		Status: leaderworkersetapiv1.LeaderWorkerSetOperatorStatus{
+			OperatorStatus: operatorv1.OperatorStatus{Conditions: []operatorv1.OperatorCondition{
+				{Type: "Degraded", Status: operatorv1.ConditionFalse},
+				{Type: "Available", Status: operatorv1.ConditionTrue, Reason: "AsExpected"},
+			}},
+			OperandImage: "quay.io/example/lws:v1",
+		},
+	}
+	namespace := operator.DefaultOperatorNamespace
+	kubeClient := kubefake.NewClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: operator.OperandDeploymentName},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](2)},
+			Status:     appsv1.DeploymentStatus{ReadyReplicas: 1, UpdatedReplicas: 2, AvailableReplicas: 1},
+		},
+		&coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "b8b2488c.x-k8s.io"},
+			Spec:       coordinationv1.LeaseSpec{HolderIdentity: ptr.To("lws-controller-manager-7d9f-x2x4b_0b6e4a1c")},
+		},
+		&coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "lws-operator-lock"},
+			Spec:       coordinationv1.LeaseSpec{HolderIdentity: ptr.To("openshift-lws-operator-5c8b-q7v2d_7f1e")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: operator.WebhookCertificateSecretName},
+			Data:       map[string][]byte{"ca.crt": servingCA},
+		},
+	)
+
+	webhooks := func(caBundles ...[]byte) map[string]interface{} {
+		var items []interface{}
+		for i, caBundle := range caBundles {
+			clientConfig := map[string]interface{}{}
+			if caBundle != nil {
+				clientConfig["caBundle"] = base64.StdEncoding.EncodeToString(caBundle)
+			}
+			items = append(items, map[string]interface{}{"name": []string{"mleaderworkerset.x-k8s.io", "mpod.kb.io"}[i], "clientConfig": clientConfig})
+		}
+		return map[string]interface{}{"webhooks": items}
+	}
+	listKinds := map[schema.GroupVersionResource]string{
+		certificatesGVR:                    "CertificateList",
+		customResourceDefinitionsGVR:       "CustomResourceDefinitionList",
+		mutatingWebhookConfigurationsGVR:   "MutatingWebhookConfigurationList",
+		validatingWebhookConfigurationsGVR: "ValidatingWebhookConfigurationList",
+		workloads[0].gvr:                   "LeaderWorkerSetList",
+		workloads[1].gvr:                   "DisaggregatedSetList",
+	}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds,
+		newUnstructured("cert-manager.io/v1", "Certificate", namespace, "lws-serving-cert", managedLabels, map[string]interface{}{
+			"status": map[string]interface{}{
+				"notAfter":   notAfter.Format(time.RFC3339),
+				"conditions": []interface{}{map[string]interface{}{"type": "Ready", "status": "True"}},
+			},
+		}),
+		newUnstructured("admissionregistration.k8s.io/v1", "MutatingWebhookConfiguration", "", "lws-mutating-webhook-configuration", managedLabels, webhooks(servingCA, nil)),
+		newUnstructured("admissionregistration.k8s.io/v1", "ValidatingWebhookConfiguration", "", "lws-validating-webhook-configuration", managedLabels, webhooks(rotatedCA)),
+		newUnstructured("apiextensions.k8s.io/v1", "CustomResourceDefinition", "", "leaderworkersets.leaderworkerset.x-k8s.io", managedLabels, map[string]interface{}{
+			"spec": map[string]interface{}{"versions": []interface{}{
+				map[string]interface{}{"name": "v1", "served": true, "storage": true},
+			}},
+			"status": map[string]interface{}{"storedVersions": []interface{}{"v1"}},
+		}),
+		newWorkload("LeaderWorkerSet", "team-a", "vllm", "Available=True", "Progressing=False"),
+		newWorkload("LeaderWorkerSet", "team-a", "sglang", "Available=True"),
+		newWorkload("LeaderWorkerSet", "team-b", "vllm", "Available=False", "Progressing=True"),
+		newWorkload("DisaggregatedSet", "team-b", "llm-d"),
+	)
+
+	c := &collector{
+		kubeClient:           kubeClient,
+		dynamicClient:        dynamicClient,
+		operatorConfigClient: operatorconfigfake.NewClientset(operatorConfig),
+		operatorNamespace:    namespace,
+	}
+	s, err := c.collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Conditions[0].Type != "Available" || s.ManagementState != "Managed" {
+		t.Errorf("expected the sorted conditions and the management state, got %+v", s)
+	}
+	if s.Operand.Replicas != 2 || s.Operand.ReadyReplicas != 1 || s.Operand.Leader != "lws-controller-manager-7d9f-x2x4b" {
+		t.Errorf("unexpected operand status %+v", s.Operand)
+	}
+	if len(s.Certificates) != 1 || s.Certificates[0].Ready != "True" || !s.Certificates[0].NotAfter.Equal(notAfter) {
+		t.Errorf("unexpected certificates %+v", s.Certificates)
+	}
+	caBundles := map[string]string{}
+	for _, webhook := range s.Webhooks {
+		caBundles[webhook.Configuration+"/"+webhook.Webhook] = webhook.CABundle
+	}
+	for webhook, expected := range map[string]string{
+		"lws-mutating-webhook-configuration/mleaderworkerset.x-k8s.io":   caBundleInjected,
+		"lws-mutating-webhook-configuration/mpod.kb.io":                  caBundleMissing,
+		"lws-validating-webhook-configuration/mleaderworkerset.x-k8s.io": caBundleStale,
+	} {
+		if caBundles[webhook] != expected {
+			t.Errorf("expected the caBundle of %s to be %s, got %q", webhook, expected, caBundles[webhook])
+		}
+	}
+	if len(s.CRDs) != 1 || s.CRDs[0].StorageVersion != "v1" || strings.Join(s.CRDs[0].StoredVersions, ",") != "v1" {
+		t.Errorf("unexpected CRDs %+v", s.CRDs)
+	}
+	expectedWorkloads := []workloadCount{
+		{Kind: "LeaderWorkerSet", Phase: "Available", Count: 2},
+		{Kind: "LeaderWorkerSet", Phase: "Progressing", Count: 1},
+		{Kind: "DisaggregatedSet", Phase: "Pending", Count: 1},
+	}
+	if len(s.Workloads) != len(expectedWorkloads) {
+		t.Fatalf("expected %v, got %v", expectedWorkloads, s.Workloads)
+	}
+	for i := range expectedWorkloads {
+		if s.Workloads[i] != expectedWorkloads[i] {
+			t.Errorf("expected %v, got %v", expectedWorkloads, s.Workloads)
+		}
+	}
+
+	out := &bytes.Buffer{}
+	if err := writeTable(out, s, now); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"Operand leader:", "lws-controller-manager-7d9f-x2x4b", "Stale", "LeaderWorkerSet"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected %q in the table:\n%s", expected, out)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1
+
+// LeaderWorkerSetMetricsApplyConfiguration represents a declarative configuration of the LeaderWorkerSetMetrics type for use
+// with apply.
+//
+// LeaderWorkerSetMetrics limits the series of the lws_leaderworkerset_* metrics.
+type LeaderWorkerSetMetricsApplyConfiguration struct {
+	// maxLeaderWorkerSets is the maximum number of LeaderWorkerSets metrics are exposed for. When the
+	// cluster has more, the first ones by namespace and name are exposed and the others are counted in
+	// lws_leaderworkerset_metrics_dropped. 0 disables the metrics.
+	//
+	// If unset, defaults to 500.
+	MaxLeaderWorkerSets *int32 `json:"maxLeaderWorkerSets,omitempty"`
+	// maxGroupsPerLeaderWorkerSet is the maximum number of groups of a LeaderWorkerSet whose restarts
+	// are exposed, starting from group index 0. 0 disables lws_leaderworkerset_group_restarts and the
+	// watch of the LeaderWorkerSet pods it is computed from.
+	//
+	// If unset, defaults to 16.
+	MaxGroupsPerLeaderWorkerSet *int32 `json:"maxGroupsPerLeaderWorkerSet,omitempty"`
+}
+
+// LeaderWorkerSetMetricsApplyConfiguration constructs a declarative configuration of the LeaderWorkerSetMetrics type for use with
+// apply.
+func LeaderWorkerSetMetrics() *LeaderWorkerSetMetricsApplyConfiguration {
+	return &LeaderWorkerSetMetricsApplyConfiguration{}
+}
+
+// WithMaxLeaderWorkerSets sets the MaxLeaderWorkerSets field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the MaxLeaderWorkerSets field is set to the value of the last call.
+func (b *LeaderWorkerSetMetricsApplyConfiguration) WithMaxLeaderWorkerSets(value int32) *LeaderWorkerSetMetricsApplyConfiguration {
+	b.MaxLeaderWorkerSets = &value
+	return b
+}
+
+// WithMaxGroupsPerLeaderWorkerSet sets the MaxGroupsPerLeaderWorkerSet field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the MaxGroupsPerLeaderWorkerSet field is set to the value of the last call.
+func (b *LeaderWorkerSetMetricsApplyConfiguration) WithMaxGroupsPerLeaderWorkerSet(value int32) *LeaderWorkerSetMetricsApplyConfiguration {
+	b.MaxGroupsPerLeaderWorkerSet = &value
+	return b
+}
@@ -36,6 +36,36 @@ type LeaderWorkerSetOperatorSpecApplyConfiguration struct {
 	// operand deployment pod template. Omitted fields within nodePlacement leave the upstream
 	// operand manifest values unchanged.
 	NodePlacement *NodePlacementApplyConfiguration `json:"nodePlacement,omitempty"`
+	// operandNamespace is the namespace the lws-controller-manager and its namespaced resources are
+	// deployed to.
+	//
+	// If unset, the operand is deployed to the namespace the operator runs in.
+	//
+	// The operator creates the namespace if needed and labels it with the restricted pod security
+	// level. When the value changes, the operator restarts, deploys the operand to the new namespace
+	// and deletes the resources it created in the previous one. The previous namespace itself is kept.
+	OperandNamespace *string `json:"operandNamespace,omitempty"`
+	// operand configures the lws-controller-manager image and the policy it is validated against.
+	Operand *OperandSpecApplyConfiguration `json:"operand,omitempty"`
+	// operandLogging configures the format of the lws-controller-manager logs. The verbosity is
+	// configured by logLevel.
+	//
+	// If unset, the operand logs in its default format.
+	OperandLogging *OperandLoggingApplyConfiguration `json:"operandLogging,omitempty"`
+	// debug raises the verbosity of the lws-controller-manager, and optionally of the operator, until
+	// expiresAt. When the window ends the previous log levels are restored and a DebugWindowEnded
+	// event is recorded; the debug block can then be removed.
+	Debug *DebugSpecApplyConfiguration `json:"debug,omitempty"`
+	// overrides take individual operand objects out of management, e.g. to hotfix the validating
+	// webhook configuration, while the operator keeps reconciling everything else. Unmanaged objects
+	// are neither applied nor deleted by the operator. Active overrides are reported in the
+	// UnsupportedOverrides condition.
+	Overrides []ComponentOverrideApplyConfiguration `json:"overrides,omitempty"`
+	// leaderWorkerSetMetrics limits the cardinality of the lws_leaderworkerset_* metrics the operator
+	// exposes for every LeaderWorkerSet.
+	//
+	// If unset, the defaults of the fields apply.
+	LeaderWorkerSetMetrics *LeaderWorkerSetMetricsApplyConfiguration `json:"leaderWorkerSetMetrics,omitempty"`
 }
 
 // LeaderWorkerSetOperatorSpecApplyConfiguration constructs a declarative configuration of the LeaderWorkerSetOperatorSpec type for use with
@@ -91,3 +121,56 @@ func (b *LeaderWorkerSetOperatorSpecApplyConfiguration) WithNodePlacement(value
 	b.NodePlacement = value
 	return b
 }
+
+// WithOperandNamespace sets the OperandNamespace field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the OperandNamespace field is set to the value of the last call.
+func (b *LeaderWorkerSetOperatorSpecApplyConfiguration) WithOperandNamespace(value string) *LeaderWorkerSetOperatorSpecApplyConfiguration {
+	b.OperandNamespace = &value
+	return b
+}
+
+// WithOperand sets the Operand field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Operand field is set to the value of the last call.
+func (b *LeaderWorkerSetOperatorSpecApplyConfiguration) WithOperand(value *OperandSpecApplyConfiguration) *LeaderWorkerSetOperatorSpecApplyConfiguration {
+	b.Operand = value
+	return b
+}
+
+// WithOperandLogging sets the OperandLogging field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the OperandLogging field is set to the value of the last call.
+func (b *LeaderWorkerSetOperatorSpecApplyConfiguration) WithOperandLogging(value *OperandLoggingApplyConfiguration) *LeaderWorkerSetOperatorSpecApplyConfiguration {
+	b.OperandLogging = value
+	return b
+}
+
+// WithDebug sets the Debug field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Debug field is set to the value of the last call.
+func (b *LeaderWorkerSetOperatorSpecApplyConfiguration) WithDebug(value *DebugSpecApplyConfiguration) *LeaderWorkerSetOperatorSpecApplyConfiguration {
+	b.Debug = value
+	return b
+}
+
+// WithOverrides adds the given value to the Overrides field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Overrides field.
+func (b *LeaderWorkerSetOperatorSpecApplyConfiguration) WithOverrides(values ...*ComponentOverrideApplyConfiguration) *LeaderWorkerSetOperatorSpecApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithOverrides")
+		}
+		b.Overrides = append(b.Overrides, *values[i])
+	}
+	return b
+}
+
+// WithLeaderWorkerSetMetrics sets the LeaderWorkerSetMetrics field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LeaderWorkerSetMetrics field is set to the value of the last call.
+func (b *LeaderWorkerSetOperatorSpecApplyConfiguration) WithLeaderWorkerSetMetrics(value *LeaderWorkerSetMetricsApplyConfiguration) *LeaderWorkerSetOperatorSpecApplyConfiguration {
+	b.LeaderWorkerSetMetrics = value
+	return b
+}
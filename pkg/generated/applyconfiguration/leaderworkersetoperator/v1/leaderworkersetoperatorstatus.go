@@ -17,6 +17,7 @@ limitations under the License.
 package v1
 
 import (
+	configv1 "github.com/openshift/client-go/config/applyconfigurations/config/v1"
 	operatorv1 "github.com/openshift/client-go/operator/applyconfigurations/operator/v1"
 )
 
@@ -26,6 +27,22 @@ import (
 // LeaderWorkerSetOperatorStatus defines the observed state of LeaderWorkerSetOperator
 type LeaderWorkerSetOperatorStatusApplyConfiguration struct {
 	operatorv1.OperatorStatusApplyConfiguration `json:",inline"`
+	// operandImage is the image the lws-controller-manager Deployment was last applied with.
+	OperandImage *string `json:"operandImage,omitempty"`
+	// operandImageDigest is the digest of the image the lws-controller-manager runs, taken from
+	// operandImage or, for tag references, from the running pods.
+	OperandImageDigest *string `json:"operandImageDigest,omitempty"`
+	// operandNamespace is the namespace the operand objects were last applied to and garbage collected
+	// in. After spec.operandNamespace changes, the operator deletes the objects it left in this namespace
+	// and then updates it.
+	OperandNamespace *string `json:"operandNamespace,omitempty"`
+	// relatedObjects lists the objects the operator manages: the operator configuration, the operator and
+	// operand namespaces and every operand object that is not taken out of management by spec.overrides.
+	// It is used by oc adm inspect and support tooling to find the objects to collect.
+	RelatedObjects []configv1.ObjectReferenceApplyConfiguration `json:"relatedObjects,omitempty"`
+	// workloads summarizes the LeaderWorkerSets and DisaggregatedSets of all namespaces. It is updated
+	// at most every 30 seconds and is unset until the operand CRDs are served.
+	Workloads *WorkloadInventoryApplyConfiguration `json:"workloads,omitempty"`
 }
 
 // LeaderWorkerSetOperatorStatusApplyConfiguration constructs a declarative configuration of the LeaderWorkerSetOperatorStatus type for use with
@@ -91,3 +108,48 @@ func (b *LeaderWorkerSetOperatorStatusApplyConfiguration) WithGenerations(values
 	}
 	return b
 }
+
+// WithOperandImage sets the OperandImage field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the OperandImage field is set to the value of the last call.
+func (b *LeaderWorkerSetOperatorStatusApplyConfiguration) WithOperandImage(value string) *LeaderWorkerSetOperatorStatusApplyConfiguration {
+	b.OperandImage = &value
+	return b
+}
+
+// WithOperandImageDigest sets the OperandImageDigest field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the OperandImageDigest field is set to the value of the last call.
+func (b *LeaderWorkerSetOperatorStatusApplyConfiguration) WithOperandImageDigest(value string) *LeaderWorkerSetOperatorStatusApplyConfiguration {
+	b.OperandImageDigest = &value
+	return b
+}
+
+// WithOperandNamespace sets the OperandNamespace field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the OperandNamespace field is set to the value of the last call.
+func (b *LeaderWorkerSetOperatorStatusApplyConfiguration) WithOperandNamespace(value string) *LeaderWorkerSetOperatorStatusApplyConfiguration {
+	b.OperandNamespace = &value
+	return b
+}
+
+// WithRelatedObjects adds the given value to the RelatedObjects field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the RelatedObjects field.
+func (b *LeaderWorkerSetOperatorStatusApplyConfiguration) WithRelatedObjects(values ...*configv1.ObjectReferenceApplyConfiguration) *LeaderWorkerSetOperatorStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithRelatedObjects")
+		}
+		b.RelatedObjects = append(b.RelatedObjects, *values[i])
+	}
+	return b
+}
+
+// WithWorkloads sets the Workloads field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Workloads field is set to the value of the last call.
+func (b *LeaderWorkerSetOperatorStatusApplyConfiguration) WithWorkloads(value *WorkloadInventoryApplyConfiguration) *LeaderWorkerSetOperatorStatusApplyConfiguration {
+	b.Workloads = value
+	return b
+}
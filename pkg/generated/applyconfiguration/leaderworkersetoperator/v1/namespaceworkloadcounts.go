@@ -0,0 +1,60 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1
+
+// NamespaceWorkloadCountsApplyConfiguration represents a declarative configuration of the NamespaceWorkloadCounts type for use
+// with apply.
+//
+// NamespaceWorkloadCounts counts the workloads of a namespace.
+type NamespaceWorkloadCountsApplyConfiguration struct {
+	// namespace is the name of the namespace.
+	Namespace *string `json:"namespace,omitempty"`
+	// leaderWorkerSets is the number of LeaderWorkerSets in the namespace.
+	LeaderWorkerSets *int32 `json:"leaderWorkerSets,omitempty"`
+	// disaggregatedSets is the number of DisaggregatedSets in the namespace.
+	DisaggregatedSets *int32 `json:"disaggregatedSets,omitempty"`
+}
+
+// NamespaceWorkloadCountsApplyConfiguration constructs a declarative configuration of the NamespaceWorkloadCounts type for use with
+// apply.
+func NamespaceWorkloadCounts() *NamespaceWorkloadCountsApplyConfiguration {
+	return &NamespaceWorkloadCountsApplyConfiguration{}
+}
+
+// WithNamespace sets the Namespace field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Namespace field is set to the value of the last call.
+func (b *NamespaceWorkloadCountsApplyConfiguration) WithNamespace(value string) *NamespaceWorkloadCountsApplyConfiguration {
+	b.Namespace = &value
+	return b
+}
+
+// WithLeaderWorkerSets sets the LeaderWorkerSets field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LeaderWorkerSets field is set to the value of the last call.
+func (b *NamespaceWorkloadCountsApplyConfiguration) WithLeaderWorkerSets(value int32) *NamespaceWorkloadCountsApplyConfiguration {
+	b.LeaderWorkerSets = &value
+	return b
+}
+
+// WithDisaggregatedSets sets the DisaggregatedSets field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the DisaggregatedSets field is set to the value of the last call.
+func (b *NamespaceWorkloadCountsApplyConfiguration) WithDisaggregatedSets(value int32) *NamespaceWorkloadCountsApplyConfiguration {
+	b.DisaggregatedSets = &value
+	return b
+}
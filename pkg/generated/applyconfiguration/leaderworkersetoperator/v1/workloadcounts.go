@@ -0,0 +1,60 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1
+
+// WorkloadCountsApplyConfiguration represents a declarative configuration of the WorkloadCounts type for use
+// with apply.
+//
+// WorkloadCounts counts the workloads of a kind by health.
+type WorkloadCountsApplyConfiguration struct {
+	// total is the number of workloads.
+	Total *int32 `json:"total,omitempty"`
+	// notAvailable is the number of workloads whose Available condition is not True.
+	NotAvailable *int32 `json:"notAvailable,omitempty"`
+	// progressing is the number of workloads whose Progressing condition is True.
+	Progressing *int32 `json:"progressing,omitempty"`
+}
+
+// WorkloadCountsApplyConfiguration constructs a declarative configuration of the WorkloadCounts type for use with
+// apply.
+func WorkloadCounts() *WorkloadCountsApplyConfiguration {
+	return &WorkloadCountsApplyConfiguration{}
+}
+
+// WithTotal sets the Total field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Total field is set to the value of the last call.
+func (b *WorkloadCountsApplyConfiguration) WithTotal(value int32) *WorkloadCountsApplyConfiguration {
+	b.Total = &value
+	return b
+}
+
+// WithNotAvailable sets the NotAvailable field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the NotAvailable field is set to the value of the last call.
+func (b *WorkloadCountsApplyConfiguration) WithNotAvailable(value int32) *WorkloadCountsApplyConfiguration {
+	b.NotAvailable = &value
+	return b
+}
+
+// WithProgressing sets the Progressing field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Progressing field is set to the value of the last call.
+func (b *WorkloadCountsApplyConfiguration) WithProgressing(value int32) *WorkloadCountsApplyConfiguration {
+	b.Progressing = &value
+	return b
+}
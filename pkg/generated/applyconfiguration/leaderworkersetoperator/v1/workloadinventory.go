@@ -0,0 +1,85 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1
+
+// WorkloadInventoryApplyConfiguration represents a declarative configuration of the WorkloadInventory type for use
+// with apply.
+//
+// WorkloadInventory summarizes the workloads of the operand APIs.
+type WorkloadInventoryApplyConfiguration struct {
+	// leaderWorkerSets counts the LeaderWorkerSets, including the ones created for DisaggregatedSets.
+	LeaderWorkerSets *WorkloadCountsApplyConfiguration `json:"leaderWorkerSets,omitempty"`
+	// disaggregatedSets counts the DisaggregatedSets.
+	DisaggregatedSets *WorkloadCountsApplyConfiguration `json:"disaggregatedSets,omitempty"`
+	// groups is the number of replica groups of all LeaderWorkerSets.
+	Groups *int32 `json:"groups,omitempty"`
+	// pods is the number of pods of all LeaderWorkerSet groups, the leaders included.
+	Pods *int32 `json:"pods,omitempty"`
+	// namespaces counts the workloads by namespace, for the namespaces with the most workloads.
+	Namespaces []NamespaceWorkloadCountsApplyConfiguration `json:"namespaces,omitempty"`
+}
+
+// WorkloadInventoryApplyConfiguration constructs a declarative configuration of the WorkloadInventory type for use with
+// apply.
+func WorkloadInventory() *WorkloadInventoryApplyConfiguration {
+	return &WorkloadInventoryApplyConfiguration{}
+}
+
+// WithLeaderWorkerSets sets the LeaderWorkerSets field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LeaderWorkerSets field is set to the value of the last call.
+func (b *WorkloadInventoryApplyConfiguration) WithLeaderWorkerSets(value *WorkloadCountsApplyConfiguration) *WorkloadInventoryApplyConfiguration {
+	b.LeaderWorkerSets = value
+	return b
+}
+
+// WithDisaggregatedSets sets the DisaggregatedSets field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the DisaggregatedSets field is set to the value of the last call.
+func (b *WorkloadInventoryApplyConfiguration) WithDisaggregatedSets(value *WorkloadCountsApplyConfiguration) *WorkloadInventoryApplyConfiguration {
+	b.DisaggregatedSets = value
+	return b
+}
+
+// WithGroups sets the Groups field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Groups field is set to the value of the last call.
+func (b *WorkloadInventoryApplyConfiguration) WithGroups(value int32) *WorkloadInventoryApplyConfiguration {
+	b.Groups = &value
+	return b
+}
+
+// WithPods sets the Pods field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Pods field is set to the value of the last call.
+func (b *WorkloadInventoryApplyConfiguration) WithPods(value int32) *WorkloadInventoryApplyConfiguration {
+	b.Pods = &value
+	return b
+}
+
+// WithNamespaces adds the given value to the Namespaces field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Namespaces field.
+func (b *WorkloadInventoryApplyConfiguration) WithNamespaces(values ...*NamespaceWorkloadCountsApplyConfiguration) *WorkloadInventoryApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithNamespaces")
+		}
+		b.Namespaces = append(b.Namespaces, *values[i])
+	}
+	return b
+}
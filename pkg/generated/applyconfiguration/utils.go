@@ -30,14 +30,30 @@ import (
 func ForKind(kind schema.GroupVersionKind) interface{} {
 	switch kind {
 	// Group=operator.openshift.io, Version=v1
+	case v1.SchemeGroupVersion.WithKind("ComponentOverride"):
+		return &leaderworkersetoperatorv1.ComponentOverrideApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("DebugSpec"):
+		return &leaderworkersetoperatorv1.DebugSpecApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("LeaderWorkerSetMetrics"):
+		return &leaderworkersetoperatorv1.LeaderWorkerSetMetricsApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("LeaderWorkerSetOperator"):
 		return &leaderworkersetoperatorv1.LeaderWorkerSetOperatorApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("LeaderWorkerSetOperatorSpec"):
 		return &leaderworkersetoperatorv1.LeaderWorkerSetOperatorSpecApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("LeaderWorkerSetOperatorStatus"):
 		return &leaderworkersetoperatorv1.LeaderWorkerSetOperatorStatusApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("NamespaceWorkloadCounts"):
+		return &leaderworkersetoperatorv1.NamespaceWorkloadCountsApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("NodePlacement"):
 		return &leaderworkersetoperatorv1.NodePlacementApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("OperandLogging"):
+		return &leaderworkersetoperatorv1.OperandLoggingApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("OperandSpec"):
+		return &leaderworkersetoperatorv1.OperandSpecApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("WorkloadCounts"):
+		return &leaderworkersetoperatorv1.WorkloadCountsApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("WorkloadInventory"):
+		return &leaderworkersetoperatorv1.WorkloadInventoryApplyConfiguration{}
 
 	}
 	return nil
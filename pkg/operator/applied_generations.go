@@ -0,0 +1,170 @@
+package operator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/resource/resourcemerge"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+// builtinAPIGroups are always served, so the managed objects of their resources can be watched from
+// the start. The cert-manager and Prometheus Operator resources are only served once their CRDs are
+// installed and are applied on every sync.
+var builtinAPIGroups = sets.New("", "apps", "rbac.authorization.k8s.io", "admissionregistration.k8s.io", "apiextensions.k8s.io")
+
+// cachedManagedResources returns the resources of the embedded manifests whose objects are watched
+// through the managed-by label to skip the apply of unchanged objects.
+func cachedManagedResources(resources []staticResource) []schema.GroupVersionResource {
+	var gvrs []schema.GroupVersionResource
+	for _, resource := range resources {
+		gvr := resource.gvr()
+		if builtinAPIGroups.Has(gvr.Group) && !slices.Contains(gvrs, gvr) {
+			gvrs = append(gvrs, gvr)
+		}
+	}
+	return gvrs
+}
+
+// appliedGenerations records the generation and the content hash of the objects applied during a
+// sync. The previous records are read back from status.generations to skip the apply of objects that
+// did not change since.
+type appliedGenerations struct {
+	previous []operatorv1.GenerationStatus
+	current  []operatorv1.GenerationStatus
+}
+
+func newAppliedGenerations(previous []operatorv1.GenerationStatus) *appliedGenerations {
+	return &appliedGenerations{previous: previous}
+}
+
+// unchanged returns whether the desired object was applied by a previous sync with the same hash and
+// the cached object still has the recorded generation and all the desired fields. Most resources do
+// not bump their generation, and none bumps it on metadata changes, so the content check catches
+// the edits the generation misses.
+func (g *appliedGenerations) unchanged(gr schema.GroupResource, desired, cached *unstructured.Unstructured, hash string) bool {
+	if cached == nil {
+		return false
+	}
+	previous := resourcemerge.GenerationFor(g.previous, gr, desired.GetNamespace(), desired.GetName())
+	if previous == nil || previous.Hash != hash || previous.LastGeneration != cached.GetGeneration() {
+		return false
+	}
+	return containsFields(cached.Object, desired.Object)
+}
+
+// record adds the applied object with the hash of its desired content.
+func (g *appliedGenerations) record(gr schema.GroupResource, obj *unstructured.Unstructured, hash string) {
+	resourcemerge.SetGeneration(&g.current, operatorv1.GenerationStatus{
+		Group:          gr.Group,
+		Resource:       gr.Resource,
+		Namespace:      obj.GetNamespace(),
+		Name:           obj.GetName(),
+		LastGeneration: obj.GetGeneration(),
+		Hash:           hash,
+	})
+}
+
+// setGenerations replaces status.generations with the objects applied during the sync. Objects that
+// failed to apply, or were not applied because their resource group is blocked, are left out and are
+// applied unconditionally by the next sync.
+func (g *appliedGenerations) setGenerations() v1helpers.UpdateStatusFunc {
+	return func(status *operatorv1.OperatorStatus) error {
+		status.Generations = slices.Clone(g.current)
+		return nil
+	}
+}
+
+// contentHash returns the hash of an applied object, exposed in status.generations to tell which
+// content was last applied.
+func contentHash(obj *unstructured.Unstructured) (string, error) {
+	// maps are marshalled with sorted keys, so the hash is stable
+	content, err := json.Marshal(obj.Object)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// containsFields returns whether every desired field is set to the same value in the live object.
+// Lists must match element by element. Empty desired values match missing fields, as the API server
+// drops them, and numbers are compared by value as the caches decode them as int64 and float64.
+func containsFields(live, desired interface{}) bool {
+	switch desired := desired.(type) {
+	case map[string]interface{}:
+		liveMap, ok := live.(map[string]interface{})
+		if !ok {
+			return live == nil && len(desired) == 0
+		}
+		for key, value := range desired {
+			if !containsFields(liveMap[key], value) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		liveList, ok := live.([]interface{})
+		if !ok {
+			return live == nil && len(desired) == 0
+		}
+		if len(liveList) != len(desired) {
+			return false
+		}
+		for i := range desired {
+			if !containsFields(liveList[i], desired[i]) {
+				return false
+			}
+		}
+		return true
+	case nil:
+		return true
+	}
+	if desiredNumber, ok := toFloat(desired); ok {
+		liveNumber, ok := toFloat(live)
+		return ok && liveNumber == desiredNumber
+	}
+	return live == desired
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch value := value.(type) {
+	case int64:
+		return float64(value), true
+	case float64:
+		return value, true
+	}
+	return 0, false
+}
+
+// cachedObject returns a copy of the managed object from the informer cache, or nil when the
+// resource is not watched or the object is not cached, e.g. after its managed-by label was removed.
+func cachedObject(listers map[schema.GroupVersionResource]cache.GenericLister, gvr schema.GroupVersionResource, namespace, name string) *unstructured.Unstructured {
+	lister, ok := listers[gvr]
+	if !ok {
+		return nil
+	}
+	var obj interface{}
+	var err error
+	if namespace != "" {
+		obj, err = lister.ByNamespace(namespace).Get(name)
+	} else {
+		obj, err = lister.Get(name)
+	}
+	if err != nil {
+		return nil
+	}
+	cached, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil
+	}
+	return cached.DeepCopy()
+}
@@ -0,0 +1,122 @@
+package operator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clienttesting "k8s.io/client-go/testing"
+
+	"github.com/openshift/library-go/pkg/operator/resource/resourcemerge"
+
+	"github.com/openshift/lws-operator/pkg/operator/operatorclient"
+)
+
+// appliedObjects returns the resource and name of the server-side applies of the last sync.
+func appliedObjects(actions []clienttesting.Action) map[string]bool {
+	applied := map[string]bool{}
+	for _, action := range actions {
+		if patchAction, ok := action.(clienttesting.PatchAction); ok && patchAction.GetPatchType() == types.ApplyPatchType {
+			applied[action.GetResource().Resource+"/"+patchAction.GetName()] = true
+		}
+	}
+	return applied
+}
+
+func TestAppliedGenerations(t *testing.T) {
+	f := newTargetConfigReconcilerFixture(t)
+	f.converge(t)
+
+	operator, err := f.reconciler.leaderWorkerSetOperatorClient.OperatorClient.LeaderWorkerSetOperators().Get(context.Background(), operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []struct {
+		resource        schema.GroupResource
+		namespace, name string
+	}{
+		{schema.GroupResource{Resource: "namespaces"}, "", testNamespace},
+		{schema.GroupResource{Resource: "services"}, testNamespace, "lws-webhook-service"},
+		{schema.GroupResource{Group: "admissionregistration.k8s.io", Resource: "validatingwebhookconfigurations"}, "", "lws-validating-webhook-configuration"},
+		{schema.GroupResource{Group: "apiextensions.k8s.io", Resource: "customresourcedefinitions"}, "", "leaderworkersets.leaderworkerset.x-k8s.io"},
+		{schema.GroupResource{Group: "cert-manager.io", Resource: "certificates"}, testNamespace, "lws-serving-cert"},
+		{schema.GroupResource{Group: "apps", Resource: "deployments"}, testNamespace, operandName},
+	} {
+		generation := resourcemerge.GenerationFor(operator.Status.Generations, expected.resource, expected.namespace, expected.name)
+		if generation == nil || len(generation.Hash) != 64 {
+			t.Errorf("expected the hash of %s %s in status.generations, got %+v", expected.resource, expected.name, generation)
+		}
+	}
+
+	// the watched objects are not applied again, the cert-manager resources are
+	f.clearActions()
+	if err := f.reconciler.sync(context.Background(), f.syncCtx); err != nil {
+		t.Fatal(err)
+	}
+	applied := appliedObjects(f.actions())
+	for _, skipped := range []string{"services/lws-webhook-service", "clusterroles/lws-manager-role", "validatingwebhookconfigurations/lws-validating-webhook-configuration", "deployments/" + operandName} {
+		if applied[skipped] {
+			t.Errorf("expected unchanged %s not to be applied", skipped)
+		}
+	}
+	if !applied["certificates/lws-serving-cert"] {
+		t.Errorf("expected resources that are not watched to be applied, got %v", applied)
+	}
+
+	// removing a label is caught although the generation does not change
+	services := f.dynamicClient.Resource(schema.GroupVersionResource{Version: "v1", Resource: "services"}).Namespace(testNamespace)
+	service, err := services.Get(context.Background(), "lws-webhook-service", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	labels := service.GetLabels()
+	delete(labels, operatorVersionLabel)
+	service.SetLabels(labels)
+	if _, err := services.Update(context.Background(), service, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := wait.PollUntilContextTimeout(context.Background(), 10*time.Millisecond, 5*time.Second, true, func(context.Context) (bool, error) {
+		cached := cachedObject(f.reconciler.managedObjectListers, schema.GroupVersionResource{Version: "v1", Resource: "services"}, testNamespace, "lws-webhook-service")
+		if cached == nil {
+			return false, nil
+		}
+		_, found := cached.GetLabels()[operatorVersionLabel]
+		return !found, nil
+	}); err != nil {
+		t.Fatalf("service update not observed: %v", err)
+	}
+	f.clearActions()
+	if err := f.reconciler.sync(context.Background(), f.syncCtx); err != nil {
+		t.Fatal(err)
+	}
+	if !appliedObjects(f.actions())["services/lws-webhook-service"] {
+		t.Errorf("expected the edited service to be applied again")
+	}
+}
+
+func TestContainsFields(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		live, desired interface{}
+		expected      bool
+	}{
+		{"defaulted fields", map[string]interface{}{"a": "x", "b": "y"}, map[string]interface{}{"a": "x"}, true},
+		{"changed value", map[string]interface{}{"a": "y"}, map[string]interface{}{"a": "x"}, false},
+		{"missing field", map[string]interface{}{}, map[string]interface{}{"a": "x"}, false},
+		{"empty desired map", map[string]interface{}{}, map[string]interface{}{"a": map[string]interface{}{}}, true},
+		{"null desired value", map[string]interface{}{}, map[string]interface{}{"creationTimestamp": nil}, true},
+		{"numbers", map[string]interface{}{"port": float64(443)}, map[string]interface{}{"port": int64(443)}, true},
+		{"list elements", []interface{}{map[string]interface{}{"a": "x", "b": "y"}}, []interface{}{map[string]interface{}{"a": "x"}}, true},
+		{"list length", []interface{}{"x", "y"}, []interface{}{"x"}, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := containsFields(tc.live, tc.desired); actual != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, actual)
+			}
+		})
+	}
+}
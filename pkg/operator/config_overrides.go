@@ -0,0 +1,251 @@
+package operator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	jsonpatch "gopkg.in/evanphx/json-patch.v4"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+)
+
+const (
+	unsupportedConfigOverridesConditionType = "UnsupportedConfigOverridesDegraded"
+
+	jsonPatchType           = "JSONPatch"
+	strategicMergePatchType = "StrategicMerge"
+)
+
+// unsupportedConfigOverrides is the format of spec.unsupportedConfigOverrides: patches applied to the
+// rendered operand manifests after the operator's own mutations, in order.
+type unsupportedConfigOverrides struct {
+	Patches []manifestPatch `json:"patches"`
+}
+
+// manifestPatch customizes the rendered manifest of a single operand object.
+type manifestPatch struct {
+	Target manifestPatchTarget `json:"target"`
+	// Type is JSONPatch for RFC 6902 patches or StrategicMerge, which falls back to a JSON merge patch
+	// for kinds without a Go type, e.g. cert-manager Certificates.
+	Type  string          `json:"type"`
+	Patch json.RawMessage `json:"patch"`
+}
+
+type manifestPatchTarget struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+// configPatches are the parsed patches of the operator CR. Failures are recorded by patch index while
+// the manifests are rendered, so every failing patch is reported, and the objects they target are not
+// applied until the patch is fixed.
+type configPatches struct {
+	patches []manifestPatch
+	// err is set when spec.unsupportedConfigOverrides cannot be parsed at all.
+	err  error
+	errs map[int]error
+}
+
+// parseConfigPatches parses spec.unsupportedConfigOverrides. Unknown fields are rejected, so a typo
+// does not silently drop a customization.
+func parseConfigPatches(raw runtime.RawExtension) *configPatches {
+	p := &configPatches{errs: map[int]error{}}
+	content := bytes.TrimSpace(raw.Raw)
+	if len(content) == 0 || bytes.Equal(content, []byte("null")) {
+		return p
+	}
+
+	overrides := unsupportedConfigOverrides{}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&overrides); err != nil {
+		p.err = err
+		return p
+	}
+
+	p.patches = overrides.Patches
+	for i, patch := range p.patches {
+		if err := validateManifestPatch(patch); err != nil {
+			p.errs[i] = err
+		}
+	}
+	return p
+}
+
+func validateManifestPatch(patch manifestPatch) error {
+	if patch.Target.Kind == "" || patch.Target.Name == "" {
+		return fmt.Errorf("target.kind and target.name are required")
+	}
+	switch patch.Type {
+	case jsonPatchType:
+		if _, err := jsonpatch.DecodePatch(patch.Patch); err != nil {
+			return fmt.Errorf("invalid JSON patch: %w", err)
+		}
+	case strategicMergePatchType:
+		values := map[string]interface{}{}
+		if err := json.Unmarshal(patch.Patch, &values); err != nil {
+			return fmt.Errorf("a strategic merge patch must be an object: %w", err)
+		}
+	default:
+		return fmt.Errorf("type must be %s or %s, got %q", jsonPatchType, strategicMergePatchType, patch.Type)
+	}
+	return nil
+}
+
+// apply patches a rendered manifest with the patches targeting it, in order. No manifest is rendered
+// while spec.unsupportedConfigOverrides cannot be parsed, since the patches it targets are unknown.
+func (p *configPatches) apply(obj runtime.Object, kind, name string) (runtime.Object, error) {
+	if p == nil {
+		return obj, nil
+	}
+	if p.err != nil {
+		return nil, &degradedError{
+			reason: "InvalidUnsupportedConfigOverrides",
+			err:    fmt.Errorf("spec.unsupportedConfigOverrides: %w", p.err),
+		}
+	}
+	for i, patch := range p.patches {
+		if patch.Target.Kind != kind || patch.Target.Name != name {
+			continue
+		}
+		if err := p.errs[i]; err != nil {
+			return nil, patchError(i, err)
+		}
+		patched, err := applyManifestPatch(obj, patch)
+		if err != nil {
+			p.errs[i] = err
+			return nil, patchError(i, err)
+		}
+		obj = patched
+	}
+	return obj, nil
+}
+
+func patchError(index int, err error) error {
+	return &degradedError{
+		reason: "InvalidUnsupportedConfigOverrides",
+		err:    fmt.Errorf("spec.unsupportedConfigOverrides patches[%d]: %w", index, err),
+	}
+}
+
+func applyManifestPatch(obj runtime.Object, patch manifestPatch) (runtime.Object, error) {
+	original, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var patched []byte
+	_, isUnstructured := obj.(*unstructured.Unstructured)
+	switch {
+	case patch.Type == jsonPatchType:
+		decoded, err := jsonpatch.DecodePatch(patch.Patch)
+		if err != nil {
+			return nil, err
+		}
+		patched, err = decoded.Apply(original)
+		if err != nil {
+			return nil, err
+		}
+	case isUnstructured:
+		patched, err = jsonpatch.MergePatch(original, patch.Patch)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		patched, err = strategicpatch.StrategicMergePatch(original, patch.Patch, obj)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var out runtime.Object = &unstructured.Unstructured{}
+	if !isUnstructured {
+		out = reflect.New(reflect.TypeOf(obj).Elem()).Interface().(runtime.Object)
+	}
+	if err := json.Unmarshal(patched, out); err != nil {
+		return nil, fmt.Errorf("patched manifest is invalid: %w", err)
+	}
+
+	// the patch customizes the object, it must not turn it into another one
+	before, after := obj.(metav1.Object), out.(metav1.Object)
+	if out.GetObjectKind().GroupVersionKind() != obj.GetObjectKind().GroupVersionKind() ||
+		before.GetName() != after.GetName() || before.GetNamespace() != after.GetNamespace() {
+		return nil, fmt.Errorf("the patch must not change the apiVersion, kind, name or namespace of the object")
+	}
+	return out, nil
+}
+
+// dryRun renders the managed manifests to record the failing patches by index before any group is
+// applied, so the condition lists every failure even when a group stops early, e.g. while it waits for
+// the certificates. It is also used to validate the operator CR at admission time.
+func (p *configPatches) dryRun(resources []staticResource, rc *renderContext) {
+	p.checkTargets(resources)
+	for _, resource := range resources {
+		if rc.unmanaged(resource.gvk.GroupKind(), resource.renderedNamespace(rc), resource.name) {
+			continue
+		}
+		// failures of the mutators are reported by the resource groups
+		_, _ = resource.render(rc)
+	}
+}
+
+// checkTargets records a failure for every patch whose target is not one of the given manifests.
+func (p *configPatches) checkTargets(resources []staticResource) {
+	for i, patch := range p.patches {
+		if p.errs[i] != nil {
+			continue
+		}
+		found := false
+		for _, resource := range resources {
+			if resource.gvk.Kind == patch.Target.Kind && resource.name == patch.Target.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			p.errs[i] = fmt.Errorf("target %s %s is not an operand manifest", patch.Target.Kind, patch.Target.Name)
+		}
+	}
+}
+
+// failures returns the failures ordered by patch index.
+func (p *configPatches) failures() []string {
+	if p.err != nil {
+		return []string{fmt.Sprintf("spec.unsupportedConfigOverrides: %v", p.err)}
+	}
+	indexes := make([]int, 0, len(p.errs))
+	for i := range p.errs {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	failures := make([]string, 0, len(indexes))
+	for _, i := range indexes {
+		failures = append(failures, fmt.Sprintf("patches[%d]: %v", i, p.errs[i]))
+	}
+	return failures
+}
+
+// configPatchesCondition reports the patches that could not be applied.
+func configPatchesCondition(p *configPatches) operatorv1.OperatorCondition {
+	if failures := p.failures(); len(failures) > 0 {
+		return operatorv1.OperatorCondition{
+			Type:    unsupportedConfigOverridesConditionType,
+			Status:  operatorv1.ConditionTrue,
+			Reason:  "InvalidUnsupportedConfigOverrides",
+			Message: strings.Join(failures, "\n"),
+		}
+	}
+	return operatorv1.OperatorCondition{
+		Type:   unsupportedConfigOverridesConditionType,
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+}
@@ -0,0 +1,179 @@
+package operator
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/server/dynamiccertificates"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+
+	leaderworkersetapiv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+)
+
+const (
+	// configOverridesWebhookPath is the path of the validating webhook declared in the CSV.
+	configOverridesWebhookPath = "/validate-unsupported-config-overrides"
+	configOverridesWebhookPort = 9443
+	// webhookServingCertDir is where OLM mounts the serving certificate of the webhooks declared in the CSV.
+	webhookServingCertDir = "/tmp/k8s-webhook-server/serving-certs"
+
+	maxAdmissionReviewBytes = 3 * 1024 * 1024
+)
+
+// configOverridesValidator rejects operator CRs whose spec.unsupportedConfigOverrides cannot be applied,
+// by rendering the operand manifests the same way the target config reconciler does without applying them.
+type configOverridesValidator struct {
+	staticResources   []staticResource
+	operatorNamespace string
+	releaseImage      string
+	operatorVersion   string
+	platform          platform
+	clock             clock.PassiveClock
+}
+
+// validate dry-runs the patches of the operator CR and returns the failures by patch index.
+func (v *configOverridesValidator) validate(operator *leaderworkersetapiv1.LeaderWorkerSetOperator) []string {
+	patches := parseConfigPatches(operator.Spec.UnsupportedConfigOverrides)
+	if patches.err != nil || len(patches.patches) == 0 {
+		return patches.failures()
+	}
+
+	// an invalid operand image is reported by the reconciler, the patches are checked against the
+	// released image instead
+	image, err := resolveOperandImage(operator.Spec.Operand, v.releaseImage)
+	if err != nil {
+		image = v.releaseImage
+	}
+	rc := &renderContext{
+		namespace:       operandNamespace(operator, v.operatorNamespace),
+		targetImage:     image,
+		operatorVersion: v.operatorVersion,
+		operator:        operator,
+		ownerReference: metav1.OwnerReference{
+			APIVersion: "operator.openshift.io/v1",
+			Kind:       "LeaderWorkerSetOperator",
+			Name:       operator.Name,
+			UID:        operator.UID,
+		},
+		specAnnotations: make(map[string]string),
+		platform:        v.platform,
+		debug:           activeDebug(operator.Spec.Debug, v.clock.Now()),
+		configPatches:   patches,
+	}
+	patches.dryRun(v.staticResources, rc)
+	return patches.failures()
+}
+
+// review answers an AdmissionReview for the operator CR.
+func (v *configOverridesValidator) review(request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	response := &admissionv1.AdmissionResponse{UID: request.UID, Allowed: true}
+	if request.Operation != admissionv1.Create && request.Operation != admissionv1.Update {
+		return response
+	}
+
+	operator := &leaderworkersetapiv1.LeaderWorkerSetOperator{}
+	if err := json.Unmarshal(request.Object.Raw, operator); err != nil {
+		response.Allowed = false
+		response.Result = &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    http.StatusBadRequest,
+			Reason:  metav1.StatusReasonBadRequest,
+			Message: fmt.Sprintf("unable to decode the operator configuration: %v", err),
+		}
+		return response
+	}
+
+	if failures := v.validate(operator); len(failures) > 0 {
+		response.Allowed = false
+		response.Result = &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    http.StatusUnprocessableEntity,
+			Reason:  metav1.StatusReasonInvalid,
+			Message: "spec.unsupportedConfigOverrides cannot be applied: " + strings.Join(failures, "; "),
+		}
+	}
+	return response
+}
+
+func (v *configOverridesValidator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxAdmissionReviewBytes))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	review := &admissionv1.AdmissionReview{}
+	if err := json.Unmarshal(body, review); err != nil || review.Request == nil {
+		http.Error(w, "expected an admission.k8s.io/v1 AdmissionReview request", http.StatusBadRequest)
+		return
+	}
+
+	review.Response = v.review(review.Request)
+	review.Request = nil
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(review); err != nil {
+		klog.Errorf("Unable to write the admission review response: %v", err)
+	}
+}
+
+// runConfigOverridesWebhook serves the validating webhook of the operator CR until the context is done.
+// The serving certificate is reloaded when OLM rotates it.
+func runConfigOverridesWebhook(ctx context.Context, validator *configOverridesValidator, certDir string) error {
+	certFile, keyFile := certDir+"/tls.crt", certDir+"/tls.key"
+	servingCert, err := dynamiccertificates.NewDynamicServingContentFromFiles("config-overrides-webhook", certFile, keyFile)
+	if err != nil {
+		return err
+	}
+	go servingCert.Run(ctx, 1)
+
+	mux := http.NewServeMux()
+	mux.Handle(configOverridesWebhookPath, validator)
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", configOverridesWebhookPort),
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		TLSConfig: &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+				certPEM, keyPEM := servingCert.CurrentCertKeyContent()
+				cert, err := tls.X509KeyPair(certPEM, keyPEM)
+				if err != nil {
+					return nil, err
+				}
+				return &cert, nil
+			},
+		},
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	if err := server.ListenAndServeTLS("", ""); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// webhookServingCertExists reports whether OLM mounted a serving certificate. The webhook is not served
+// when the operator is deployed without OLM, e.g. from the deploy directory.
+func webhookServingCertExists(certDir string) bool {
+	_, err := os.Stat(certDir + "/tls.crt")
+	return err == nil
+}
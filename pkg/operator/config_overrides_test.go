@@ -0,0 +1,217 @@
+package operator
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	leaderworkersetoperatorv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+)
+
+func TestConfigPatches(t *testing.T) {
+	resources, err := loadStaticResources()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name             string
+		overrides        string
+		kind             string
+		resourceName     string
+		verify           func(t *testing.T, obj runtime.Object)
+		expectedFailures []string
+	}{
+		{
+			name:         "strategic merge patch of the operand container",
+			overrides:    `{"patches":[{"target":{"kind":"Deployment","name":"lws-controller-manager"},"type":"StrategicMerge","patch":{"spec":{"template":{"spec":{"containers":[{"name":"manager","env":[{"name":"GOMEMLIMIT","value":"400MiB"}]}]}}}}}]}`,
+			kind:         "Deployment",
+			resourceName: operandName,
+			verify: func(t *testing.T, obj runtime.Object) {
+				deployment := obj.(*appsv1.Deployment)
+				if deployment.Namespace != "lws-test" || len(deployment.OwnerReferences) != 1 {
+					t.Errorf("expected the patch to apply on top of the operator mutations, got %s %v", deployment.Namespace, deployment.OwnerReferences)
+				}
+				containers := deployment.Spec.Template.Spec.Containers
+				if len(containers) != 1 || containers[0].Image != "quay.io/example/lws:v1" {
+					t.Fatalf("expected the containers to be merged by name, got %+v", containers)
+				}
+				if !slices.Contains(containers[0].Env, corev1.EnvVar{Name: "GOMEMLIMIT", Value: "400MiB"}) {
+					t.Errorf("expected the patched env, got %v", containers[0].Env)
+				}
+			},
+		},
+		{
+			name:         "json patch of an operator-owned manifest",
+			overrides:    `{"patches":[{"target":{"kind":"ConfigMap","name":"lws-manager-config"},"type":"JSONPatch","patch":[{"op":"add","path":"/metadata/annotations","value":{"example.com/patched":"true"}}]}]}`,
+			kind:         "ConfigMap",
+			resourceName: "lws-manager-config",
+			verify: func(t *testing.T, obj runtime.Object) {
+				if obj.(*corev1.ConfigMap).Annotations["example.com/patched"] != "true" {
+					t.Errorf("expected the annotation to be added, got %v", obj.(*corev1.ConfigMap).Annotations)
+				}
+			},
+		},
+		{
+			name:         "merge patch of a kind without a go type",
+			overrides:    `{"patches":[{"target":{"kind":"Certificate","name":"lws-serving-cert"},"type":"StrategicMerge","patch":{"spec":{"duration":"720h"}}}]}`,
+			kind:         "Certificate",
+			resourceName: "lws-serving-cert",
+			verify: func(t *testing.T, obj runtime.Object) {
+				duration, _, _ := unstructured.NestedString(obj.(*unstructured.Unstructured).Object, "spec", "duration")
+				if duration != "720h" {
+					t.Errorf("expected the duration to be patched, got %q", duration)
+				}
+			},
+		},
+		{
+			name: "failures are reported by patch index",
+			overrides: `{"patches":[` +
+				`{"target":{"kind":"ConfigMap","name":"lws-manager-config"},"type":"JSONPatch","patch":[]},` +
+				`{"target":{"kind":"ConfigMap","name":"lws-manager-config"},"type":"JSONPatch","patch":[{"op":"replace","path":"/metadata/name","value":"other"}]},` +
+				`{"target":{"kind":"ConfigMap","name":"missing"},"type":"JSONPatch","patch":[]},` +
+				`{"target":{"kind":"ConfigMap","name":"lws-manager-config"},"type":"Kustomize","patch":{}}` +
+				`]}`,
+			kind:         "ConfigMap",
+			resourceName: "lws-manager-config",
+			expectedFailures: []string{
+				"patches[1]: the patch must not change the apiVersion, kind, name or namespace of the object",
+				"patches[2]: target ConfigMap missing is not an operand manifest",
+				`patches[3]: type must be JSONPatch or StrategicMerge, got "Kustomize"`,
+			},
+		},
+		{
+			name:             "unknown fields",
+			overrides:        `{"patch":[]}`,
+			kind:             "Deployment",
+			resourceName:     operandName,
+			expectedFailures: []string{`spec.unsupportedConfigOverrides: json: unknown field "patch"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rc := testRenderContext()
+			rc.configPatches = parseConfigPatches(runtime.RawExtension{Raw: []byte(tt.overrides)})
+			rc.configPatches.dryRun(resources, rc)
+
+			if failures := rc.configPatches.failures(); !slices.Equal(failures, tt.expectedFailures) {
+				t.Errorf("expected failures %q, got %q", tt.expectedFailures, failures)
+			}
+			if tt.kind == "" {
+				return
+			}
+
+			obj, err := findStaticResource(t, resources, tt.kind, tt.resourceName).render(rc)
+			if len(tt.expectedFailures) > 0 {
+				var degradedErr *degradedError
+				if !errors.As(err, &degradedErr) || degradedErr.reason != "InvalidUnsupportedConfigOverrides" {
+					t.Errorf("expected the target not to be rendered, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.verify(t, obj)
+		})
+	}
+}
+
+func TestConfigPatchesCondition(t *testing.T) {
+	f := newTargetConfigReconcilerFixture(t)
+	f.updateOperatorSpec(t, func(spec *leaderworkersetoperatorv1.LeaderWorkerSetOperatorSpec) {
+		spec.UnsupportedConfigOverrides = runtime.RawExtension{Raw: []byte(`{"patches":[{"target":{"kind":"Deployment","name":"lws-controller-manager"},"type":"JSONPatch","patch":[{"op":"remove","path":"/spec/paused"}]}]}`)}
+	})
+	if err := f.reconciler.sync(t.Context(), f.syncCtx); err == nil {
+		t.Fatal("expected the sync to fail")
+	}
+	condition := f.condition(t, unsupportedConfigOverridesConditionType)
+	if condition == nil || condition.Status != operatorv1.ConditionTrue || !strings.HasPrefix(condition.Message, "patches[0]: ") {
+		t.Errorf("expected the failing patch to be reported, got %+v", condition)
+	}
+	if condition := f.condition(t, "DeploymentDegraded"); condition == nil || condition.Reason != "InvalidUnsupportedConfigOverrides" {
+		t.Errorf("expected the Deployment group to be degraded, got %+v", condition)
+	}
+
+	f.updateOperatorSpec(t, func(spec *leaderworkersetoperatorv1.LeaderWorkerSetOperatorSpec) {
+		spec.UnsupportedConfigOverrides = runtime.RawExtension{}
+	})
+	f.converge(t)
+	if condition := f.condition(t, unsupportedConfigOverridesConditionType); condition == nil || condition.Status != operatorv1.ConditionFalse {
+		t.Errorf("expected %s=False, got %+v", unsupportedConfigOverridesConditionType, condition)
+	}
+}
+
+func TestConfigOverridesAdmission(t *testing.T) {
+	resources, err := loadStaticResources()
+	if err != nil {
+		t.Fatal(err)
+	}
+	validator := &configOverridesValidator{
+		staticResources:   resources,
+		operatorNamespace: operatorNamespace,
+		releaseImage:      "quay.io/example/lws:v1",
+		operatorVersion:   "v1.2.3",
+		platform:          platformOpenShift,
+		clock:             clocktesting.NewFakePassiveClock(time.Now()),
+	}
+
+	tests := []struct {
+		name            string
+		overrides       string
+		expectedAllowed bool
+		expectedMessage string
+	}{
+		{
+			name:            "no overrides",
+			expectedAllowed: true,
+		},
+		{
+			name:            "valid patch",
+			overrides:       `{"patches":[{"target":{"kind":"Service","name":"lws-webhook-service"},"type":"StrategicMerge","patch":{"metadata":{"labels":{"example.com/team":"ml"}}}}]}`,
+			expectedAllowed: true,
+		},
+		{
+			name:            "patch of a missing path",
+			overrides:       `{"patches":[{"target":{"kind":"Service","name":"lws-webhook-service"},"type":"StrategicMerge","patch":{}},{"target":{"kind":"Deployment","name":"lws-controller-manager"},"type":"JSONPatch","patch":[{"op":"replace","path":"/spec/missing/field","value":1}]}]}`,
+			expectedMessage: "spec.unsupportedConfigOverrides cannot be applied: patches[1]: ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operator := &leaderworkersetoperatorv1.LeaderWorkerSetOperator{}
+			operator.Name = "cluster"
+			if tt.overrides != "" {
+				operator.Spec.UnsupportedConfigOverrides.Raw = []byte(tt.overrides)
+			}
+			raw, err := json.Marshal(operator)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			response := validator.review(&admissionv1.AdmissionRequest{
+				UID:       "uid",
+				Operation: admissionv1.Update,
+				Object:    runtime.RawExtension{Raw: raw},
+			})
+			if response.UID != "uid" || response.Allowed != tt.expectedAllowed {
+				t.Fatalf("expected allowed=%v, got %+v", tt.expectedAllowed, response)
+			}
+			if tt.expectedMessage != "" && (response.Result == nil || !strings.HasPrefix(response.Result.Message, tt.expectedMessage)) {
+				t.Errorf("expected message %q, got %+v", tt.expectedMessage, response.Result)
+			}
+		})
+	}
+}
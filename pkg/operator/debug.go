@@ -0,0 +1,125 @@
+package operator
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/yaml"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	leaderworkersetoperatorv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+	"github.com/openshift/lws-operator/pkg/operator/operatorclient"
+)
+
+const (
+	debugActiveConditionType = "DebugActive"
+	// operandPprofAddress only listens on the loopback interface, the endpoints are reached with
+	// oc port-forward.
+	operandPprofAddress = "127.0.0.1:8082"
+)
+
+// activeDebug returns spec.debug while its window is open.
+func activeDebug(debug *leaderworkersetoperatorv1.DebugSpec, now time.Time) *leaderworkersetoperatorv1.DebugSpec {
+	if debug == nil || !now.Before(debug.ExpiresAt.Time) {
+		return nil
+	}
+	return debug
+}
+
+// operandLogLevel returns the log level of the operand, raised during a debug window.
+func operandLogLevel(rc *renderContext) operatorv1.LogLevel {
+	if rc.debug != nil {
+		return rc.debug.Level
+	}
+	return rc.operator.Spec.LogLevel
+}
+
+// manageDebug reports the debug window in the DebugActive condition and schedules a sync for its end,
+// which reverts the log levels. The start and end of a window are detected against the status the
+// condition is written to, and recordEvent records them once the status update succeeded, so neither
+// further syncs nor a restart of the operator repeat the events.
+func (c *TargetConfigReconciler) manageDebug(syncCtx factory.SyncContext, operator *leaderworkersetoperatorv1.LeaderWorkerSetOperator, debug *leaderworkersetoperatorv1.DebugSpec) (update operatorclient.UpdateStatusFunc, recordEvent func()) {
+	condition := operatorv1.OperatorCondition{
+		Type:   debugActiveConditionType,
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	var reason, message string
+	switch requested := operator.Spec.Debug; {
+	case debug != nil:
+		until := debug.ExpiresAt.UTC().Format(time.RFC3339)
+		condition.Status, condition.Reason, condition.Message = operatorv1.ConditionTrue, "DebugWindowOpen", fmt.Sprintf("Log level %s until %s", debug.Level, until)
+		reason, message = "DebugWindowStarted", fmt.Sprintf("Operand log level raised to %s until %s", debug.Level, until)
+		syncCtx.Queue().AddAfter(syncCtx.QueueKey(), debug.ExpiresAt.Sub(c.clock.Now()))
+	case requested != nil:
+		expired := requested.ExpiresAt.UTC().Format(time.RFC3339)
+		condition.Reason, condition.Message = "DebugWindowExpired", fmt.Sprintf("Debug window expired at %s, spec.debug can be removed", expired)
+		reason, message = "DebugWindowEnded", fmt.Sprintf("Debug window expired at %s, operand log level reverted to %s", expired, logLevelOrDefault(operator.Spec.LogLevel))
+	default:
+		reason, message = "DebugWindowEnded", fmt.Sprintf("Debug window removed, operand log level reverted to %s", logLevelOrDefault(operator.Spec.LogLevel))
+	}
+
+	transition := false
+	update = func(status *leaderworkersetoperatorv1.LeaderWorkerSetOperatorStatus) error {
+		wasActive := v1helpers.IsOperatorConditionTrue(status.Conditions, debugActiveConditionType)
+		transition = wasActive != (condition.Status == operatorv1.ConditionTrue)
+		v1helpers.SetOperatorCondition(&status.Conditions, condition)
+		return nil
+	}
+	recordEvent = func() {
+		if transition {
+			c.eventRecorder.Eventf(reason, "%s", message)
+		}
+	}
+	return update, recordEvent
+}
+
+func logLevelOrDefault(level operatorv1.LogLevel) operatorv1.LogLevel {
+	if level == "" {
+		return operatorv1.Normal
+	}
+	return level
+}
+
+// setOperandPprof enables the profiling endpoints in the operand configuration during a debug window
+// that requests them.
+func setOperandPprof(config []byte, debug *leaderworkersetoperatorv1.DebugSpec) ([]byte, error) {
+	if debug == nil || !debug.Pprof {
+		return config, nil
+	}
+	values := map[string]interface{}{}
+	if err := yaml.Unmarshal(config, &values); err != nil {
+		return nil, err
+	}
+	values["pprofBindAddress"] = operandPprofAddress
+	return yaml.Marshal(values)
+}
+
+// debugOperatorClient raises the operator log level reported to the library-go log level controller
+// while a debug window that includes the operator is open. The log level controller syncs on changes
+// of the operator CR, so the level is reverted by the status update at the end of the window.
+type debugOperatorClient struct {
+	*operatorclient.LeaderWorkerSetClient
+	clock clock.PassiveClock
+}
+
+func (c *debugOperatorClient) GetOperatorState() (*operatorv1.OperatorSpec, *operatorv1.OperatorStatus, string, error) {
+	spec, status, resourceVersion, err := c.LeaderWorkerSetClient.GetOperatorState()
+	if err != nil {
+		return nil, nil, "", err
+	}
+	operator, err := c.Lister.Get(operatorclient.OperatorConfigName)
+	if err != nil {
+		// the informer has not synced yet
+		return spec, status, resourceVersion, nil
+	}
+	if debug := activeDebug(operator.Spec.Debug, c.clock.Now()); debug != nil && debug.IncludeOperator {
+		spec = spec.DeepCopy()
+		spec.OperatorLogLevel = debug.Level
+	}
+	return spec, status, resourceVersion, nil
+}
@@ -0,0 +1,85 @@
+package operator
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	leaderworkersetoperatorv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+)
+
+func TestDebugWindow(t *testing.T) {
+	f := newTargetConfigReconcilerFixture(t)
+	ctx := context.Background()
+	operatorClient := &debugOperatorClient{LeaderWorkerSetClient: f.reconciler.leaderWorkerSetOperatorClient, clock: f.clock}
+	f.updateOperatorSpec(t, func(spec *leaderworkersetoperatorv1.LeaderWorkerSetOperatorSpec) {
+		spec.Debug = &leaderworkersetoperatorv1.DebugSpec{
+			Level:           operatorv1.TraceAll,
+			ExpiresAt:       metav1.NewTime(f.clock.Now().Add(time.Hour)),
+			IncludeOperator: true,
+			Pprof:           true,
+		}
+	})
+	f.converge(t)
+
+	if args := f.operandDeployment(t).Spec.Template.Spec.Containers[0].Args; !slices.Contains(args, "--zap-log-level=9") {
+		t.Errorf("expected the debug log level, got %v", args)
+	}
+	if config := f.operandConfig(t); !strings.Contains(config, "pprofBindAddress: "+operandPprofAddress) {
+		t.Errorf("expected pprof to be enabled, got %s", config)
+	}
+	if condition := f.condition(t, debugActiveConditionType); condition == nil || condition.Status != operatorv1.ConditionTrue {
+		t.Errorf("expected DebugActive, got %+v", condition)
+	}
+	if spec, _, _, err := operatorClient.GetOperatorState(); err != nil || spec.OperatorLogLevel != operatorv1.TraceAll {
+		t.Errorf("expected the operator log level to be raised, got %v, %v", spec, err)
+	}
+
+	f.clock.Step(2 * time.Hour)
+	f.converge(t)
+
+	if args := f.operandDeployment(t).Spec.Template.Spec.Containers[0].Args; !slices.Contains(args, "--zap-log-level=2") {
+		t.Errorf("expected the log level to be reverted, got %v", args)
+	}
+	if config := f.operandConfig(t); strings.Contains(config, "pprofBindAddress") {
+		t.Errorf("expected pprof to be disabled, got %s", config)
+	}
+	if condition := f.condition(t, debugActiveConditionType); condition == nil || condition.Status != operatorv1.ConditionFalse || condition.Reason != "DebugWindowExpired" {
+		t.Errorf("expected DebugActive=False with reason DebugWindowExpired, got %+v", condition)
+	}
+	if spec, _, _, err := operatorClient.GetOperatorState(); err != nil || spec.OperatorLogLevel != "" {
+		t.Errorf("expected the operator log level to be reverted, got %v, %v", spec, err)
+	}
+
+	// further syncs do not repeat the events
+	if err := f.reconciler.sync(ctx, f.syncCtx); err != nil {
+		t.Fatal(err)
+	}
+	var reasons []string
+	for _, event := range f.recorder.Events() {
+		if strings.HasPrefix(event.Reason, "DebugWindow") {
+			reasons = append(reasons, event.Reason)
+		}
+	}
+	if !slices.Equal(reasons, []string{"DebugWindowStarted", "DebugWindowEnded"}) {
+		t.Errorf("expected one start and one end event, got %v", reasons)
+	}
+}
+
+func (f *targetConfigReconcilerFixture) operandConfig(tb testing.TB) string {
+	tb.Helper()
+	obj, err := f.dynamicClient.Resource(schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}).Namespace(testNamespace).Get(context.Background(), "lws-manager-config", metav1.GetOptions{})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	data, _ := obj.Object["data"].(map[string]interface{})
+	config, _ := data["controller_manager_config.yaml"].(string)
+	return config
+}
@@ -0,0 +1,89 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	leaderworkersetapiv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+	operatorconfigclient "github.com/openshift/lws-operator/pkg/generated/clientset/versioned"
+	operatorclientinformers "github.com/openshift/lws-operator/pkg/generated/informers/externalversions/leaderworkersetoperator/v1"
+	leaderworkersetoperatorv1lister "github.com/openshift/lws-operator/pkg/generated/listers/leaderworkersetoperator/v1"
+	"github.com/openshift/lws-operator/pkg/operator/operatorclient"
+)
+
+// DefaultConfigController creates the cluster LeaderWorkerSetOperator with the defaults of the CSV
+// alm-examples when the operator starts without one, so installing the operator is enough to deploy
+// the operand. The CR is only created once per operator process: deleting it afterwards removes the
+// operand until the operator restarts, and GitOps-managed clusters disable the controller entirely.
+type DefaultConfigController struct {
+	operatorLister       leaderworkersetoperatorv1lister.LeaderWorkerSetOperatorLister
+	operatorConfigClient operatorconfigclient.Interface
+	eventRecorder        events.Recorder
+	// ensured is set once the CR has been found or created.
+	ensured bool
+}
+
+func NewDefaultConfigController(
+	operatorClientInformer operatorclientinformers.LeaderWorkerSetOperatorInformer,
+	operatorConfigClient operatorconfigclient.Interface,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &DefaultConfigController{
+		operatorLister:       operatorClientInformer.Lister(),
+		operatorConfigClient: operatorConfigClient,
+		eventRecorder:        eventRecorder,
+	}
+
+	return factory.New().
+		WithInformers(specChangesInformer{operatorClientInformer.Informer()}).
+		WithSync(c.sync).
+		ToController("DefaultConfigController", eventRecorder)
+}
+
+func (c *DefaultConfigController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	if c.ensured {
+		return nil
+	}
+	_, err := c.operatorLister.Get(operatorclient.OperatorConfigName)
+	if err == nil {
+		c.ensured = true
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	_, err = c.operatorConfigClient.OpenShiftOperatorV1().LeaderWorkerSetOperators().Create(ctx, defaultOperatorConfig(), metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		// created by someone else since the informer was synced
+		c.ensured = true
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to create operator configuration %s: %w", operatorclient.OperatorConfigName, err)
+	}
+	c.ensured = true
+	c.eventRecorder.Eventf("DefaultConfigCreated", "Created LeaderWorkerSetOperator %s with the default configuration", operatorclient.OperatorConfigName)
+	return nil
+}
+
+// defaultOperatorConfig returns the cluster LeaderWorkerSetOperator of the CSV alm-examples.
+func defaultOperatorConfig() *leaderworkersetapiv1.LeaderWorkerSetOperator {
+	return &leaderworkersetapiv1.LeaderWorkerSetOperator{
+		ObjectMeta: metav1.ObjectMeta{Name: operatorclient.OperatorConfigName},
+		Spec: leaderworkersetapiv1.LeaderWorkerSetOperatorSpec{
+			OperatorSpec: operatorv1.OperatorSpec{
+				ManagementState:  operatorv1.Managed,
+				LogLevel:         operatorv1.Normal,
+				OperatorLogLevel: operatorv1.Normal,
+			},
+		},
+	}
+}
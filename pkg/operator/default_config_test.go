@@ -0,0 +1,84 @@
+package operator
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/clock"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	leaderworkersetoperatorv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+	operatorconfigfake "github.com/openshift/lws-operator/pkg/generated/clientset/versioned/fake"
+	operatorclientinformers "github.com/openshift/lws-operator/pkg/generated/informers/externalversions"
+	"github.com/openshift/lws-operator/pkg/operator/operatorclient"
+)
+
+func newDefaultConfigController(t *testing.T, objects ...runtime.Object) (*DefaultConfigController, *operatorconfigfake.Clientset) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	client := operatorconfigfake.NewClientset(objects...)
+	informers := operatorclientinformers.NewSharedInformerFactory(client, 0)
+	informer := informers.OpenShiftOperator().V1().LeaderWorkerSetOperators()
+	c := &DefaultConfigController{
+		operatorLister:       informer.Lister(),
+		operatorConfigClient: client,
+		eventRecorder:        events.NewInMemoryRecorder("test", clock.RealClock{}),
+	}
+	informers.Start(ctx.Done())
+	informers.WaitForCacheSync(ctx.Done())
+	return c, client
+}
+
+func TestDefaultConfigController(t *testing.T) {
+	c, client := newDefaultConfigController(t)
+	syncCtx := factory.NewSyncContext("test", c.eventRecorder)
+	operators := client.OpenShiftOperatorV1().LeaderWorkerSetOperators()
+
+	if err := c.sync(context.Background(), syncCtx); err != nil {
+		t.Fatal(err)
+	}
+	operator, err := operators.Get(context.Background(), operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if operator.Spec.ManagementState != operatorv1.Managed || operator.Spec.LogLevel != operatorv1.Normal {
+		t.Errorf("expected a Managed CR with the Normal log level, got %+v", operator.Spec)
+	}
+
+	// deleting the CR removes the operand and is not reverted
+	if err := operators.Delete(context.Background(), operatorclient.OperatorConfigName, metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.sync(context.Background(), syncCtx); err != nil {
+		t.Fatal(err)
+	}
+	if list, err := operators.List(context.Background(), metav1.ListOptions{}); err != nil || len(list.Items) != 0 {
+		t.Errorf("expected the deleted CR not to be recreated, got %v, %v", list, err)
+	}
+}
+
+func TestDefaultConfigControllerKeepsExistingConfig(t *testing.T) {
+	existing := &leaderworkersetoperatorv1.LeaderWorkerSetOperator{
+		ObjectMeta: metav1.ObjectMeta{Name: operatorclient.OperatorConfigName},
+		Spec: leaderworkersetoperatorv1.LeaderWorkerSetOperatorSpec{
+			OperatorSpec: operatorv1.OperatorSpec{ManagementState: operatorv1.Removed},
+		},
+	}
+	c, client := newDefaultConfigController(t, existing)
+
+	if err := c.sync(context.Background(), factory.NewSyncContext("test", c.eventRecorder)); err != nil {
+		t.Fatal(err)
+	}
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "create" {
+			t.Errorf("expected the existing CR to be kept, got %v", action)
+		}
+	}
+}
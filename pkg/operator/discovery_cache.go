@@ -0,0 +1,56 @@
+package operator
+
+import (
+	apiextensionv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/tools/cache"
+)
+
+// newDiscoveryInvalidationHandler invalidates the cached discovery whenever a CRD changes the set of
+// resources served by the API server. Status-only updates, e.g. from the CRD controllers, are ignored.
+func newDiscoveryInvalidationHandler(discoveryClient discovery.CachedDiscoveryInterface) cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(interface{}) {
+			discoveryClient.Invalidate()
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldCRD, ok := oldObj.(*apiextensionv1.CustomResourceDefinition)
+			if !ok {
+				return
+			}
+			newCRD, ok := newObj.(*apiextensionv1.CustomResourceDefinition)
+			if !ok {
+				return
+			}
+			if crdServesResources(oldCRD) != crdServesResources(newCRD) ||
+				!equality.Semantic.DeepEqual(servedVersions(oldCRD), servedVersions(newCRD)) ||
+				!equality.Semantic.DeepEqual(oldCRD.Status.AcceptedNames, newCRD.Status.AcceptedNames) {
+				discoveryClient.Invalidate()
+			}
+		},
+		DeleteFunc: func(interface{}) {
+			discoveryClient.Invalidate()
+		},
+	}
+}
+
+// crdServesResources reports whether the API server serves the resources of the CRD.
+func crdServesResources(crd *apiextensionv1.CustomResourceDefinition) bool {
+	for _, condition := range crd.Status.Conditions {
+		if condition.Type == apiextensionv1.Established {
+			return condition.Status == apiextensionv1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func servedVersions(crd *apiextensionv1.CustomResourceDefinition) []string {
+	var versions []string
+	for _, version := range crd.Spec.Versions {
+		if version.Served {
+			versions = append(versions, version.Name)
+		}
+	}
+	return versions
+}
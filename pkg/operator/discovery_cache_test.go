@@ -0,0 +1,71 @@
+package operator
+
+import (
+	"testing"
+
+	apiextensionv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/client-go/discovery"
+)
+
+type countingDiscovery struct {
+	discovery.CachedDiscoveryInterface
+	invalidations int
+}
+
+func (d *countingDiscovery) Invalidate() {
+	d.invalidations++
+}
+
+func TestDiscoveryInvalidationHandler(t *testing.T) {
+	crd := &apiextensionv1.CustomResourceDefinition{
+		Spec: apiextensionv1.CustomResourceDefinitionSpec{
+			Versions: []apiextensionv1.CustomResourceDefinitionVersion{{Name: "v1", Served: true}},
+		},
+		Status: apiextensionv1.CustomResourceDefinitionStatus{
+			Conditions: []apiextensionv1.CustomResourceDefinitionCondition{{Type: apiextensionv1.Established, Status: apiextensionv1.ConditionTrue}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		mutate   func(crd *apiextensionv1.CustomResourceDefinition)
+		expected int
+	}{
+		{
+			name:     "status only update",
+			mutate:   func(crd *apiextensionv1.CustomResourceDefinition) { crd.Status.StoredVersions = []string{"v1"} },
+			expected: 0,
+		},
+		{
+			name: "new served version",
+			mutate: func(crd *apiextensionv1.CustomResourceDefinition) {
+				crd.Spec.Versions = append(crd.Spec.Versions, apiextensionv1.CustomResourceDefinitionVersion{Name: "v2", Served: true})
+			},
+			expected: 1,
+		},
+		{
+			name: "no longer established",
+			mutate: func(crd *apiextensionv1.CustomResourceDefinition) {
+				crd.Status.Conditions[0].Status = apiextensionv1.ConditionFalse
+			},
+			expected: 1,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			d := &countingDiscovery{}
+			updated := crd.DeepCopy()
+			tc.mutate(updated)
+			newDiscoveryInvalidationHandler(d).OnUpdate(crd, updated)
+			if d.invalidations != tc.expected {
+				t.Errorf("expected %d invalidations, got %d", tc.expected, d.invalidations)
+			}
+		})
+	}
+
+	d := &countingDiscovery{}
+	newDiscoveryInvalidationHandler(d).OnAdd(crd, false)
+	if d.invalidations != 1 {
+		t.Errorf("expected an added CRD to invalidate discovery, got %d invalidations", d.invalidations)
+	}
+}
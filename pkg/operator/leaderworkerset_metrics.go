@@ -0,0 +1,326 @@
+package operator
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1informer "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions/apiextensions/v1"
+	apiextensionsv1lister "k8s.io/apiextensions-apiserver/pkg/client/listers/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/component-base/metrics"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	leaderworkersetapiv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+	operatorclientinformers "github.com/openshift/lws-operator/pkg/generated/informers/externalversions/leaderworkersetoperator/v1"
+	leaderworkersetoperatorv1lister "github.com/openshift/lws-operator/pkg/generated/listers/leaderworkersetoperator/v1"
+	"github.com/openshift/lws-operator/pkg/operator/operatorclient"
+)
+
+const (
+	defaultMaxLeaderWorkerSetMetrics = 500
+	defaultMaxGroupMetrics           = 16
+
+	// set by the LWS controller on the leader and worker pods of every group
+	leaderWorkerSetNameLabel = "leaderworkerset.sigs.k8s.io/name"
+	groupIndexLabel          = "leaderworkerset.sigs.k8s.io/group-index"
+)
+
+// leaderWorkerSetConditions are the LeaderWorkerSet conditions exposed in lws_leaderworkerset_status_condition.
+var leaderWorkerSetConditions = []string{"Available", "Progressing", "UpdateInProgress"}
+
+// leaderWorkerSetMetricsSources are the caches and limits the collector reads on every scrape.
+type leaderWorkerSetMetricsSources struct {
+	leaderWorkerSets cache.GenericLister
+	// pods is nil while the group restarts are disabled.
+	pods                corev1listers.PodLister
+	maxLeaderWorkerSets int
+	maxGroups           int
+}
+
+// leaderWorkerSetCollector exposes the lws_leaderworkerset_* metrics from the informer caches of
+// LeaderWorkerSets and their pods. It reports nothing until LeaderWorkerSetMetricsController has set
+// its sources.
+type leaderWorkerSetCollector struct {
+	metrics.BaseStableCollector
+
+	lock    sync.RWMutex
+	sources *leaderWorkerSetMetricsSources
+
+	specReplicas          *metrics.Desc
+	specSize              *metrics.Desc
+	statusReplicas        *metrics.Desc
+	statusReadyReplicas   *metrics.Desc
+	statusUpdatedReplicas *metrics.Desc
+	statusCondition       *metrics.Desc
+	groupRestarts         *metrics.Desc
+	metricsDropped        *metrics.Desc
+}
+
+func newLeaderWorkerSetCollector() *leaderWorkerSetCollector {
+	labels := []string{"namespace", "name"}
+	desc := func(name, help string, labels ...string) *metrics.Desc {
+		return metrics.NewDesc("lws_leaderworkerset_"+name, help, labels, nil, metrics.ALPHA, "")
+	}
+	return &leaderWorkerSetCollector{
+		specReplicas:          desc("spec_replicas", "Number of desired groups of the LeaderWorkerSet.", labels...),
+		specSize:              desc("spec_size", "Number of pods of every group of the LeaderWorkerSet, the leader included.", labels...),
+		statusReplicas:        desc("status_replicas", "Number of groups created for the LeaderWorkerSet.", labels...),
+		statusReadyReplicas:   desc("status_ready_replicas", "Number of groups of the LeaderWorkerSet whose pods are all ready.", labels...),
+		statusUpdatedReplicas: desc("status_updated_replicas", "Number of groups of the LeaderWorkerSet updated to the latest revision.", labels...),
+		statusCondition:       desc("status_condition", "Whether the condition of the LeaderWorkerSet is True (1) or not (0), by condition.", append(labels, "condition")...),
+		groupRestarts:         desc("group_restarts", "Number of container restarts of the current pods of a LeaderWorkerSet group, by group index.", append(labels, "group")...),
+		metricsDropped:        desc("metrics_dropped", "Number of LeaderWorkerSets without metrics because of spec.leaderWorkerSetMetrics.maxLeaderWorkerSets."),
+	}
+}
+
+func (c *leaderWorkerSetCollector) setSources(sources *leaderWorkerSetMetricsSources) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.sources = sources
+}
+
+func (c *leaderWorkerSetCollector) DescribeWithStability(ch chan<- *metrics.Desc) {
+	for _, desc := range []*metrics.Desc{c.specReplicas, c.specSize, c.statusReplicas, c.statusReadyReplicas, c.statusUpdatedReplicas, c.statusCondition, c.groupRestarts, c.metricsDropped} {
+		ch <- desc
+	}
+}
+
+func (c *leaderWorkerSetCollector) CollectWithStability(ch chan<- metrics.Metric) {
+	c.lock.RLock()
+	sources := c.sources
+	c.lock.RUnlock()
+	if sources == nil {
+		return
+	}
+
+	list, err := sources.leaderWorkerSets.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Failed to list LeaderWorkerSets for metrics: %v", err)
+		return
+	}
+	var leaderWorkerSets []*unstructured.Unstructured
+	for _, obj := range list {
+		if u, ok := obj.(*unstructured.Unstructured); ok {
+			leaderWorkerSets = append(leaderWorkerSets, u)
+		}
+	}
+	// the limit keeps the same LeaderWorkerSets between scrapes
+	slices.SortFunc(leaderWorkerSets, func(a, b *unstructured.Unstructured) int {
+		if n := strings.Compare(a.GetNamespace(), b.GetNamespace()); n != 0 {
+			return n
+		}
+		return strings.Compare(a.GetName(), b.GetName())
+	})
+	exposed := leaderWorkerSets[:min(len(leaderWorkerSets), sources.maxLeaderWorkerSets)]
+	ch <- metrics.NewLazyConstMetric(c.metricsDropped, metrics.GaugeValue, float64(len(leaderWorkerSets)-len(exposed)))
+
+	var restarts map[groupKey]int64
+	if sources.pods != nil && sources.maxGroups > 0 {
+		restarts = groupRestarts(sources.pods, sources.maxGroups)
+	}
+	for _, lws := range exposed {
+		namespace, name := lws.GetNamespace(), lws.GetName()
+		gauge := func(desc *metrics.Desc, value int64, labelValues ...string) {
+			ch <- metrics.NewLazyConstMetric(desc, metrics.GaugeValue, float64(value), append([]string{namespace, name}, labelValues...)...)
+		}
+
+		replicas := nestedInt64(lws, 1, "spec", "replicas")
+		gauge(c.specReplicas, replicas)
+		gauge(c.specSize, nestedInt64(lws, 1, "spec", "leaderWorkerTemplate", "size"))
+		gauge(c.statusReplicas, nestedInt64(lws, 0, "status", "replicas"))
+		gauge(c.statusReadyReplicas, nestedInt64(lws, 0, "status", "readyReplicas"))
+		gauge(c.statusUpdatedReplicas, nestedInt64(lws, 0, "status", "updatedReplicas"))
+		for _, condition := range leaderWorkerSetConditions {
+			var status int64
+			if workloadCondition(lws, condition) == "True" {
+				status = 1
+			}
+			gauge(c.statusCondition, status, condition)
+		}
+		if restarts != nil {
+			for group := range min(int(replicas), sources.maxGroups) {
+				gauge(c.groupRestarts, restarts[groupKey{namespace, name, group}], strconv.Itoa(group))
+			}
+		}
+	}
+}
+
+func nestedInt64(obj *unstructured.Unstructured, defaultValue int64, fields ...string) int64 {
+	value, found, _ := unstructured.NestedInt64(obj.Object, fields...)
+	if !found {
+		return defaultValue
+	}
+	return value
+}
+
+// groupKey identifies a group of a LeaderWorkerSet.
+type groupKey struct {
+	namespace, name string
+	group           int
+}
+
+// groupRestarts sums the container restarts of the pods of every group below maxGroups.
+func groupRestarts(pods corev1listers.PodLister, maxGroups int) map[groupKey]int64 {
+	restarts := map[groupKey]int64{}
+	list, err := pods.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Failed to list LeaderWorkerSet pods for metrics: %v", err)
+		return restarts
+	}
+	for _, pod := range list {
+		group, err := strconv.Atoi(pod.Labels[groupIndexLabel])
+		if err != nil || group >= maxGroups {
+			continue
+		}
+		key := groupKey{pod.Namespace, pod.Labels[leaderWorkerSetNameLabel], group}
+		for _, status := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+			restarts[key] += int64(status.RestartCount)
+		}
+	}
+	return restarts
+}
+
+// trimLeaderWorkerSetPod keeps only the fields of the pods the group restarts are computed from, so
+// the pod cache of large clusters stays small.
+func trimLeaderWorkerSetPod(obj interface{}) (interface{}, error) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return obj, nil
+	}
+	trimmed := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            pod.Name,
+			Namespace:       pod.Namespace,
+			UID:             pod.UID,
+			ResourceVersion: pod.ResourceVersion,
+			Labels:          pod.Labels,
+		},
+	}
+	for _, status := range pod.Status.InitContainerStatuses {
+		trimmed.Status.InitContainerStatuses = append(trimmed.Status.InitContainerStatuses, corev1.ContainerStatus{Name: status.Name, RestartCount: status.RestartCount})
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		trimmed.Status.ContainerStatuses = append(trimmed.Status.ContainerStatuses, corev1.ContainerStatus{Name: status.Name, RestartCount: status.RestartCount})
+	}
+	return trimmed, nil
+}
+
+// LeaderWorkerSetMetricsController points the lws_leaderworkerset_* collector at the caches of the
+// LeaderWorkerSets and their pods, within the limits of spec.leaderWorkerSetMetrics. The LeaderWorkerSet
+// informer is shared with WorkloadInventoryController and, like there, only started once the CRD is
+// served. The pod informer is only started while the group restarts are enabled.
+type LeaderWorkerSetMetricsController struct {
+	operatorLister leaderworkersetoperatorv1lister.LeaderWorkerSetOperatorLister
+	crdLister      apiextensionsv1lister.CustomResourceDefinitionLister
+	informers      dynamicinformer.DynamicSharedInformerFactory
+	kubeClient     kubernetes.Interface
+	collector      *leaderWorkerSetCollector
+
+	leaderWorkerSets informers.GenericInformer
+	pods             cache.SharedIndexInformer
+	stopPods         context.CancelFunc
+}
+
+func NewLeaderWorkerSetMetricsController(
+	operatorClientInformer operatorclientinformers.LeaderWorkerSetOperatorInformer,
+	crdInformer apiextensionsv1informer.CustomResourceDefinitionInformer,
+	workloadInformers dynamicinformer.DynamicSharedInformerFactory,
+	kubeClient kubernetes.Interface,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &LeaderWorkerSetMetricsController{
+		operatorLister: operatorClientInformer.Lister(),
+		crdLister:      crdInformer.Lister(),
+		informers:      workloadInformers,
+		kubeClient:     kubeClient,
+		collector:      leaderWorkerSetMetrics,
+	}
+
+	return factory.New().
+		WithInformers(specChangesInformer{operatorClientInformer.Informer()}, crdInformer.Informer()).
+		ResyncEvery(time.Minute*5).
+		WithSync(c.sync).
+		ToController("LeaderWorkerSetMetricsController", eventRecorder)
+}
+
+func (c *LeaderWorkerSetMetricsController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	operator, err := c.operatorLister.Get(operatorclient.OperatorConfigName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	maxLeaderWorkerSets, maxGroups := leaderWorkerSetMetricsLimits(operator)
+
+	if c.leaderWorkerSets == nil {
+		if !workloadServed(c.crdLister, leaderWorkerSetWorkloads) {
+			c.collector.setSources(nil)
+			return nil
+		}
+		c.leaderWorkerSets = c.informers.ForResource(leaderWorkerSetWorkloads.gvr)
+		c.informers.Start(ctx.Done())
+	}
+	switch watchPods := maxLeaderWorkerSets > 0 && maxGroups > 0; {
+	case watchPods && c.pods == nil:
+		c.pods = coreinformers.NewFilteredPodInformer(c.kubeClient, metav1.NamespaceAll, 10*time.Minute, cache.Indexers{}, func(options *metav1.ListOptions) {
+			options.LabelSelector = leaderWorkerSetNameLabel
+		})
+		if err := c.pods.SetTransform(trimLeaderWorkerSetPod); err != nil {
+			return err
+		}
+		podsCtx, stopPods := context.WithCancel(ctx)
+		c.stopPods = stopPods
+		go c.pods.Run(podsCtx.Done())
+		klog.V(2).Infof("Watching LeaderWorkerSet pods for the group restart metrics")
+	case !watchPods && c.pods != nil:
+		c.stopPods()
+		c.pods, c.stopPods = nil, nil
+		klog.V(2).Infof("Stopped watching LeaderWorkerSet pods, the group restart metrics are disabled")
+	}
+	if !c.leaderWorkerSets.Informer().HasSynced() || (c.pods != nil && !c.pods.HasSynced()) {
+		syncCtx.Queue().AddAfter(syncCtx.QueueKey(), time.Second)
+		return nil
+	}
+
+	sources := &leaderWorkerSetMetricsSources{
+		leaderWorkerSets:    c.leaderWorkerSets.Lister(),
+		maxLeaderWorkerSets: maxLeaderWorkerSets,
+		maxGroups:           maxGroups,
+	}
+	if c.pods != nil {
+		sources.pods = corev1listers.NewPodLister(c.pods.GetIndexer())
+	}
+	c.collector.setSources(sources)
+	return nil
+}
+
+// leaderWorkerSetMetricsLimits returns the limits of spec.leaderWorkerSetMetrics with the defaults
+// applied.
+func leaderWorkerSetMetricsLimits(operator *leaderworkersetapiv1.LeaderWorkerSetOperator) (maxLeaderWorkerSets, maxGroups int) {
+	maxLeaderWorkerSets, maxGroups = defaultMaxLeaderWorkerSetMetrics, defaultMaxGroupMetrics
+	if operator == nil || operator.Spec.LeaderWorkerSetMetrics == nil {
+		return maxLeaderWorkerSets, maxGroups
+	}
+	if limit := operator.Spec.LeaderWorkerSetMetrics.MaxLeaderWorkerSets; limit != nil {
+		maxLeaderWorkerSets = int(*limit)
+	}
+	if limit := operator.Spec.LeaderWorkerSetMetrics.MaxGroupsPerLeaderWorkerSet; limit != nil {
+		maxGroups = int(*limit)
+	}
+	return maxLeaderWorkerSets, maxGroups
+}
@@ -0,0 +1,198 @@
+package operator
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	apiextensionsinformers "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/component-base/metrics/testutil"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/ptr"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	leaderworkersetoperatorv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+	operatorconfigfake "github.com/openshift/lws-operator/pkg/generated/clientset/versioned/fake"
+	operatorclientinformers "github.com/openshift/lws-operator/pkg/generated/informers/externalversions"
+	"github.com/openshift/lws-operator/pkg/operator/operatorclient"
+)
+
+func newLeaderWorkerSetPod(namespace, leaderWorkerSet, name, group string, restarts ...int32) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+			Labels:    map[string]string{leaderWorkerSetNameLabel: leaderWorkerSet, groupIndexLabel: group},
+		},
+	}
+	for _, restartCount := range restarts {
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{RestartCount: restartCount})
+	}
+	return pod
+}
+
+func TestLeaderWorkerSetCollector(t *testing.T) {
+	leaderWorkerSets := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, lws := range []runtime.Object{
+		newWorkload(leaderWorkerSetWorkloads, "team-a", "vllm",
+			map[string]interface{}{"replicas": int64(3), "leaderWorkerTemplate": map[string]interface{}{"size": int64(4)}},
+			map[string]interface{}{
+				"replicas":        int64(3),
+				"readyReplicas":   int64(2),
+				"updatedReplicas": int64(1),
+				"conditions":      workloadConditions("Available=True", "Progressing=True", "UpdateInProgress=True"),
+			}),
+		// dropped by the limit
+		newWorkload(leaderWorkerSetWorkloads, "team-b", "sglang", nil, nil),
+	} {
+		if err := leaderWorkerSets.Add(lws); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pods := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, pod := range []*corev1.Pod{
+		newLeaderWorkerSetPod("team-a", "vllm", "vllm-0", "0", 1),
+		newLeaderWorkerSetPod("team-a", "vllm", "vllm-0-1", "0", 2, 1),
+		newLeaderWorkerSetPod("team-a", "vllm", "vllm-1", "1"),
+		// beyond maxGroups
+		newLeaderWorkerSetPod("team-a", "vllm", "vllm-2", "2", 5),
+	} {
+		if err := pods.Add(pod); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	collector := newLeaderWorkerSetCollector()
+	collector.setSources(&leaderWorkerSetMetricsSources{
+		leaderWorkerSets:    cache.NewGenericLister(leaderWorkerSets, leaderWorkerSetGroupResource),
+		pods:                corev1listers.NewPodLister(pods),
+		maxLeaderWorkerSets: 1,
+		maxGroups:           2,
+	})
+
+	expected := `
+# HELP lws_leaderworkerset_group_restarts [ALPHA] Number of container restarts of the current pods of a LeaderWorkerSet group, by group index.
+# TYPE lws_leaderworkerset_group_restarts gauge
+lws_leaderworkerset_group_restarts{group="0",name="vllm",namespace="team-a"} 4
+lws_leaderworkerset_group_restarts{group="1",name="vllm",namespace="team-a"} 0
+# HELP lws_leaderworkerset_metrics_dropped [ALPHA] Number of LeaderWorkerSets without metrics because of spec.leaderWorkerSetMetrics.maxLeaderWorkerSets.
+# TYPE lws_leaderworkerset_metrics_dropped gauge
+lws_leaderworkerset_metrics_dropped 1
+# HELP lws_leaderworkerset_spec_replicas [ALPHA] Number of desired groups of the LeaderWorkerSet.
+# TYPE lws_leaderworkerset_spec_replicas gauge
+lws_leaderworkerset_spec_replicas{name="vllm",namespace="team-a"} 3
+# HELP lws_leaderworkerset_spec_size [ALPHA] Number of pods of every group of the LeaderWorkerSet, the leader included.
+# TYPE lws_leaderworkerset_spec_size gauge
+lws_leaderworkerset_spec_size{name="vllm",namespace="team-a"} 4
+# HELP lws_leaderworkerset_status_condition [ALPHA] Whether the condition of the LeaderWorkerSet is True (1) or not (0), by condition.
+# TYPE lws_leaderworkerset_status_condition gauge
+lws_leaderworkerset_status_condition{condition="Available",name="vllm",namespace="team-a"} 1
+lws_leaderworkerset_status_condition{condition="Progressing",name="vllm",namespace="team-a"} 1
+lws_leaderworkerset_status_condition{condition="UpdateInProgress",name="vllm",namespace="team-a"} 1
+# HELP lws_leaderworkerset_status_ready_replicas [ALPHA] Number of groups of the LeaderWorkerSet whose pods are all ready.
+# TYPE lws_leaderworkerset_status_ready_replicas gauge
+lws_leaderworkerset_status_ready_replicas{name="vllm",namespace="team-a"} 2
+# HELP lws_leaderworkerset_status_replicas [ALPHA] Number of groups created for the LeaderWorkerSet.
+# TYPE lws_leaderworkerset_status_replicas gauge
+lws_leaderworkerset_status_replicas{name="vllm",namespace="team-a"} 3
+# HELP lws_leaderworkerset_status_updated_replicas [ALPHA] Number of groups of the LeaderWorkerSet updated to the latest revision.
+# TYPE lws_leaderworkerset_status_updated_replicas gauge
+lws_leaderworkerset_status_updated_replicas{name="vllm",namespace="team-a"} 1
+`
+	if err := testutil.CustomCollectAndCompare(collector, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestLeaderWorkerSetMetricsController(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	operator := &leaderworkersetoperatorv1.LeaderWorkerSetOperator{
+		ObjectMeta: metav1.ObjectMeta{Name: operatorclient.OperatorConfigName},
+		Spec: leaderworkersetoperatorv1.LeaderWorkerSetOperatorSpec{
+			OperatorSpec: operatorv1.OperatorSpec{ManagementState: operatorv1.Managed},
+		},
+	}
+	operatorClient := operatorconfigfake.NewClientset(operator)
+	operatorInformers := operatorclientinformers.NewSharedInformerFactory(operatorClient, 0)
+	operatorInformer := operatorInformers.OpenShiftOperator().V1().LeaderWorkerSetOperators()
+	apiextensionClient := apiextensionsfake.NewSimpleClientset() //nolint:staticcheck
+	apiextensionInformers := apiextensionsinformers.NewSharedInformerFactory(apiextensionClient, 0)
+	crdInformer := apiextensionInformers.Apiextensions().V1().CustomResourceDefinitions()
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{leaderWorkerSetWorkloads.gvr: "LeaderWorkerSetList"},
+		newWorkload(leaderWorkerSetWorkloads, "team-a", "vllm", nil, nil),
+	)
+	c := &LeaderWorkerSetMetricsController{
+		operatorLister: operatorInformer.Lister(),
+		crdLister:      crdInformer.Lister(),
+		informers:      dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, 0),
+		kubeClient:     kubefake.NewClientset(newLeaderWorkerSetPod("team-a", "vllm", "vllm-0", "0", 3)),
+		collector:      newLeaderWorkerSetCollector(),
+	}
+	operatorInformers.Start(ctx.Done())
+	apiextensionInformers.Start(ctx.Done())
+	operatorInformers.WaitForCacheSync(ctx.Done())
+	apiextensionInformers.WaitForCacheSync(ctx.Done())
+	syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))
+
+	// nothing is watched before the CRD is served
+	if err := c.sync(ctx, syncCtx); err != nil {
+		t.Fatal(err)
+	}
+	if c.leaderWorkerSets != nil || c.pods != nil || c.collector.sources != nil {
+		t.Fatalf("expected no informers and no metrics before the LeaderWorkerSet CRD is served")
+	}
+
+	crd := newEstablishedCRD(leaderWorkerSetCRDName, "v1")
+	if err := crdInformer.Informer().GetIndexer().Add(crd); err != nil {
+		t.Fatal(err)
+	}
+	syncUntilSourcesSet := func(t *testing.T, maxGroups int) *leaderWorkerSetMetricsSources {
+		t.Helper()
+		if err := wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, 5*time.Second, true, func(ctx context.Context) (bool, error) {
+			if err := c.sync(ctx, syncCtx); err != nil {
+				return false, err
+			}
+			return c.collector.sources != nil && c.collector.sources.maxGroups == maxGroups, nil
+		}); err != nil {
+			t.Fatalf("metric sources were not set: %v", err)
+		}
+		return c.collector.sources
+	}
+	sources := syncUntilSourcesSet(t, defaultMaxGroupMetrics)
+	if sources.maxLeaderWorkerSets != defaultMaxLeaderWorkerSetMetrics || sources.maxGroups != defaultMaxGroupMetrics || sources.pods == nil {
+		t.Errorf("expected the default limits with the group restarts, got %+v", sources)
+	}
+	if pods, err := sources.pods.List(labels.Everything()); err != nil || len(pods) != 1 || pods[0].Spec.Containers != nil {
+		t.Errorf("expected the trimmed LeaderWorkerSet pod in the cache, got %v, %v", pods, err)
+	}
+
+	// disabling the group restarts stops the pod watch
+	operator = operator.DeepCopy()
+	operator.Spec.LeaderWorkerSetMetrics = &leaderworkersetoperatorv1.LeaderWorkerSetMetrics{MaxGroupsPerLeaderWorkerSet: ptr.To[int32](0)}
+	if err := operatorInformer.Informer().GetIndexer().Update(operator); err != nil {
+		t.Fatal(err)
+	}
+	sources = syncUntilSourcesSet(t, 0)
+	if c.pods != nil || sources.pods != nil {
+		t.Errorf("expected the pod watch to be stopped")
+	}
+}
@@ -0,0 +1,162 @@
+package operator
+
+import (
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+
+	leaderworkersetapiv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+)
+
+const metricsNamespace = "lws_operator"
+
+var (
+	webhookProbeDuration = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Namespace:      metricsNamespace,
+			Subsystem:      "webhook_probe",
+			Name:           "duration_seconds",
+			Help:           "Latency of the dry-run requests used to probe the operand webhooks, by probe and result.",
+			Buckets:        []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"probe", "result"},
+	)
+
+	webhookProbeHealthy = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Namespace:      metricsNamespace,
+			Subsystem:      "webhook_probe",
+			Name:           "healthy",
+			Help:           "Whether the last probe of the operand webhooks succeeded (1) or failed (0), by probe.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"probe"},
+	)
+
+	webhookProbeFailures = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      metricsNamespace,
+			Subsystem:      "webhook_probe",
+			Name:           "failures_total",
+			Help:           "Number of failed probes of the operand webhooks, by probe and reason.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"probe", "reason"},
+	)
+
+	workloadCount = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Namespace:      metricsNamespace,
+			Name:           "workloads",
+			Help:           "Number of LeaderWorkerSets and DisaggregatedSets, by kind and namespace. The namespaces beyond the ones with the most workloads are summed up in namespace=\"other\".",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"kind", "namespace"},
+	)
+
+	workloadsNotAvailable = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Namespace:      metricsNamespace,
+			Subsystem:      "workloads",
+			Name:           "not_available",
+			Help:           "Number of LeaderWorkerSets and DisaggregatedSets whose Available condition is not True, by kind.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"kind"},
+	)
+
+	workloadsProgressing = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Namespace:      metricsNamespace,
+			Subsystem:      "workloads",
+			Name:           "progressing",
+			Help:           "Number of LeaderWorkerSets and DisaggregatedSets whose Progressing condition is True, by kind.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"kind"},
+	)
+
+	workloadGroups = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Namespace:      metricsNamespace,
+			Subsystem:      "workloads",
+			Name:           "groups",
+			Help:           "Number of replica groups of all LeaderWorkerSets.",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+
+	workloadPods = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Namespace:      metricsNamespace,
+			Subsystem:      "workloads",
+			Name:           "pods",
+			Help:           "Number of pods of all LeaderWorkerSet groups, the leaders included.",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+
+	// leaderWorkerSetMetrics exposes the lws_leaderworkerset_* metrics of every LeaderWorkerSet.
+	leaderWorkerSetMetrics = newLeaderWorkerSetCollector()
+)
+
+func init() {
+	legacyregistry.MustRegister(
+		webhookProbeDuration,
+		webhookProbeHealthy,
+		webhookProbeFailures,
+		workloadCount,
+		workloadsNotAvailable,
+		workloadsProgressing,
+		workloadGroups,
+		workloadPods,
+	)
+	legacyregistry.CustomMustRegister(leaderWorkerSetMetrics)
+}
+
+// otherNamespaces is the namespace label of the workloadCount series summing up the namespaces beyond
+// maxInventoryNamespaces.
+const otherNamespaces = "other"
+
+// workloadCountLabels are the kind and namespace labels of a workloadCount series.
+type workloadCountLabels struct {
+	kind, namespace string
+}
+
+// recordWorkloadInventory sets the workload gauges and returns the workloadCount series it set. The
+// namespaces are sorted by their number of workloads; the first maxInventoryNamespaces get a series
+// each and the others are summed up in the otherNamespaces series. The series of the previous call
+// that were not set again, e.g. of namespaces without workloads, are deleted.
+func recordWorkloadInventory(inventory *leaderworkersetapiv1.WorkloadInventory, namespaces []leaderworkersetapiv1.NamespaceWorkloadCounts, recorded sets.Set[workloadCountLabels]) sets.Set[workloadCountLabels] {
+	counts := map[workloadCountLabels]int32{}
+	for i, namespaceCounts := range namespaces {
+		namespace := namespaceCounts.Namespace
+		if i >= maxInventoryNamespaces {
+			namespace = otherNamespaces
+		}
+		counts[workloadCountLabels{kind: leaderWorkerSetWorkloads.kind, namespace: namespace}] += namespaceCounts.LeaderWorkerSets
+		counts[workloadCountLabels{kind: disaggregatedSetWorkloads.kind, namespace: namespace}] += namespaceCounts.DisaggregatedSets
+	}
+	set := sets.New[workloadCountLabels]()
+	for labels, count := range counts {
+		if count > 0 {
+			workloadCount.WithLabelValues(labels.kind, labels.namespace).Set(float64(count))
+			set.Insert(labels)
+		}
+	}
+	for labels := range recorded.Difference(set) {
+		workloadCount.DeleteLabelValues(labels.kind, labels.namespace)
+	}
+
+	for kind, counts := range map[string]leaderworkersetapiv1.WorkloadCounts{
+		leaderWorkerSetWorkloads.kind:  inventory.LeaderWorkerSets,
+		disaggregatedSetWorkloads.kind: inventory.DisaggregatedSets,
+	} {
+		workloadsNotAvailable.WithLabelValues(kind).Set(float64(counts.NotAvailable))
+		workloadsProgressing.WithLabelValues(kind).Set(float64(counts.Progressing))
+	}
+	workloadGroups.Set(float64(inventory.Groups))
+	workloadPods.Set(float64(inventory.Pods))
+	return set
+}
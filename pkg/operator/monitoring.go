@@ -0,0 +1,97 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/discovery"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+)
+
+const MonitoringAvailableConditionType = "MonitoringAvailable"
+
+// serviceMonitorGVK is served once the Prometheus Operator CRDs are installed.
+var serviceMonitorGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"}
+
+// IsMonitoringAvailable reports whether the cluster serves the Prometheus Operator API, for
+// RenderOptions.MonitoringAvailable.
+func IsMonitoringAvailable(discoveryClient discovery.DiscoveryInterface) (bool, error) {
+	found, err := isResourceRegistered(discoveryClient, serviceMonitorGVK)
+	if err != nil {
+		return false, fmt.Errorf("unable to check the monitoring API is served: %w", err)
+	}
+	return found, nil
+}
+
+// manageMonitoring applies the monitoring resource group when the Prometheus Operator API is served.
+// Without it the monitoring resources are removed once and the group is skipped until the CRD appears,
+// which triggers a sync through the CRD informer. The returned condition reports which case applies.
+func (c *TargetConfigReconciler) manageMonitoring(ctx context.Context, resources []staticResource, rc *renderContext) (operatorv1.OperatorCondition, error) {
+	condition := operatorv1.OperatorCondition{
+		Type:   MonitoringAvailableConditionType,
+		Status: operatorv1.ConditionUnknown,
+	}
+
+	found, err := isResourceRegistered(c.discoveryClient, serviceMonitorGVK)
+	if err != nil {
+		condition.Reason = "DiscoveryFailed"
+		condition.Message = err.Error()
+		return condition, fmt.Errorf("unable to check the monitoring API is served: %w", err)
+	}
+
+	monitoringResources := staticResourcesInGroup(resources, monitoringResourceGroup)
+	if !found {
+		condition.Status = operatorv1.ConditionFalse
+		condition.Reason = "PrometheusOperatorNotInstalled"
+		condition.Message = fmt.Sprintf("%s is not served, the operand metrics are not scraped", serviceMonitorGVK.GroupVersion().WithResource("servicemonitors").String())
+		if c.monitoringResourcesRemoved {
+			return condition, nil
+		}
+		if err := c.removeStaticResources(ctx, monitoringResources, rc); err != nil {
+			return condition, err
+		}
+		c.monitoringResourcesRemoved = true
+		return condition, nil
+	}
+
+	c.monitoringResourcesRemoved = false
+	condition.Status = operatorv1.ConditionTrue
+	condition.Reason = "AsExpected"
+	_, err = c.applyStaticResources(ctx, monitoringResources, rc)
+	return condition, err
+}
+
+// removeStaticResources deletes the objects of the given manifests that were applied by the operator.
+func (c *TargetConfigReconciler) removeStaticResources(ctx context.Context, resources []staticResource, rc *renderContext) error {
+	var errs []error
+	for _, resource := range resources {
+		client := c.dynamicClient.Resource(resource.gvr())
+		namespace := resource.renderedNamespace(rc)
+		if rc.unmanaged(resource.gvk.GroupKind(), namespace, resource.name) {
+			continue
+		}
+		current, err := client.Namespace(namespace).Get(ctx, resource.name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if current.GetLabels()[managedByLabel] != managedByLabelValue {
+			continue
+		}
+		err = client.Namespace(namespace).Delete(ctx, resource.name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("unable to delete %s %s: %w", resource.gvk.Kind, resourceName(namespace, resource.name), err))
+			continue
+		}
+		c.eventRecorder.Eventf("MonitoringResourceDeleted", "Deleted %s %s because the monitoring API is not served", resource.gvk.Kind, resourceName(namespace, resource.name))
+	}
+	return utilerrors.NewAggregate(errs)
+}
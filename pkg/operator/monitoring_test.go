@@ -0,0 +1,65 @@
+package operator
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+func (f *targetConfigReconcilerFixture) condition(t *testing.T, conditionType string) *operatorv1.OperatorCondition {
+	t.Helper()
+	_, status, _, err := f.reconciler.leaderWorkerSetOperatorClient.GetOperatorStateWithQuorum(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v1helpers.FindOperatorCondition(status.Conditions, conditionType)
+}
+
+func TestMonitoringWithoutPrometheusOperator(t *testing.T) {
+	f := newTargetConfigReconcilerFixture(t)
+	serviceMonitors := f.dynamicClient.Resource(serviceMonitorGVK.GroupVersion().WithResource("servicemonitors")).Namespace(testNamespace)
+
+	// the monitoring resources of a cluster that had the Prometheus Operator before
+	resource := findStaticResource(t, f.reconciler.staticResources, "ServiceMonitor", "lws-controller-manager-metrics-monitor")
+	obj, err := resource.render(testRenderContext())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.reconciler.applyStaticResource(context.Background(), obj, resource); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.reconciler.sync(context.Background(), f.syncCtx); err != nil {
+		t.Fatal(err)
+	}
+	if condition := f.condition(t, MonitoringAvailableConditionType); condition == nil || condition.Status != operatorv1.ConditionFalse || condition.Reason != "PrometheusOperatorNotInstalled" {
+		t.Fatalf("expected MonitoringAvailable to be False, got %+v", condition)
+	}
+	if condition := f.condition(t, "MonitoringDegraded"); condition == nil || condition.Status != operatorv1.ConditionFalse {
+		t.Fatalf("expected MonitoringDegraded to be False, got %+v", condition)
+	}
+	if list, err := serviceMonitors.List(context.Background(), metav1.ListOptions{}); err != nil || len(list.Items) != 0 {
+		t.Fatalf("expected the ServiceMonitor to be removed, got %v, %v", list, err)
+	}
+
+	// the Prometheus Operator gets installed
+	f.discovery.Resources = append(f.discovery.Resources, &metav1.APIResourceList{
+		GroupVersion: serviceMonitorGVK.GroupVersion().String(),
+		APIResources: []metav1.APIResource{{Name: "servicemonitors", Kind: serviceMonitorGVK.Kind, Namespaced: true}},
+	})
+	f.reconciler.discoveryClient.Invalidate()
+
+	if err := f.reconciler.sync(context.Background(), f.syncCtx); err != nil {
+		t.Fatal(err)
+	}
+	if condition := f.condition(t, MonitoringAvailableConditionType); condition == nil || condition.Status != operatorv1.ConditionTrue {
+		t.Fatalf("expected MonitoringAvailable to be True, got %+v", condition)
+	}
+	if list, err := serviceMonitors.List(context.Background(), metav1.ListOptions{}); err != nil || len(list.Items) != 1 {
+		t.Fatalf("expected the ServiceMonitor to be applied, got %v, %v", list, err)
+	}
+}
@@ -0,0 +1,139 @@
+package operator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/distribution/reference"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	leaderworkersetapiv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+	"github.com/openshift/lws-operator/pkg/operator/operatorclient"
+)
+
+// resolveOperandImage returns the operand image, spec.operand.image or else the image the operator was
+// released with, validated against the image policy of the operator CR. Images set in the CR must be
+// pinned by digest unless tag references are allowed; the released image is pinned by the release.
+func resolveOperandImage(operand *leaderworkersetapiv1.OperandSpec, releaseImage string) (string, error) {
+	if operand == nil {
+		operand = &leaderworkersetapiv1.OperandSpec{}
+	}
+
+	image, source := releaseImage, "RELATED_IMAGE_OPERAND_IMAGE"
+	if operand.Image != "" {
+		image, source = operand.Image, "spec.operand.image"
+	}
+	if image == "" {
+		return "", &degradedError{
+			reason: "OperandImageMissing",
+			err:    fmt.Errorf("no operand image is available, set spec.operand.image or RELATED_IMAGE_OPERAND_IMAGE on the operator"),
+		}
+	}
+
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", &degradedError{
+			reason: "InvalidOperandImage",
+			err:    fmt.Errorf("%s %q is not a valid image reference: %w", source, image, err),
+		}
+	}
+	if _, pinned := named.(reference.Canonical); !pinned && operand.Image != "" && !operand.AllowTagReference {
+		return "", &degradedError{
+			reason: "OperandImageNotPinned",
+			err:    fmt.Errorf("%s %q must be referenced by digest, or spec.operand.allowTagReference must be set", source, image),
+		}
+	}
+	if !registryAllowed(named, operand.AllowedRegistries) {
+		return "", &degradedError{
+			reason: "OperandImageRegistryNotAllowed",
+			err:    fmt.Errorf("%s %q is not pulled from one of the allowed registries %s", source, image, strings.Join(operand.AllowedRegistries, ", ")),
+		}
+	}
+	return image, nil
+}
+
+// registryAllowed matches the repository of the image against registry hosts and repository prefixes.
+func registryAllowed(named reference.Named, allowedRegistries []string) bool {
+	if len(allowedRegistries) == 0 {
+		return true
+	}
+	repository := named.Name()
+	for _, allowed := range allowedRegistries {
+		allowed = strings.TrimSuffix(allowed, "/")
+		if repository == allowed || strings.HasPrefix(repository, allowed+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// imageDigest returns the digest of a digest reference, or an empty string for tag references.
+func imageDigest(image string) string {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return ""
+	}
+	if canonical, ok := named.(reference.Canonical); ok {
+		return canonical.Digest().String()
+	}
+	return ""
+}
+
+// runningImageDigest returns the digest the container runtime resolved the image of the operand pods
+// to, if all running pods of the Deployment agree on it.
+func runningImageDigest(podLister corev1listers.PodLister, deployment *appsv1.Deployment, image string) string {
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return ""
+	}
+	pods, err := podLister.Pods(deployment.Namespace).List(selector)
+	if err != nil {
+		return ""
+	}
+	digests := sets.New[string]()
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if !sameImage(status.Image, image) {
+				continue
+			}
+			// e.g. quay.io/org/lws@sha256:<digest>, with an optional docker-pullable:// prefix
+			if _, digest, found := strings.Cut(status.ImageID, "@"); found {
+				digests.Insert(digest)
+			}
+		}
+	}
+	if digests.Len() != 1 {
+		return ""
+	}
+	return digests.UnsortedList()[0]
+}
+
+// sameImage compares image references after normalization, since the container runtime reports
+// fully qualified references, e.g. docker.io/library/lws:v1 for lws:v1.
+func sameImage(a, b string) bool {
+	namedA, err := reference.ParseNormalizedNamed(a)
+	if err != nil {
+		return a == b
+	}
+	namedB, err := reference.ParseNormalizedNamed(b)
+	if err != nil {
+		return a == b
+	}
+	return namedA.String() == namedB.String()
+}
+
+// setOperandImageStatus reports the image the operand Deployment was applied with and its digest.
+func setOperandImageStatus(image, digest string) operatorclient.UpdateStatusFunc {
+	return func(status *leaderworkersetapiv1.LeaderWorkerSetOperatorStatus) error {
+		status.OperandImage = image
+		status.OperandImageDigest = digest
+		return nil
+	}
+}
@@ -0,0 +1,181 @@
+package operator
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	leaderworkersetoperatorv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+	"github.com/openshift/lws-operator/pkg/operator/operatorclient"
+)
+
+const (
+	testReleaseImage = "registry.redhat.io/lws/lws-rhel9@sha256:0000000000000000000000000000000000000000000000000000000000000001"
+	testHotfixImage  = "quay.io/lws-hotfix/lws@sha256:0000000000000000000000000000000000000000000000000000000000000002"
+)
+
+func TestResolveOperandImage(t *testing.T) {
+	tests := []struct {
+		name           string
+		operand        *leaderworkersetoperatorv1.OperandSpec
+		releaseImage   string
+		expectedImage  string
+		expectedReason string
+	}{
+		{
+			name:          "release image",
+			releaseImage:  testReleaseImage,
+			expectedImage: testReleaseImage,
+		},
+		{
+			name:          "release image referenced by tag",
+			releaseImage:  "quay.io/lws/lws:v0.7.0",
+			expectedImage: "quay.io/lws/lws:v0.7.0",
+		},
+		{
+			name:           "no image",
+			expectedReason: "OperandImageMissing",
+		},
+		{
+			name:          "hotfix image",
+			operand:       &leaderworkersetoperatorv1.OperandSpec{Image: testHotfixImage},
+			releaseImage:  testReleaseImage,
+			expectedImage: testHotfixImage,
+		},
+		{
+			name:           "hotfix image referenced by tag",
+			operand:        &leaderworkersetoperatorv1.OperandSpec{Image: "quay.io/lws-hotfix/lws:hotfix"},
+			releaseImage:   testReleaseImage,
+			expectedReason: "OperandImageNotPinned",
+		},
+		{
+			name:          "hotfix image referenced by tag allowed",
+			operand:       &leaderworkersetoperatorv1.OperandSpec{Image: "quay.io/lws-hotfix/lws:hotfix", AllowTagReference: true},
+			expectedImage: "quay.io/lws-hotfix/lws:hotfix",
+		},
+		{
+			name:           "invalid image",
+			operand:        &leaderworkersetoperatorv1.OperandSpec{Image: "Quay.io/LWS:latest", AllowTagReference: true},
+			expectedReason: "InvalidOperandImage",
+		},
+		{
+			name:          "allowed registry host",
+			operand:       &leaderworkersetoperatorv1.OperandSpec{AllowedRegistries: []string{"quay.io", "registry.redhat.io"}},
+			releaseImage:  testReleaseImage,
+			expectedImage: testReleaseImage,
+		},
+		{
+			name:          "allowed repository prefix",
+			operand:       &leaderworkersetoperatorv1.OperandSpec{Image: testHotfixImage, AllowedRegistries: []string{"quay.io/lws-hotfix/"}},
+			expectedImage: testHotfixImage,
+		},
+		{
+			name:           "registry not allowed",
+			operand:        &leaderworkersetoperatorv1.OperandSpec{Image: testHotfixImage, AllowedRegistries: []string{"registry.redhat.io", "quay.io/lws"}},
+			expectedReason: "OperandImageRegistryNotAllowed",
+		},
+		{
+			name:           "short name resolves to docker.io",
+			operand:        &leaderworkersetoperatorv1.OperandSpec{Image: "lws:v1", AllowTagReference: true, AllowedRegistries: []string{"quay.io"}},
+			expectedReason: "OperandImageRegistryNotAllowed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			image, err := resolveOperandImage(tt.operand, tt.releaseImage)
+			if tt.expectedReason != "" {
+				var degradedErr *degradedError
+				if !errors.As(err, &degradedErr) || degradedErr.reason != tt.expectedReason {
+					t.Fatalf("expected %s, got %v", tt.expectedReason, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if image != tt.expectedImage {
+				t.Errorf("expected %s, got %s", tt.expectedImage, image)
+			}
+		})
+	}
+}
+
+func TestRunningImageDigest(t *testing.T) {
+	const digest = "sha256:0000000000000000000000000000000000000000000000000000000000000003"
+	pod := func(name string, phase corev1.PodPhase, image, imageID string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace, Labels: map[string]string{"control-plane": "controller-manager"}},
+			Status: corev1.PodStatus{
+				Phase:             phase,
+				ContainerStatuses: []corev1.ContainerStatus{{Image: image, ImageID: imageID}},
+			},
+		}
+	}
+	kubeClient := fake.NewClientset(
+		pod("running", corev1.PodRunning, "quay.io/lws/lws:v1", "docker-pullable://quay.io/lws/lws@"+digest),
+		pod("pending", corev1.PodPending, "quay.io/lws/lws:v1", ""),
+		pod("previous", corev1.PodRunning, "quay.io/lws/lws:v0", "quay.io/lws/lws@sha256:0000000000000000000000000000000000000000000000000000000000000004"),
+	)
+	kubeInformers := informers.NewSharedInformerFactoryWithOptions(kubeClient, 0, informers.WithNamespace(testNamespace))
+	podLister := kubeInformers.Core().V1().Pods().Lister()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	kubeInformers.Start(ctx.Done())
+	kubeInformers.WaitForCacheSync(ctx.Done())
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: operandName, Namespace: testNamespace},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"control-plane": "controller-manager"}},
+		},
+	}
+	if got := runningImageDigest(podLister, deployment, "quay.io/lws/lws:v1"); got != digest {
+		t.Errorf("expected %s, got %q", digest, got)
+	}
+	if got := runningImageDigest(podLister, deployment, "quay.io/lws/lws:v2"); got != "" {
+		t.Errorf("expected no digest before the pods run the image, got %q", got)
+	}
+}
+
+func TestOperandImageStatus(t *testing.T) {
+	f := newTargetConfigReconcilerFixture(t)
+	f.updateOperatorSpec(t, func(spec *leaderworkersetoperatorv1.LeaderWorkerSetOperatorSpec) {
+		spec.Operand = &leaderworkersetoperatorv1.OperandSpec{Image: testHotfixImage}
+	})
+	f.converge(t)
+
+	operator, err := f.reconciler.operatorLister.Get(operatorclient.OperatorConfigName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if operator.Status.OperandImage != testHotfixImage || operator.Status.OperandImageDigest != imageDigest(testHotfixImage) {
+		t.Errorf("expected the hotfix image and its digest in status, got %q, %q", operator.Status.OperandImage, operator.Status.OperandImageDigest)
+	}
+
+	// a tag reference that is not allowed keeps the running Deployment and degrades the operator
+	f.updateOperatorSpec(t, func(spec *leaderworkersetoperatorv1.LeaderWorkerSetOperatorSpec) {
+		spec.Operand.Image = "quay.io/lws-hotfix/lws:latest"
+	})
+	if err := f.reconciler.sync(context.Background(), f.syncCtx); err == nil {
+		t.Fatal("expected the sync to fail")
+	}
+	condition := f.condition(t, "DeploymentDegraded")
+	if condition == nil || condition.Status != operatorv1.ConditionTrue || condition.Reason != "OperandImageNotPinned" {
+		t.Errorf("expected DeploymentDegraded with reason OperandImageNotPinned, got %+v", condition)
+	}
+	operator, err = f.reconciler.leaderWorkerSetOperatorClient.OperatorClient.LeaderWorkerSetOperators().Get(context.Background(), operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if operator.Status.OperandImage != testHotfixImage {
+		t.Errorf("expected the status to keep the applied image, got %q", operator.Status.OperandImage)
+	}
+}
@@ -0,0 +1,88 @@
+package operator
+
+import (
+	"fmt"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	leaderworkersetoperatorv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+)
+
+// operandLogLevels maps spec.logLevel to the zap verbosity of the operand.
+var operandLogLevels = map[operatorv1.LogLevel]int{
+	operatorv1.Normal:   2,
+	operatorv1.Debug:    4,
+	operatorv1.Trace:    6,
+	operatorv1.TraceAll: 9,
+}
+
+var (
+	operandLogEncoders = map[leaderworkersetoperatorv1.OperandLogEncoder]string{
+		leaderworkersetoperatorv1.OperandLogEncoderJSON:    "json",
+		leaderworkersetoperatorv1.OperandLogEncoderConsole: "console",
+	}
+	operandStacktraceLevels = map[leaderworkersetoperatorv1.OperandStacktraceLevel]string{
+		leaderworkersetoperatorv1.OperandStacktraceLevelInfo:  "info",
+		leaderworkersetoperatorv1.OperandStacktraceLevelError: "error",
+		leaderworkersetoperatorv1.OperandStacktraceLevelPanic: "panic",
+	}
+	operandTimeEncodings = map[leaderworkersetoperatorv1.OperandTimeEncoding]string{
+		leaderworkersetoperatorv1.OperandTimeEncodingEpoch:       "epoch",
+		leaderworkersetoperatorv1.OperandTimeEncodingMillis:      "millis",
+		leaderworkersetoperatorv1.OperandTimeEncodingNano:        "nano",
+		leaderworkersetoperatorv1.OperandTimeEncodingISO8601:     "iso8601",
+		leaderworkersetoperatorv1.OperandTimeEncodingRFC3339:     "rfc3339",
+		leaderworkersetoperatorv1.OperandTimeEncodingRFC3339Nano: "rfc3339nano",
+	}
+)
+
+// operandLogArgs renders the zap flags of the operand from its log level and spec.operandLogging.
+// Omitted logging fields are not rendered, so the operand keeps its defaults for them.
+func operandLogArgs(logLevel operatorv1.LogLevel, logging *leaderworkersetoperatorv1.OperandLogging) ([]string, error) {
+	level, ok := operandLogLevels[logLevel]
+	if !ok {
+		level = operandLogLevels[operatorv1.Normal]
+	}
+	args := []string{fmt.Sprintf("--zap-log-level=%d", level)}
+
+	if logging == nil {
+		return args, nil
+	}
+	if err := validateOperandLogging(logging); err != nil {
+		return nil, err
+	}
+	if logging.Encoder != "" {
+		args = append(args, "--zap-encoder="+operandLogEncoders[logging.Encoder])
+	}
+	if logging.StacktraceLevel != "" {
+		args = append(args, "--zap-stacktrace-level="+operandStacktraceLevels[logging.StacktraceLevel])
+	}
+	if logging.TimeEncoding != "" {
+		args = append(args, "--zap-time-encoding="+operandTimeEncodings[logging.TimeEncoding])
+	}
+	return args, nil
+}
+
+// validateOperandLogging repeats the validation of the CRD, which older CRDs did not enforce, since
+// the operand refuses to start with flags it does not understand.
+func validateOperandLogging(logging *leaderworkersetoperatorv1.OperandLogging) error {
+	invalid := func(format string, args ...interface{}) error {
+		return &degradedError{reason: "InvalidOperandLogging", err: fmt.Errorf(format, args...)}
+	}
+	if _, ok := operandLogEncoders[logging.Encoder]; logging.Encoder != "" && !ok {
+		return invalid("spec.operandLogging.encoder %q is not supported", logging.Encoder)
+	}
+	if _, ok := operandStacktraceLevels[logging.StacktraceLevel]; logging.StacktraceLevel != "" && !ok {
+		return invalid("spec.operandLogging.stacktraceLevel %q is not supported", logging.StacktraceLevel)
+	}
+	if _, ok := operandTimeEncodings[logging.TimeEncoding]; logging.TimeEncoding != "" && !ok {
+		return invalid("spec.operandLogging.timeEncoding %q is not supported", logging.TimeEncoding)
+	}
+	switch logging.TimeEncoding {
+	case leaderworkersetoperatorv1.OperandTimeEncodingEpoch, leaderworkersetoperatorv1.OperandTimeEncodingMillis, leaderworkersetoperatorv1.OperandTimeEncodingNano:
+		if logging.Encoder == leaderworkersetoperatorv1.OperandLogEncoderConsole {
+			return invalid("spec.operandLogging.timeEncoding %s is only supported by the JSON encoder", logging.TimeEncoding)
+		}
+	}
+	return nil
+}
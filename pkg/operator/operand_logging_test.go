@@ -0,0 +1,82 @@
+package operator
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	leaderworkersetoperatorv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+)
+
+func TestOperandLogArgs(t *testing.T) {
+	tests := []struct {
+		name           string
+		logLevel       operatorv1.LogLevel
+		logging        *leaderworkersetoperatorv1.OperandLogging
+		expectedArgs   []string
+		expectedReason string
+	}{
+		{
+			name:         "default",
+			expectedArgs: []string{"--zap-log-level=2"},
+		},
+		{
+			name:         "log level only",
+			logLevel:     operatorv1.TraceAll,
+			logging:      &leaderworkersetoperatorv1.OperandLogging{},
+			expectedArgs: []string{"--zap-log-level=9"},
+		},
+		{
+			name:     "json with rfc3339 timestamps",
+			logLevel: operatorv1.Debug,
+			logging: &leaderworkersetoperatorv1.OperandLogging{
+				Encoder:         leaderworkersetoperatorv1.OperandLogEncoderJSON,
+				StacktraceLevel: leaderworkersetoperatorv1.OperandStacktraceLevelPanic,
+				TimeEncoding:    leaderworkersetoperatorv1.OperandTimeEncodingRFC3339,
+			},
+			expectedArgs: []string{"--zap-log-level=4", "--zap-encoder=json", "--zap-stacktrace-level=panic", "--zap-time-encoding=rfc3339"},
+		},
+		{
+			name: "numeric timestamps with the default encoder",
+			logging: &leaderworkersetoperatorv1.OperandLogging{
+				TimeEncoding: leaderworkersetoperatorv1.OperandTimeEncodingMillis,
+			},
+			expectedArgs: []string{"--zap-log-level=2", "--zap-time-encoding=millis"},
+		},
+		{
+			name: "numeric timestamps with the console encoder",
+			logging: &leaderworkersetoperatorv1.OperandLogging{
+				Encoder:      leaderworkersetoperatorv1.OperandLogEncoderConsole,
+				TimeEncoding: leaderworkersetoperatorv1.OperandTimeEncodingEpoch,
+			},
+			expectedReason: "InvalidOperandLogging",
+		},
+		{
+			name: "unknown encoder",
+			logging: &leaderworkersetoperatorv1.OperandLogging{
+				Encoder: "logfmt",
+			},
+			expectedReason: "InvalidOperandLogging",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, err := operandLogArgs(tt.logLevel, tt.logging)
+			if tt.expectedReason != "" {
+				var degradedErr *degradedError
+				if !errors.As(err, &degradedErr) || degradedErr.reason != tt.expectedReason {
+					t.Fatalf("expected %s, got %v", tt.expectedReason, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(args, tt.expectedArgs) {
+				t.Errorf("expected %v, got %v", tt.expectedArgs, args)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+
+	leaderworkersetapiv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+)
+
+const (
+	namespaceResourceGroup = "Namespace"
+
+	podSecurityLevel = "restricted"
+	// clusterMonitoringLabel enables scraping by the openshift-monitoring Prometheus.
+	clusterMonitoringLabel = "openshift.io/cluster-monitoring"
+	// podSecurityLabelSyncLabel turns off the OpenShift pod security label syncer for a namespace.
+	podSecurityLabelSyncLabel = "security.openshift.io/scc.podSecurityLabelSync"
+
+	// operatorServiceAccount is the service account the operator runs as, in the operator namespace.
+	operatorServiceAccount = "openshift-lws-operator"
+	// operandNamespaceRoleBinding grants the operator the operandNamespaceClusterRole permissions in an
+	// operand namespace other than its own, where it has no Role.
+	operandNamespaceRoleBinding = "openshift-lws-operator"
+	operandNamespaceClusterRole = "openshift-lws-operator-operand-namespace"
+)
+
+var (
+	namespacesGVR   = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+	roleBindingsGVR = schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "rolebindings"}
+)
+
+// operandNamespace returns the namespace configured for the operand, defaulting to the namespace of
+// the operator.
+func operandNamespace(operator *leaderworkersetapiv1.LeaderWorkerSetOperator, operatorNamespace string) string {
+	if operator == nil || operator.Spec.OperandNamespace == "" {
+		return operatorNamespace
+	}
+	return operator.Spec.OperandNamespace
+}
+
+// operandNamespaceLabels opts the operand namespace into cluster monitoring on OpenShift and locks a
+// dedicated operand namespace down to the restricted pod security level. The pod security labels of
+// the operator namespace are left to its installer. On OpenShift, the label syncer owns the audit and
+// warn levels of namespaces outside of openshift-, so it is turned off and only enforce is applied.
+func operandNamespaceLabels(p platform, dedicated bool) map[string]string {
+	labels := map[string]string{}
+	if dedicated {
+		labels["pod-security.kubernetes.io/enforce"] = podSecurityLevel
+		if p == platformOpenShift {
+			labels[podSecurityLabelSyncLabel] = "false"
+		} else {
+			labels["pod-security.kubernetes.io/audit"] = podSecurityLevel
+			labels["pod-security.kubernetes.io/warn"] = podSecurityLevel
+		}
+	}
+	if p == platformOpenShift {
+		labels[clusterMonitoringLabel] = "true"
+	}
+	return labels
+}
+
+// manageOperandNamespace creates the operand namespace and applies its labels. The namespace does not
+// get an owner reference, so neither deleting the operator CR nor changing the operand namespace
+// deletes it, together with anything else running in it, e.g. the operator itself.
+func (c *TargetConfigReconciler) manageOperandNamespace(ctx context.Context, rc *renderContext) error {
+	if rc.unmanaged(schema.GroupKind{Kind: "Namespace"}, "", rc.namespace) {
+		return c.manageOperandNamespaceRoleBinding(ctx, rc)
+	}
+	required := renderOperandNamespace(rc)
+	hash, err := contentHash(required)
+	if err != nil {
+		return err
+	}
+	actual, err := c.dynamicClient.Resource(namespacesGVR).Apply(ctx, rc.namespace, required, metav1.ApplyOptions{
+		FieldManager: fieldManager(namespaceResourceGroup),
+	})
+	if apierrors.IsConflict(err) {
+		// e.g. an administrator relaxed the pod security level on purpose
+		conflicts, _ := fieldManagerConflicts(err)
+		return &degradedError{
+			reason: "ApplyConflict",
+			err:    fmt.Errorf("labels of namespace %s are managed by other controllers: %s", rc.namespace, strings.Join(conflicts, ", ")),
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("unable to apply namespace %s: %w", rc.namespace, err)
+	}
+	rc.generations.record(namespacesGVR.GroupResource(), actual, hash)
+	return c.manageOperandNamespaceRoleBinding(ctx, rc)
+}
+
+// manageOperandNamespaceRoleBinding binds the operandNamespaceClusterRole to the operator in a dedicated
+// operand namespace. The operator only holds read permissions on the namespaced operand kinds
+// cluster-wide, and writes them through its Role in the operator namespace or this binding. Like the
+// static resources, the binding is only applied again when it drifts.
+func (c *TargetConfigReconciler) manageOperandNamespaceRoleBinding(ctx context.Context, rc *renderContext) error {
+	if rc.namespace == c.operatorNamespace {
+		return nil
+	}
+	required := renderOperandNamespaceRoleBinding(rc)
+	hash, err := contentHash(required)
+	if err != nil {
+		return err
+	}
+	gr := roleBindingsGVR.GroupResource()
+	if cached := cachedObject(c.managedObjectListers, roleBindingsGVR, rc.namespace, operandNamespaceRoleBinding); rc.generations.unchanged(gr, required, cached, hash) {
+		klog.V(4).Infof("Skipping unchanged RoleBinding %s/%s", rc.namespace, operandNamespaceRoleBinding)
+		rc.generations.record(gr, cached, hash)
+		return nil
+	}
+	actual, err := c.dynamicClient.Resource(roleBindingsGVR).Namespace(rc.namespace).Apply(ctx, operandNamespaceRoleBinding, required, metav1.ApplyOptions{
+		FieldManager: fieldManager(namespaceResourceGroup),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to apply RoleBinding %s/%s: %w", rc.namespace, operandNamespaceRoleBinding, err)
+	}
+	rc.generations.record(gr, actual, hash)
+	return nil
+}
+
+// renderOperandNamespaceRoleBinding returns the RoleBinding applied by manageOperandNamespaceRoleBinding.
+func renderOperandNamespaceRoleBinding(rc *renderContext) *unstructured.Unstructured {
+	roleBinding := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "rbac.authorization.k8s.io/v1",
+		"kind":       "RoleBinding",
+		"metadata": map[string]interface{}{
+			"name":      operandNamespaceRoleBinding,
+			"namespace": rc.namespace,
+		},
+		"roleRef": map[string]interface{}{
+			"apiGroup": "rbac.authorization.k8s.io",
+			"kind":     "ClusterRole",
+			"name":     operandNamespaceClusterRole,
+		},
+		"subjects": []interface{}{
+			map[string]interface{}{
+				"kind":      "ServiceAccount",
+				"name":      operatorServiceAccount,
+				"namespace": rc.operatorNamespace,
+			},
+		},
+	}}
+	setManagedLabels(roleBinding, rc.operatorVersion)
+	return roleBinding
+}
+
+// renderOperandNamespace returns the operand namespace as applied by manageOperandNamespace.
+func renderOperandNamespace(rc *renderContext) *unstructured.Unstructured {
+	namespace := &unstructured.Unstructured{}
+	namespace.SetAPIVersion("v1")
+	namespace.SetKind("Namespace")
+	namespace.SetName(rc.namespace)
+	namespace.SetLabels(operandNamespaceLabels(rc.platform, rc.namespace != rc.operatorNamespace))
+	return namespace
+}
@@ -0,0 +1,241 @@
+package operator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	leaderworkersetoperatorv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+	"github.com/openshift/lws-operator/pkg/operator/operatorclient"
+)
+
+func TestOperandNamespaceLabels(t *testing.T) {
+	f := newTargetConfigReconcilerFixture(t)
+	f.converge(t)
+
+	namespace, err := f.dynamicClient.Resource(namespacesGVR).Get(context.Background(), testNamespace, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	labels := namespace.GetLabels()
+	// the pod security labels of the operator namespace are left to its installer
+	for _, key := range []string{"pod-security.kubernetes.io/enforce", "pod-security.kubernetes.io/audit", "pod-security.kubernetes.io/warn"} {
+		if _, ok := labels[key]; ok {
+			t.Errorf("expected no %s label on the operator namespace, got %v", key, labels)
+		}
+	}
+	if labels[clusterMonitoringLabel] != "true" {
+		t.Errorf("expected %s=true on OpenShift, got %v", clusterMonitoringLabel, labels)
+	}
+	if len(namespace.GetOwnerReferences()) != 0 {
+		t.Errorf("expected the namespace not to be owned by the operator CR, got %v", namespace.GetOwnerReferences())
+	}
+
+	// the label syncer owns audit and warn of a dedicated namespace on OpenShift
+	labels = operandNamespaceLabels(platformOpenShift, true)
+	if labels["pod-security.kubernetes.io/enforce"] != podSecurityLevel || labels[podSecurityLabelSyncLabel] != "false" {
+		t.Errorf("expected the enforce level without the label syncer, got %v", labels)
+	}
+	if _, ok := labels["pod-security.kubernetes.io/audit"]; ok {
+		t.Errorf("expected no audit level on OpenShift, got %v", labels)
+	}
+
+	labels = operandNamespaceLabels(platformKubernetes, true)
+	for _, key := range []string{"pod-security.kubernetes.io/enforce", "pod-security.kubernetes.io/audit", "pod-security.kubernetes.io/warn"} {
+		if labels[key] != podSecurityLevel {
+			t.Errorf("expected %s=%s on Kubernetes, got %v", key, podSecurityLevel, labels)
+		}
+	}
+	if _, ok := labels[clusterMonitoringLabel]; ok {
+		t.Errorf("expected no %s label on Kubernetes", clusterMonitoringLabel)
+	}
+}
+
+func TestOperandNamespaceChangeRestartsOperator(t *testing.T) {
+	f := newTargetConfigReconcilerFixture(t)
+	var restartReason string
+	f.reconciler.restart = func(reason string) { restartReason = reason }
+
+	ctx := context.Background()
+	f.updateOperatorSpec(t, func(spec *leaderworkersetoperatorv1.LeaderWorkerSetOperatorSpec) {
+		spec.OperandNamespace = "lws-operand"
+	})
+
+	f.clearActions()
+	if err := f.reconciler.sync(ctx, f.syncCtx); err != nil {
+		t.Fatal(err)
+	}
+	if restartReason != "operand namespace changed from openshift-lws-operator to lws-operand" {
+		t.Errorf("expected a restart, got %q", restartReason)
+	}
+	if len(f.dynamicClient.Actions()) != 0 {
+		t.Errorf("expected nothing to be applied before the restart, got %v", f.dynamicClient.Actions())
+	}
+}
+
+func TestOperandNamespaceMigration(t *testing.T) {
+	const previousNamespace = "lws-previous"
+	managed := map[string]string{managedByLabel: managedByLabelValue, operatorVersionLabel: "v1.2.3"}
+
+	serviceAccount := &unstructured.Unstructured{}
+	serviceAccount.SetAPIVersion("v1")
+	serviceAccount.SetKind("ServiceAccount")
+	serviceAccount.SetNamespace(previousNamespace)
+	serviceAccount.SetName("lws-controller-manager")
+	serviceAccount.SetLabels(managed)
+
+	certificate := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"secretName": WebhookCertificateSecretName},
+	}}
+	certificate.SetAPIVersion("cert-manager.io/v1")
+	certificate.SetKind("Certificate")
+	certificate.SetNamespace(previousNamespace)
+	certificate.SetName(WebhookCertificateName)
+	certificate.SetLabels(managed)
+
+	// the binding applied by the operator while the operand ran in the previous namespace
+	roleBinding := renderOperandNamespaceRoleBinding(&renderContext{namespace: previousNamespace, operatorNamespace: testNamespace, operatorVersion: "v1.2.3"})
+
+	f := newTargetConfigReconcilerFixture(t, serviceAccount, certificate, roleBinding)
+	ctx := context.Background()
+	operators := f.reconciler.leaderWorkerSetOperatorClient.OperatorClient.LeaderWorkerSetOperators()
+	operator, err := operators.Get(ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	operator.Status.OperandNamespace = previousNamespace
+	if _, err := operators.UpdateStatus(ctx, operator, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, 5*time.Second, true, func(context.Context) (bool, error) {
+		cached, err := f.reconciler.operatorLister.Get(operatorclient.OperatorConfigName)
+		return err == nil && cached.Status.OperandNamespace == previousNamespace, nil
+	}); err != nil {
+		t.Fatalf("status update not observed: %v", err)
+	}
+	_, err = f.reconciler.kubeClient.CoreV1().Secrets(previousNamespace).Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        WebhookCertificateSecretName,
+			Namespace:   previousNamespace,
+			Annotations: map[string]string{certificateNameAnnotation: WebhookCertificateName},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f.converge(t)
+
+	for _, obj := range []*unstructured.Unstructured{serviceAccount, certificate} {
+		gvr := schema.GroupVersionResource{Group: obj.GroupVersionKind().Group, Version: "v1", Resource: "serviceaccounts"}
+		if obj.GetKind() == "Certificate" {
+			gvr.Resource = "certificates"
+		}
+		if _, err := f.dynamicClient.Resource(gvr).Namespace(previousNamespace).Get(ctx, obj.GetName(), metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+			t.Errorf("expected %s %s to be deleted from the previous namespace, got %v", obj.GetKind(), obj.GetName(), err)
+		}
+		if _, err := f.dynamicClient.Resource(gvr).Namespace(testNamespace).Get(ctx, obj.GetName(), metav1.GetOptions{}); err != nil {
+			t.Errorf("expected %s %s to be applied to the operand namespace, got %v", obj.GetKind(), obj.GetName(), err)
+		}
+	}
+	if _, err := f.reconciler.kubeClient.CoreV1().Secrets(previousNamespace).Get(ctx, WebhookCertificateSecretName, metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the certificate secret to be deleted from the previous namespace, got %v", err)
+	}
+	if _, err := f.reconciler.kubeClient.CoreV1().Secrets(testNamespace).Get(ctx, WebhookCertificateSecretName, metav1.GetOptions{}); err != nil {
+		t.Errorf("expected the certificate secret of the operand namespace to be kept, got %v", err)
+	}
+	if _, err := f.dynamicClient.Resource(roleBindingsGVR).Namespace(previousNamespace).Get(ctx, operandNamespaceRoleBinding, metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the operator RoleBinding to be deleted from the previous namespace, got %v", err)
+	}
+	if operator, err := operators.Get(ctx, operatorclient.OperatorConfigName, metav1.GetOptions{}); err != nil || operator.Status.OperandNamespace != testNamespace {
+		t.Errorf("expected status.operandNamespace to be updated to %s, got %v", testNamespace, err)
+	}
+}
+
+func TestOperandNamespaceRoleBinding(t *testing.T) {
+	f := newTargetConfigReconcilerFixture(t)
+	ctx := context.Background()
+	rc := testRenderContext()
+	rc.namespace = testNamespace
+	rc.operatorNamespace = testNamespace
+	rc.generations = newAppliedGenerations(nil)
+
+	// the operator namespace is covered by the Role of the operator
+	if err := f.reconciler.manageOperandNamespace(ctx, rc); err != nil {
+		t.Fatal(err)
+	}
+	roleBindings := f.dynamicClient.Resource(roleBindingsGVR)
+	if _, err := roleBindings.Namespace(testNamespace).Get(ctx, operandNamespaceRoleBinding, metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected no RoleBinding in the operator namespace, got %v", err)
+	}
+
+	rc.namespace = "lws-operand"
+	if err := f.reconciler.manageOperandNamespace(ctx, rc); err != nil {
+		t.Fatal(err)
+	}
+	roleBinding, err := roleBindings.Namespace("lws-operand").Get(ctx, operandNamespaceRoleBinding, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected the operator RoleBinding in the operand namespace: %v", err)
+	}
+	if name, _, _ := unstructured.NestedString(roleBinding.Object, "roleRef", "name"); name != operandNamespaceClusterRole {
+		t.Errorf("expected the RoleBinding to reference %s, got %s", operandNamespaceClusterRole, name)
+	}
+	if rc.generations.current[len(rc.generations.current)-1].Resource != roleBindingsGVR.Resource {
+		t.Errorf("expected the RoleBinding in status.generations, got %+v", rc.generations.current)
+	}
+
+	// an unchanged binding is not applied again
+	if err := wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, 5*time.Second, true, func(context.Context) (bool, error) {
+		return cachedObject(f.reconciler.managedObjectListers, roleBindingsGVR, "lws-operand", operandNamespaceRoleBinding) != nil, nil
+	}); err != nil {
+		t.Fatalf("RoleBinding not observed: %v", err)
+	}
+	f.clearActions()
+	rc.generations = newAppliedGenerations(rc.generations.current)
+	if err := f.reconciler.manageOperandNamespaceRoleBinding(ctx, rc); err != nil {
+		t.Fatal(err)
+	}
+	if applied := appliedObjects(f.actions()); applied["rolebindings/"+operandNamespaceRoleBinding] {
+		t.Errorf("expected the unchanged RoleBinding not to be applied")
+	}
+	if len(rc.generations.current) != 1 {
+		t.Errorf("expected the skipped RoleBinding to be recorded, got %+v", rc.generations.current)
+	}
+
+	// a hand edited binding is applied again
+	if err := unstructured.SetNestedSlice(roleBinding.Object, []interface{}{}, "subjects"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := roleBindings.Namespace("lws-operand").Update(ctx, roleBinding, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, 5*time.Second, true, func(context.Context) (bool, error) {
+		cached := cachedObject(f.reconciler.managedObjectListers, roleBindingsGVR, "lws-operand", operandNamespaceRoleBinding)
+		return cached != nil && len(cached.Object["subjects"].([]interface{})) == 0, nil
+	}); err != nil {
+		t.Fatalf("RoleBinding update not observed: %v", err)
+	}
+	f.clearActions()
+	rc.generations = newAppliedGenerations(rc.generations.current)
+	if err := f.reconciler.manageOperandNamespaceRoleBinding(ctx, rc); err != nil {
+		t.Fatal(err)
+	}
+	if applied := appliedObjects(f.actions()); !applied["rolebindings/"+operandNamespaceRoleBinding] {
+		t.Errorf("expected the edited RoleBinding to be applied again, got %v", applied)
+	}
+
+	// the managed-by labels do not make the binding a stale RoleBinding
+	if err := f.reconciler.collectGarbage(ctx, f.reconciler.staticResources, rc); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := roleBindings.Namespace("lws-operand").Get(ctx, operandNamespaceRoleBinding, metav1.GetOptions{}); err != nil {
+		t.Errorf("expected the RoleBinding to be kept by the garbage collection: %v", err)
+	}
+}
@@ -0,0 +1,161 @@
+package operator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	leaderworkersetoperatorv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+)
+
+const (
+	// additionalTrustBundleKey is the ConfigMap key holding the PEM encoded CA certificates, as used by
+	// the OpenShift trusted CA bundle injection.
+	additionalTrustBundleKey    = "ca-bundle.crt"
+	additionalTrustBundleVolume = "additional-trust-bundle"
+	// additionalTrustBundleDir is added to the CA directories of the operand through SSL_CERT_DIR. Go
+	// keeps loading the system bundle file, so the system CAs stay trusted.
+	additionalTrustBundleDir = "/etc/pki/lws/additional-trust-bundle"
+)
+
+// operandReferenceGetters read the secrets and ConfigMaps the operand pods reference, from the informers
+// in the reconciler and from the API in the CLI.
+type operandReferenceGetters struct {
+	secret    func(namespace, name string) (*corev1.Secret, error)
+	configMap func(namespace, name string) (*corev1.ConfigMap, error)
+}
+
+// operandReferenceAnnotations validates the secrets and ConfigMaps referenced by spec.operand and
+// returns a hash of their content per object, which is recorded in the pod template annotations so
+// the pods are rolled when a pull secret or the trust bundle changes.
+func (c *TargetConfigReconciler) operandReferenceAnnotations(namespace string, operand *leaderworkersetoperatorv1.OperandSpec) (map[string]string, error) {
+	return operandReferenceAnnotations(operandReferenceGetters{
+		secret: func(namespace, name string) (*corev1.Secret, error) {
+			return c.secretLister.Secrets(namespace).Get(name)
+		},
+		configMap: func(namespace, name string) (*corev1.ConfigMap, error) {
+			return c.configMapLister.ConfigMaps(namespace).Get(name)
+		},
+	}, namespace, operand)
+}
+
+func operandReferenceAnnotations(getters operandReferenceGetters, namespace string, operand *leaderworkersetoperatorv1.OperandSpec) (map[string]string, error) {
+	annotations := map[string]string{}
+	if operand == nil {
+		return annotations, nil
+	}
+
+	for _, pullSecret := range operand.ImagePullSecrets {
+		secret, err := getters.secret(namespace, pullSecret.Name)
+		if apierrors.IsNotFound(err) {
+			return nil, &degradedError{
+				reason: "OperandReferenceMissing",
+				err:    fmt.Errorf("image pull secret %s/%s from spec.operand.imagePullSecrets does not exist", namespace, pullSecret.Name),
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		annotations["secrets/"+secret.Name] = hashData(secret.Data)
+	}
+
+	if operand.AdditionalTrustBundle != nil {
+		name := operand.AdditionalTrustBundle.Name
+		configMap, err := getters.configMap(namespace, name)
+		if apierrors.IsNotFound(err) {
+			return nil, &degradedError{
+				reason: "OperandReferenceMissing",
+				err:    fmt.Errorf("trust bundle ConfigMap %s/%s from spec.operand.additionalTrustBundle does not exist", namespace, name),
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(configMap.Data[additionalTrustBundleKey]) == 0 {
+			return nil, &degradedError{
+				reason: "InvalidAdditionalTrustBundle",
+				err:    fmt.Errorf("trust bundle ConfigMap %s/%s has no %s key", namespace, name, additionalTrustBundleKey),
+			}
+		}
+		annotations["configmaps/"+configMap.Name] = hashData(map[string][]byte{
+			additionalTrustBundleKey: []byte(configMap.Data[additionalTrustBundleKey]),
+		})
+	}
+	return annotations, nil
+}
+
+// hashData returns a stable hash of secret or ConfigMap data.
+func hashData(data map[string][]byte) string {
+	hash := sha256.New()
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		// the lengths keep the boundaries between keys and values unambiguous
+		fmt.Fprintf(hash, "%d:%s%d:", len(key), key, len(data[key]))
+		hash.Write(data[key])
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// setOperandImagePullSecrets adds the spec.operand.imagePullSecrets to the operand ServiceAccount.
+func setOperandImagePullSecrets(obj runtime.Object, rc *renderContext) error {
+	serviceAccount, ok := obj.(*corev1.ServiceAccount)
+	if !ok {
+		return fmt.Errorf("unexpected type %T", obj)
+	}
+	if operand := rc.operator.Spec.Operand; operand != nil {
+		serviceAccount.ImagePullSecrets = mergeLocalObjectReferences(serviceAccount.ImagePullSecrets, operand.ImagePullSecrets)
+	}
+	return nil
+}
+
+// applyOperandReferences adds the image pull secrets and the additional trust bundle from the operator
+// CR to the operand pod template. The pull secrets are also set on the pods, since the ServiceAccount
+// only provides them to pods created after it was updated.
+func applyOperandReferences(podSpec *corev1.PodSpec, operand *leaderworkersetoperatorv1.OperandSpec) {
+	if operand == nil {
+		return
+	}
+
+	podSpec.ImagePullSecrets = mergeLocalObjectReferences(podSpec.ImagePullSecrets, operand.ImagePullSecrets)
+
+	if operand.AdditionalTrustBundle == nil {
+		return
+	}
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: additionalTrustBundleVolume,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: *operand.AdditionalTrustBundle,
+				Items:                []corev1.KeyToPath{{Key: additionalTrustBundleKey, Path: additionalTrustBundleKey}},
+			},
+		},
+	})
+	for i := range podSpec.Containers {
+		container := &podSpec.Containers[i]
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      additionalTrustBundleVolume,
+			MountPath: additionalTrustBundleDir,
+			ReadOnly:  true,
+		})
+		container.Env = append(container.Env, corev1.EnvVar{Name: "SSL_CERT_DIR", Value: additionalTrustBundleDir})
+	}
+}
+
+// mergeLocalObjectReferences appends the references that are not in the list yet.
+func mergeLocalObjectReferences(existing, additional []corev1.LocalObjectReference) []corev1.LocalObjectReference {
+	for _, reference := range additional {
+		if !slices.Contains(existing, reference) {
+			existing = append(existing, reference)
+		}
+	}
+	return existing
+}
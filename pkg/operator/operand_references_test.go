@@ -0,0 +1,154 @@
+package operator
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	leaderworkersetoperatorv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+)
+
+func TestApplyOperandReferences(t *testing.T) {
+	podSpec := &corev1.PodSpec{
+		ImagePullSecrets: []corev1.LocalObjectReference{{Name: "existing"}},
+		Containers:       []corev1.Container{{Name: "manager"}},
+	}
+	applyOperandReferences(podSpec, &leaderworkersetoperatorv1.OperandSpec{
+		ImagePullSecrets:      []corev1.LocalObjectReference{{Name: "existing"}, {Name: "mirror"}},
+		AdditionalTrustBundle: &corev1.LocalObjectReference{Name: "mirror-ca"},
+	})
+
+	if len(podSpec.ImagePullSecrets) != 2 || podSpec.ImagePullSecrets[1].Name != "mirror" {
+		t.Errorf("expected the pull secrets to be merged, got %v", podSpec.ImagePullSecrets)
+	}
+	if len(podSpec.Volumes) != 1 || podSpec.Volumes[0].ConfigMap == nil || podSpec.Volumes[0].ConfigMap.Name != "mirror-ca" {
+		t.Fatalf("expected a trust bundle volume, got %v", podSpec.Volumes)
+	}
+	container := podSpec.Containers[0]
+	if len(container.VolumeMounts) != 1 || container.VolumeMounts[0].MountPath != additionalTrustBundleDir {
+		t.Errorf("expected the trust bundle to be mounted at %s, got %v", additionalTrustBundleDir, container.VolumeMounts)
+	}
+	if len(container.Env) != 1 || container.Env[0].Name != "SSL_CERT_DIR" || container.Env[0].Value != additionalTrustBundleDir {
+		t.Errorf("expected SSL_CERT_DIR=%s, got %v", additionalTrustBundleDir, container.Env)
+	}
+}
+
+func TestHashData(t *testing.T) {
+	if hashData(map[string][]byte{"a": []byte("bc")}) == hashData(map[string][]byte{"ab": []byte("c")}) {
+		t.Error("expected different key boundaries to hash differently")
+	}
+	if hashData(map[string][]byte{"a": []byte("1"), "b": []byte("2")}) != hashData(map[string][]byte{"b": []byte("2"), "a": []byte("1")}) {
+		t.Error("expected the hash not to depend on map order")
+	}
+}
+
+func TestOperandReferences(t *testing.T) {
+	f := newTargetConfigReconcilerFixture(t)
+	ctx := context.Background()
+	f.updateOperatorSpec(t, func(spec *leaderworkersetoperatorv1.LeaderWorkerSetOperatorSpec) {
+		spec.Operand = &leaderworkersetoperatorv1.OperandSpec{
+			ImagePullSecrets:      []corev1.LocalObjectReference{{Name: "mirror-pull-secret"}},
+			AdditionalTrustBundle: &corev1.LocalObjectReference{Name: "mirror-ca"},
+		}
+	})
+
+	// the Deployment is not applied until the referenced objects exist
+	if err := f.reconciler.sync(ctx, f.syncCtx); err == nil {
+		t.Fatal("expected the sync to fail")
+	}
+	condition := f.condition(t, "DeploymentDegraded")
+	if condition == nil || condition.Status != operatorv1.ConditionTrue || condition.Reason != "OperandReferenceMissing" {
+		t.Errorf("expected DeploymentDegraded with reason OperandReferenceMissing, got %+v", condition)
+	}
+
+	kubeClient := f.reconciler.kubeClient
+	if _, err := kubeClient.CoreV1().Secrets(testNamespace).Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "mirror-pull-secret", Namespace: testNamespace},
+		Type:       corev1.SecretTypeDockerConfigJson,
+		Data:       map[string][]byte{corev1.DockerConfigJsonKey: []byte(`{"auths":{}}`)},
+	}, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	configMap, err := kubeClient.CoreV1().ConfigMaps(testNamespace).Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "mirror-ca", Namespace: testNamespace},
+		Data:       map[string]string{additionalTrustBundleKey: "first CA"},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.converge(t)
+
+	serviceAccount, err := f.dynamicClient.Resource(schema.GroupVersionResource{Version: "v1", Resource: "serviceaccounts"}).Namespace(testNamespace).Get(ctx, operandName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pullSecrets, _, _ := unstructured.NestedSlice(serviceAccount.Object, "imagePullSecrets")
+	if len(pullSecrets) != 1 {
+		t.Errorf("expected the pull secret on the ServiceAccount, got %v", pullSecrets)
+	}
+
+	deployment := f.operandDeployment(t)
+	firstHash := deployment.Spec.Template.Annotations["configmaps/mirror-ca"]
+	if firstHash == "" || deployment.Spec.Template.Annotations["secrets/mirror-pull-secret"] == "" {
+		t.Errorf("expected the referenced objects to be hashed into the pod template, got %v", deployment.Spec.Template.Annotations)
+	}
+	if len(deployment.Spec.Template.Spec.ImagePullSecrets) != 1 {
+		t.Errorf("expected the pull secret on the pods, got %v", deployment.Spec.Template.Spec.ImagePullSecrets)
+	}
+
+	// a new CA rolls the pods
+	configMap.Data[additionalTrustBundleKey] = "second CA"
+	if _, err := kubeClient.CoreV1().ConfigMaps(testNamespace).Update(ctx, configMap, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, 5*time.Second, true, func(context.Context) (bool, error) {
+		cached, err := f.reconciler.configMapLister.ConfigMaps(testNamespace).Get("mirror-ca")
+		return err == nil && cached.Data[additionalTrustBundleKey] == "second CA", nil
+	}); err != nil {
+		t.Fatalf("ConfigMap update not observed: %v", err)
+	}
+	if err := f.reconciler.sync(ctx, f.syncCtx); err != nil {
+		t.Fatal(err)
+	}
+	if hash := f.operandDeployment(t).Spec.Template.Annotations["configmaps/mirror-ca"]; hash == firstHash {
+		t.Error("expected the trust bundle hash to change")
+	}
+
+	// a bundle without the expected key is reported instead of mounting an empty directory
+	delete(configMap.Data, additionalTrustBundleKey)
+	configMap.Data["ca.crt"] = "second CA"
+	if _, err := kubeClient.CoreV1().ConfigMaps(testNamespace).Update(ctx, configMap, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, 5*time.Second, true, func(context.Context) (bool, error) {
+		_, err := f.reconciler.operandReferenceAnnotations(testNamespace, &leaderworkersetoperatorv1.OperandSpec{AdditionalTrustBundle: &corev1.LocalObjectReference{Name: "mirror-ca"}})
+		var degradedErr *degradedError
+		return errors.As(err, &degradedErr) && degradedErr.reason == "InvalidAdditionalTrustBundle", nil
+	}); err != nil {
+		t.Errorf("expected InvalidAdditionalTrustBundle: %v", err)
+	}
+}
+
+func (f *targetConfigReconcilerFixture) operandDeployment(tb testing.TB) *appsv1.Deployment {
+	tb.Helper()
+	obj, err := f.dynamicClient.Resource(schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}).Namespace(testNamespace).Get(context.Background(), operandName, metav1.GetOptions{})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	deployment := &appsv1.Deployment{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, deployment); err != nil {
+		tb.Fatal(err)
+	}
+	return deployment
+}
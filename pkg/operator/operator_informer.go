@@ -0,0 +1,49 @@
+package operator
+
+import (
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+
+	leaderworkersetapiv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+)
+
+// specChangesInformer is the operator CR informer without the updates that only change the status. The
+// status is written by several controllers, e.g. by the workload inventory every 30 seconds, and the
+// controllers watching the CR only act on its spec and metadata.
+type specChangesInformer struct {
+	factory.Informer
+}
+
+func (i specChangesInformer) AddEventHandler(handler cache.ResourceEventHandler) (cache.ResourceEventHandlerRegistration, error) {
+	return i.Informer.AddEventHandler(ignoreStatusUpdates{handler})
+}
+
+type ignoreStatusUpdates struct {
+	cache.ResourceEventHandler
+}
+
+func (h ignoreStatusUpdates) OnUpdate(oldObj, newObj interface{}) {
+	if statusOnlyUpdate(oldObj, newObj) {
+		return
+	}
+	h.ResourceEventHandler.OnUpdate(oldObj, newObj)
+}
+
+// statusOnlyUpdate reports whether an update of the operator CR only changed its status. The resyncs of
+// the informer, which do not change the resource version, are passed on.
+func statusOnlyUpdate(oldObj, newObj interface{}) bool {
+	oldOperator, ok := oldObj.(*leaderworkersetapiv1.LeaderWorkerSetOperator)
+	if !ok {
+		return false
+	}
+	newOperator, ok := newObj.(*leaderworkersetapiv1.LeaderWorkerSetOperator)
+	if !ok || oldOperator.ResourceVersion == newOperator.ResourceVersion {
+		return false
+	}
+	oldMeta, newMeta := oldOperator.ObjectMeta.DeepCopy(), newOperator.ObjectMeta.DeepCopy()
+	oldMeta.ResourceVersion, newMeta.ResourceVersion = "", ""
+	oldMeta.ManagedFields, newMeta.ManagedFields = nil, nil
+	return equality.Semantic.DeepEqual(oldOperator.Spec, newOperator.Spec) && equality.Semantic.DeepEqual(oldMeta, newMeta)
+}
@@ -0,0 +1,66 @@
+package operator
+
+import (
+	"testing"
+
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	leaderworkersetoperatorv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+)
+
+func TestIgnoreStatusUpdates(t *testing.T) {
+	operator := &leaderworkersetoperatorv1.LeaderWorkerSetOperator{}
+	operator.Name = "cluster"
+	operator.ResourceVersion = "1"
+	operator.Spec.ManagementState = operatorv1.Managed
+
+	for _, tc := range []struct {
+		name     string
+		update   func(*leaderworkersetoperatorv1.LeaderWorkerSetOperator)
+		expected bool
+	}{
+		{
+			name: "status",
+			update: func(operator *leaderworkersetoperatorv1.LeaderWorkerSetOperator) {
+				operator.Status.Workloads = &leaderworkersetoperatorv1.WorkloadInventory{Groups: 3}
+			},
+		},
+		{
+			name: "spec",
+			update: func(operator *leaderworkersetoperatorv1.LeaderWorkerSetOperator) {
+				operator.Spec.LogLevel = operatorv1.Debug
+				operator.Generation++
+			},
+			expected: true,
+		},
+		{
+			name: "annotations",
+			update: func(operator *leaderworkersetoperatorv1.LeaderWorkerSetOperator) {
+				operator.Annotations = map[string]string{"example.com/owner": "ml-platform"}
+			},
+			expected: true,
+		},
+		{
+			name:     "resync",
+			expected: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			updated := operator.DeepCopy()
+			if tc.update != nil {
+				updated.ResourceVersion = "2"
+				tc.update(updated)
+			}
+			queued := false
+			handler := ignoreStatusUpdates{cache.ResourceEventHandlerFuncs{
+				UpdateFunc: func(interface{}, interface{}) { queued = true },
+			}}
+			handler.OnUpdate(operator, updated)
+			if queued != tc.expected {
+				t.Errorf("expected the update to be passed on: %v, got %v", tc.expected, queued)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package operatorclient
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	leaderworkersetoperatorapiv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+)
+
+// UpdateStatusFunc modifies the status of the operator CR, including the fields that are not part of
+// the library-go OperatorStatus.
+type UpdateStatusFunc func(status *leaderworkersetoperatorapiv1.LeaderWorkerSetOperatorStatus) error
+
+// OperatorStatusFuncs adapts library-go status update functions, e.g. v1helpers.UpdateConditionFn.
+func OperatorStatusFuncs(updateFuncs ...v1helpers.UpdateStatusFunc) UpdateStatusFunc {
+	return func(status *leaderworkersetoperatorapiv1.LeaderWorkerSetOperatorStatus) error {
+		for _, update := range updateFuncs {
+			if err := update(&status.OperatorStatus); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// UpdateStatus applies the update functions to the whole status of the operator CR, the same way
+// v1helpers.UpdateStatus does for the OperatorStatus: the current status is read from the lister,
+// falling back to a live GET after a conflict, and nothing is written when the status does not change.
+func (l *LeaderWorkerSetClient) UpdateStatus(ctx context.Context, updateFuncs ...UpdateStatusFunc) (bool, error) {
+	updated := false
+	previousResourceVersion := ""
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		instance, err := l.Lister.Get(OperatorConfigName)
+		if err != nil {
+			return err
+		}
+		if instance.ResourceVersion == previousResourceVersion {
+			// the lister has not caught up with the conflicting write
+			instance, err = l.OperatorClient.LeaderWorkerSetOperators().Get(ctx, OperatorConfigName, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			klog.V(2).Infof("lister was stale at resourceVersion=%v, live get showed resourceVersion=%v", previousResourceVersion, instance.ResourceVersion)
+		}
+		previousResourceVersion = instance.ResourceVersion
+
+		required := instance.DeepCopy()
+		for _, update := range updateFuncs {
+			if err := update(&required.Status); err != nil {
+				return err
+			}
+		}
+		if equality.Semantic.DeepEqual(instance.Status, required.Status) {
+			return nil
+		}
+
+		if _, err := l.OperatorClient.LeaderWorkerSetOperators().UpdateStatus(ctx, required, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+		updated = true
+		return nil
+	})
+	return updated, err
+}
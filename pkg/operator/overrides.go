@@ -0,0 +1,55 @@
+package operator
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	leaderworkersetoperatorv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+)
+
+const unsupportedOverridesConditionType = "UnsupportedOverrides"
+
+// unmanaged reports whether spec.overrides takes the object out of management. Unmanaged objects are
+// skipped by every step that applies or deletes operand objects.
+func (rc *renderContext) unmanaged(gk schema.GroupKind, namespace, name string) bool {
+	for _, override := range rc.operator.Spec.Overrides {
+		if override.Unmanaged && override.Group == gk.Group && override.Kind == gk.Kind && override.Name == name &&
+			(override.Namespace == "" || override.Namespace == namespace) {
+			return true
+		}
+	}
+	return false
+}
+
+// unsupportedOverridesCondition lists the unmanaged objects, since the operator can neither keep them
+// up to date nor roll them back.
+func unsupportedOverridesCondition(overrides []leaderworkersetoperatorv1.ComponentOverride) operatorv1.OperatorCondition {
+	var unmanaged []string
+	for _, override := range overrides {
+		if !override.Unmanaged {
+			continue
+		}
+		kind := override.Kind
+		if override.Group != "" {
+			kind += "." + override.Group
+		}
+		unmanaged = append(unmanaged, fmt.Sprintf("%s %s", kind, resourceName(override.Namespace, override.Name)))
+	}
+	if len(unmanaged) == 0 {
+		return operatorv1.OperatorCondition{
+			Type:   unsupportedOverridesConditionType,
+			Status: operatorv1.ConditionFalse,
+			Reason: "AsExpected",
+		}
+	}
+	return operatorv1.OperatorCondition{
+		Type:    unsupportedOverridesConditionType,
+		Status:  operatorv1.ConditionTrue,
+		Reason:  "UnmanagedObjects",
+		Message: "The following objects are not managed by the operator: " + strings.Join(unmanaged, ", "),
+	}
+}
@@ -0,0 +1,79 @@
+package operator
+
+import (
+	"context"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clienttesting "k8s.io/client-go/testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	leaderworkersetoperatorv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+)
+
+func TestUnmanagedOverrides(t *testing.T) {
+	staleRole := &unstructured.Unstructured{}
+	staleRole.SetAPIVersion("rbac.authorization.k8s.io/v1")
+	staleRole.SetKind("ClusterRole")
+	staleRole.SetName("lws-hotfix-role")
+	staleRole.SetLabels(map[string]string{managedByLabel: managedByLabelValue, operatorVersionLabel: "v1.2.3"})
+
+	f := newTargetConfigReconcilerFixture(t, staleRole)
+	ctx := context.Background()
+	f.updateOperatorSpec(t, func(spec *leaderworkersetoperatorv1.LeaderWorkerSetOperatorSpec) {
+		spec.Overrides = []leaderworkersetoperatorv1.ComponentOverride{
+			{Group: "admissionregistration.k8s.io", Kind: "ValidatingWebhookConfiguration", Name: "lws-validating-webhook-configuration", Unmanaged: true},
+			{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: "lws-hotfix-role", Unmanaged: true},
+			{Group: "apps", Kind: "Deployment", Namespace: testNamespace, Name: operandName, Unmanaged: true},
+			{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: "lws-manager-role", Unmanaged: false},
+		}
+	})
+	f.converge(t)
+
+	var applied []string
+	for _, action := range f.dynamicClient.Actions() {
+		if patch, ok := action.(clienttesting.PatchAction); ok {
+			applied = append(applied, patch.GetResource().Resource+"/"+patch.GetName())
+		}
+	}
+	for _, unexpected := range []string{"validatingwebhookconfigurations/lws-validating-webhook-configuration", "deployments/" + operandName} {
+		for _, name := range applied {
+			if name == unexpected {
+				t.Errorf("expected %s not to be applied", unexpected)
+			}
+		}
+	}
+	if _, err := f.dynamicClient.Resource(schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}).Namespace(testNamespace).Get(ctx, operandName, metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the unmanaged Deployment not to be created, got %v", err)
+	}
+	if _, err := f.dynamicClient.Resource(schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"}).Get(ctx, "lws-hotfix-role", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected the unmanaged ClusterRole not to be garbage collected, got %v", err)
+	}
+	if _, err := f.dynamicClient.Resource(schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"}).Get(ctx, "lws-manager-role", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected overrides without unmanaged to be ignored, got %v", err)
+	}
+
+	condition := f.condition(t, unsupportedOverridesConditionType)
+	expectedMessage := "The following objects are not managed by the operator: " +
+		"ValidatingWebhookConfiguration.admissionregistration.k8s.io lws-validating-webhook-configuration, " +
+		"ClusterRole.rbac.authorization.k8s.io lws-hotfix-role, " +
+		"Deployment.apps openshift-lws-operator/lws-controller-manager"
+	if condition == nil || condition.Status != operatorv1.ConditionTrue || condition.Message != expectedMessage {
+		t.Errorf("expected UnsupportedOverrides to list the unmanaged objects, got %+v", condition)
+	}
+
+	f.updateOperatorSpec(t, func(spec *leaderworkersetoperatorv1.LeaderWorkerSetOperatorSpec) {
+		spec.Overrides = nil
+	})
+	f.converge(t)
+	if _, err := f.dynamicClient.Resource(schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}).Namespace(testNamespace).Get(ctx, operandName, metav1.GetOptions{}); err != nil {
+		t.Errorf("expected the Deployment to be applied once it is managed again, got %v", err)
+	}
+	if condition := f.condition(t, unsupportedOverridesConditionType); condition == nil || condition.Status != operatorv1.ConditionFalse {
+		t.Errorf("expected UnsupportedOverrides=False, got %+v", condition)
+	}
+}
@@ -0,0 +1,74 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/library-go/pkg/config/clusterstatus"
+	"github.com/openshift/library-go/pkg/controller/controllercmd"
+)
+
+// platform is the kind of cluster the operator runs on.
+type platform string
+
+const (
+	// platformOpenShift enables the integrations with the OpenShift cluster monitoring stack.
+	platformOpenShift platform = "OpenShift"
+	// platformKubernetes is any cluster that does not serve the OpenShift config API, e.g. kind.
+	platformKubernetes platform = "Kubernetes"
+)
+
+// infrastructureGVK is served by every OpenShift cluster and never by vanilla Kubernetes.
+var infrastructureGVK = schema.GroupVersionKind{Group: configv1.GroupName, Version: "v1", Kind: "Infrastructure"}
+
+// detectPlatform checks whether the API server serves the OpenShift config API.
+func detectPlatform(discoveryClient discovery.DiscoveryInterface) (platform, error) {
+	found, err := isResourceRegistered(discoveryClient, infrastructureGVK)
+	if err != nil {
+		return "", fmt.Errorf("unable to detect the platform: %w", err)
+	}
+	if found {
+		return platformOpenShift, nil
+	}
+	return platformKubernetes, nil
+}
+
+// IsKubernetesPlatform reports whether the cluster does not serve the OpenShift APIs, for
+// RenderOptions.Kubernetes.
+func IsKubernetesPlatform(discoveryClient discovery.DiscoveryInterface) (bool, error) {
+	p, err := detectPlatform(discoveryClient)
+	return p == platformKubernetes, err
+}
+
+// PlatformTopologyDetector reads the control plane topology from the OpenShift Infrastructure config
+// and falls back to highly available leader election values on clusters without the config API,
+// instead of failing the lookup on every start.
+type PlatformTopologyDetector struct{}
+
+var _ controllercmd.TopologyDetector = PlatformTopologyDetector{}
+
+func (PlatformTopologyDetector) DetectTopology(ctx context.Context, restConfig *rest.Config) (configv1.TopologyMode, error) {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		return "", err
+	}
+	p, err := detectPlatform(discoveryClient)
+	if err != nil {
+		return "", err
+	}
+	if p != platformOpenShift {
+		klog.V(2).Infof("%s is not served, using highly available control plane topology", infrastructureGVK.GroupVersion())
+		return configv1.HighlyAvailableTopologyMode, nil
+	}
+	infraStatus, err := clusterstatus.GetClusterInfraStatus(ctx, restConfig)
+	if err != nil {
+		return "", err
+	}
+	return infraStatus.ControlPlaneTopology, nil
+}
@@ -0,0 +1,83 @@
+package operator
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestDetectPlatform(t *testing.T) {
+	tests := []struct {
+		name      string
+		resources []*metav1.APIResourceList
+		expected  platform
+	}{
+		{
+			name: "OpenShift",
+			resources: []*metav1.APIResourceList{{
+				GroupVersion: "config.openshift.io/v1",
+				APIResources: []metav1.APIResource{{Name: "infrastructures", Kind: "Infrastructure"}},
+			}},
+			expected: platformOpenShift,
+		},
+		{
+			name:     "Kubernetes",
+			expected: platformKubernetes,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			discovery := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: tt.resources}}
+			p, err := detectPlatform(discovery)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, p)
+			}
+		})
+	}
+}
+
+func TestKubernetesPlatformSkipsOpenShiftMonitoring(t *testing.T) {
+	f := newTargetConfigReconcilerFixture(t)
+	f.reconciler.platform = platformKubernetes
+	f.reconciler.staticResources = staticResourcesForPlatform(f.reconciler.staticResources, platformKubernetes)
+	f.discovery.Resources = append(f.discovery.Resources, &metav1.APIResourceList{
+		GroupVersion: serviceMonitorGVK.GroupVersion().String(),
+		APIResources: []metav1.APIResource{{Name: "servicemonitors", Kind: serviceMonitorGVK.Kind, Namespaced: true}},
+	})
+
+	if err := f.reconciler.sync(context.Background(), f.syncCtx); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, resource := range []string{"roles", "rolebindings"} {
+		_, err := f.dynamicClient.Resource(schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: resource}).Namespace(testNamespace).Get(context.Background(), "lws-prometheus-k8s", metav1.GetOptions{})
+		if err == nil {
+			t.Errorf("expected the openshift-monitoring %s not to be applied", resource)
+		}
+	}
+
+	serviceMonitor, err := f.dynamicClient.Resource(serviceMonitorGVK.GroupVersion().WithResource("servicemonitors")).Namespace(testNamespace).
+		Get(context.Background(), "lws-controller-manager-metrics-monitor", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	endpoints, _, _ := unstructured.NestedSlice(serviceMonitor.Object, "spec", "endpoints")
+	if len(endpoints) != 1 {
+		t.Fatalf("expected one endpoint, got %v", endpoints)
+	}
+	tlsConfig, _, _ := unstructured.NestedMap(endpoints[0].(map[string]interface{}), "tlsConfig")
+	if _, ok := tlsConfig["certFile"]; ok {
+		t.Errorf("expected no openshift-monitoring client certificate, got %v", tlsConfig)
+	}
+	if name, _, _ := unstructured.NestedString(tlsConfig, "keySecret", "name"); name != MetricsCertificateSecretName {
+		t.Errorf("expected the key to be read from %s, got %v", MetricsCertificateSecretName, tlsConfig)
+	}
+}
@@ -0,0 +1,53 @@
+package operator
+
+import (
+	configv1 "github.com/openshift/api/config/v1"
+
+	leaderworkersetapiv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+	"github.com/openshift/lws-operator/pkg/operator/operatorclient"
+)
+
+// relatedObjects lists the operator CR, the operator and operand namespaces, the RoleBinding of a
+// dedicated operand namespace and the static resources in apply order, for oc adm inspect. It is built
+// from the registry on every sync, so manifests added or removed with an operator version and objects
+// taken out of management by spec.overrides are reflected.
+// The monitoring resources are only listed while the Prometheus Operator API is served.
+func relatedObjects(resources []staticResource, rc *renderContext, operatorNamespace string, monitoringAvailable bool) []configv1.ObjectReference {
+	objects := []configv1.ObjectReference{
+		{Group: "operator.openshift.io", Resource: "leaderworkersetoperators", Name: rc.operator.Name},
+		{Resource: "namespaces", Name: operatorNamespace},
+	}
+	if rc.namespace != operatorNamespace {
+		objects = append(objects,
+			configv1.ObjectReference{Resource: "namespaces", Name: rc.namespace},
+			configv1.ObjectReference{Group: roleBindingsGVR.Group, Resource: roleBindingsGVR.Resource, Namespace: rc.namespace, Name: operandNamespaceRoleBinding},
+		)
+	}
+	for _, group := range resourceGroupOrder {
+		if group == monitoringResourceGroup && !monitoringAvailable {
+			continue
+		}
+		for _, resource := range staticResourcesInGroup(resources, group) {
+			namespace := resource.renderedNamespace(rc)
+			if rc.unmanaged(resource.gvk.GroupKind(), namespace, resource.name) {
+				continue
+			}
+			gvr := resource.gvr()
+			objects = append(objects, configv1.ObjectReference{
+				Group:     gvr.Group,
+				Resource:  gvr.Resource,
+				Namespace: namespace,
+				Name:      resource.name,
+			})
+		}
+	}
+	return objects
+}
+
+// setRelatedObjects replaces status.relatedObjects.
+func setRelatedObjects(objects []configv1.ObjectReference) operatorclient.UpdateStatusFunc {
+	return func(status *leaderworkersetapiv1.LeaderWorkerSetOperatorStatus) error {
+		status.RelatedObjects = objects
+		return nil
+	}
+}
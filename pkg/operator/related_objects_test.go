@@ -0,0 +1,96 @@
+package operator
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	leaderworkersetoperatorv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+	"github.com/openshift/lws-operator/pkg/operator/operatorclient"
+)
+
+func TestRelatedObjects(t *testing.T) {
+	f := newTargetConfigReconcilerFixture(t)
+	f.discovery.Resources = append(f.discovery.Resources, &metav1.APIResourceList{
+		GroupVersion: serviceMonitorGVK.GroupVersion().String(),
+		APIResources: []metav1.APIResource{{Name: "servicemonitors", Kind: serviceMonitorGVK.Kind, Namespaced: true}},
+	})
+	f.converge(t)
+
+	relatedObjects := func() map[configv1.ObjectReference]bool {
+		t.Helper()
+		operator, err := f.reconciler.leaderWorkerSetOperatorClient.OperatorClient.LeaderWorkerSetOperators().Get(context.Background(), operatorclient.OperatorConfigName, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		objects := map[configv1.ObjectReference]bool{}
+		for _, object := range operator.Status.RelatedObjects {
+			if objects[object] {
+				t.Errorf("%v is listed twice", object)
+			}
+			objects[object] = true
+		}
+		return objects
+	}
+
+	serviceMonitor := configv1.ObjectReference{Group: "monitoring.coreos.com", Resource: "servicemonitors", Namespace: testNamespace, Name: "lws-controller-manager-metrics-monitor"}
+	webhook := configv1.ObjectReference{Group: "admissionregistration.k8s.io", Resource: "validatingwebhookconfigurations", Name: "lws-validating-webhook-configuration"}
+	objects := relatedObjects()
+	for _, expected := range []configv1.ObjectReference{
+		{Group: "operator.openshift.io", Resource: "leaderworkersetoperators", Name: operatorclient.OperatorConfigName},
+		{Resource: "namespaces", Name: testNamespace},
+		{Group: "rbac.authorization.k8s.io", Resource: "clusterroles", Name: "lws-manager-role"},
+		{Group: "apiextensions.k8s.io", Resource: "customresourcedefinitions", Name: "leaderworkersets.leaderworkerset.x-k8s.io"},
+		webhook,
+		{Group: "cert-manager.io", Resource: "issuers", Namespace: testNamespace, Name: "lws-selfsigned-issuer"},
+		{Group: "cert-manager.io", Resource: "certificates", Namespace: testNamespace, Name: "lws-serving-cert"},
+		serviceMonitor,
+		{Group: "apps", Resource: "deployments", Namespace: testNamespace, Name: operandName},
+	} {
+		if !objects[expected] {
+			t.Errorf("expected %v in status.relatedObjects", expected)
+		}
+	}
+	if len(objects) != len(f.reconciler.staticResources)+2 {
+		t.Errorf("expected the operator CR, the namespace and %d static resources, got %d objects", len(f.reconciler.staticResources), len(objects))
+	}
+
+	f.updateOperatorSpec(t, func(spec *leaderworkersetoperatorv1.LeaderWorkerSetOperatorSpec) {
+		spec.Overrides = []leaderworkersetoperatorv1.ComponentOverride{
+			{Group: webhook.Group, Kind: "ValidatingWebhookConfiguration", Name: webhook.Name, Unmanaged: true},
+		}
+	})
+	f.converge(t)
+	if relatedObjects()[webhook] {
+		t.Errorf("expected the unmanaged webhook configuration to be removed from status.relatedObjects")
+	}
+
+	// the Prometheus Operator gets uninstalled
+	f.discovery.Resources = f.discovery.Resources[:len(f.discovery.Resources)-1]
+	f.reconciler.discoveryClient.Invalidate()
+	f.converge(t)
+	if relatedObjects()[serviceMonitor] {
+		t.Errorf("expected the ServiceMonitor to be removed from status.relatedObjects without the Prometheus Operator API")
+	}
+}
+
+func TestRelatedObjectsOperandNamespace(t *testing.T) {
+	rc := testRenderContext()
+	rc.namespace = "lws-operand"
+	roleBinding := configv1.ObjectReference{Group: "rbac.authorization.k8s.io", Resource: "rolebindings", Namespace: "lws-operand", Name: operandNamespaceRoleBinding}
+
+	objects := relatedObjects(nil, rc, testNamespace, false)
+	if !slices.Contains(objects, roleBinding) || !slices.Contains(objects, configv1.ObjectReference{Resource: "namespaces", Name: "lws-operand"}) {
+		t.Errorf("expected the operand namespace and its RoleBinding in status.relatedObjects, got %v", objects)
+	}
+
+	rc.namespace = testNamespace
+	for _, object := range relatedObjects(nil, rc, testNamespace, false) {
+		if object.Resource == "rolebindings" && object.Name == operandNamespaceRoleBinding {
+			t.Errorf("expected no operand namespace RoleBinding in the operator namespace, got %v", object)
+		}
+	}
+}
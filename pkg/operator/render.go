@@ -0,0 +1,224 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/kubernetes"
+
+	leaderworkersetapiv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+	"github.com/openshift/lws-operator/pkg/version"
+)
+
+const (
+	// DefaultOperatorNamespace is the namespace the operator is installed to by default.
+	DefaultOperatorNamespace = operatorNamespace
+	// OperandDeploymentName is the name of the operand Deployment and the prefix of its pods.
+	OperandDeploymentName = operandName
+)
+
+// OperandNamespace returns the namespace the operand is installed to for an operator CR.
+func OperandNamespace(operator *leaderworkersetapiv1.LeaderWorkerSetOperator, operatorNamespace string) string {
+	return operandNamespace(operator, operatorNamespace)
+}
+
+// ManifestNames returns the names of the embedded manifests of a kind, e.g. the CRDs the operator applies.
+func ManifestNames(kind string) ([]string, error) {
+	resources, err := loadStaticResources()
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, resource := range resources {
+		if resource.gvk.Kind == kind {
+			names = append(names, resource.name)
+		}
+	}
+	return names, nil
+}
+
+// resourceGroupOrder is the order in which the target config reconciler applies the resource groups.
+var resourceGroupOrder = []string{
+	namespaceResourceGroup,
+	rbacResourceGroup,
+	certificatesResourceGroup,
+	crdsResourceGroup,
+	webhooksResourceGroup,
+	monitoringResourceGroup,
+	deploymentResourceGroup,
+}
+
+// RenderOptions are the inputs of RenderManifests.
+type RenderOptions struct {
+	// Operator is the operator CR the manifests are rendered for.
+	Operator *leaderworkersetapiv1.LeaderWorkerSetOperator
+	// OperatorNamespace is the namespace of the operator, the operand namespace defaults to it.
+	OperatorNamespace string
+	// OperandImage is the operand image the operator was released with, RELATED_IMAGE_OPERAND_IMAGE.
+	OperandImage string
+	// Kubernetes renders the manifests for clusters without the OpenShift APIs.
+	Kubernetes bool
+	// MonitoringAvailable renders the monitoring resource group, which the reconciler only applies when
+	// the cluster serves the Prometheus Operator API.
+	MonitoringAvailable bool
+	// Now decides whether the debug window of the operator CR is open.
+	Now time.Time
+	// PodTemplateAnnotations are the resource versions and content hashes the reconciler records in the
+	// operand pod template to roll the pods; they can only be read from a cluster and are omitted if unset.
+	PodTemplateAnnotations map[string]string
+}
+
+// RenderedManifest is a manifest as the target config reconciler applies it.
+type RenderedManifest struct {
+	// Group is the resource group the manifest is applied in, e.g. RBAC.
+	Group  string
+	Object *unstructured.Unstructured
+}
+
+// RenderManifests renders the operand manifests the target config reconciler applies for the operator
+// CR, in apply order, without a cluster. Objects taken out of management by spec.overrides are omitted.
+// Configurations the reconciler reports as degraded, e.g. an operand image outside of the allowed
+// registries or failing manifest patches, are returned as errors.
+func RenderManifests(options RenderOptions) ([]RenderedManifest, error) {
+	operator := options.Operator
+	if operator == nil {
+		return nil, fmt.Errorf("an operator configuration is required")
+	}
+	p := platformOpenShift
+	if options.Kubernetes {
+		p = platformKubernetes
+	}
+	resources, err := loadStaticResources()
+	if err != nil {
+		return nil, err
+	}
+	resources = staticResourcesForPlatform(resources, p)
+
+	image, err := resolveOperandImage(operator.Spec.Operand, options.OperandImage)
+	if err != nil {
+		return nil, err
+	}
+	rc := &renderContext{
+		namespace:         operandNamespace(operator, options.OperatorNamespace),
+		operatorNamespace: options.OperatorNamespace,
+		targetImage:       image,
+		operatorVersion:   operatorVersionLabelValue(version.Get().GitVersion),
+		operator:          operator,
+		ownerReference: metav1.OwnerReference{
+			APIVersion: "operator.openshift.io/v1",
+			Kind:       "LeaderWorkerSetOperator",
+			Name:       operator.Name,
+			UID:        operator.UID,
+		},
+		specAnnotations: make(map[string]string),
+		platform:        p,
+		debug:           activeDebug(operator.Spec.Debug, options.Now),
+		configPatches:   parseConfigPatches(operator.Spec.UnsupportedConfigOverrides),
+	}
+	maps.Copy(rc.specAnnotations, options.PodTemplateAnnotations)
+	rc.configPatches.dryRun(resources, rc)
+	if failures := rc.configPatches.failures(); len(failures) > 0 {
+		return nil, fmt.Errorf("spec.unsupportedConfigOverrides cannot be applied:\n%s", strings.Join(failures, "\n"))
+	}
+	if _, err := operandLogArgs(operandLogLevel(rc), operator.Spec.OperandLogging); err != nil {
+		return nil, err
+	}
+
+	var manifests []RenderedManifest
+	if !rc.unmanaged(schema.GroupKind{Kind: "Namespace"}, "", rc.namespace) {
+		manifests = append(manifests, RenderedManifest{Group: namespaceResourceGroup, Object: renderOperandNamespace(rc)})
+	}
+	if rc.namespace != rc.operatorNamespace {
+		manifests = append(manifests, RenderedManifest{Group: namespaceResourceGroup, Object: renderOperandNamespaceRoleBinding(rc)})
+	}
+	var errs []error
+	for _, group := range resourceGroupOrder {
+		if group == monitoringResourceGroup && !options.MonitoringAvailable {
+			continue
+		}
+		for _, resource := range staticResourcesInGroup(resources, group) {
+			if rc.unmanaged(resource.gvk.GroupKind(), resource.renderedNamespace(rc), resource.name) {
+				continue
+			}
+			required, err := resource.render(rc)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			obj, err := toAppliedObject(required)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("unable to convert %s %s: %w", resource.gvk.Kind, resource.name, err))
+				continue
+			}
+			manifests = append(manifests, RenderedManifest{Group: group, Object: obj})
+		}
+	}
+	if len(errs) > 0 {
+		return nil, utilerrors.NewAggregate(errs)
+	}
+	return manifests, nil
+}
+
+// LivePodTemplateAnnotations reads the resource versions and content hashes the reconciler records in the
+// operand pod template from the cluster, for RenderOptions.PodTemplateAnnotations. Certificate secrets and
+// ConfigMaps that do not exist yet are left out, references from spec.operand that do not exist are errors
+// as in the reconciler.
+func LivePodTemplateAnnotations(ctx context.Context, kubeClient kubernetes.Interface, options RenderOptions) (map[string]string, error) {
+	if options.Operator == nil {
+		return nil, fmt.Errorf("an operator configuration is required")
+	}
+	namespace := operandNamespace(options.Operator, options.OperatorNamespace)
+	annotations := map[string]string{}
+
+	for _, secretName := range []string{WebhookCertificateSecretName, MetricsCertificateSecretName} {
+		secret, err := kubeClient.CoreV1().Secrets(namespace).Get(ctx, secretName, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		annotations["secrets/"+secret.Name] = secret.ResourceVersion
+	}
+
+	referenceAnnotations, err := operandReferenceAnnotations(operandReferenceGetters{
+		secret: func(namespace, name string) (*corev1.Secret, error) {
+			return kubeClient.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+		},
+		configMap: func(namespace, name string) (*corev1.ConfigMap, error) {
+			return kubeClient.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+		},
+	}, namespace, options.Operator.Spec.Operand)
+	if err != nil {
+		return nil, err
+	}
+	maps.Copy(annotations, referenceAnnotations)
+
+	resources, err := loadStaticResources()
+	if err != nil {
+		return nil, err
+	}
+	for _, resource := range staticResourcesInGroup(resources, deploymentResourceGroup) {
+		if resource.gvk.Kind != "ConfigMap" {
+			continue
+		}
+		configMap, err := kubeClient.CoreV1().ConfigMaps(namespace).Get(ctx, resource.name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		annotations["configmaps/"+configMap.Name] = configMap.ResourceVersion
+	}
+	return annotations, nil
+}
@@ -0,0 +1,205 @@
+package operator
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	leaderworkersetoperatorv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+	"github.com/openshift/lws-operator/pkg/operator/operatorclient"
+)
+
+// TestRenderManifestsMatchesReconciler checks that the offline render produces the objects the
+// reconciler applies.
+func TestRenderManifestsMatchesReconciler(t *testing.T) {
+	f := newTargetConfigReconcilerFixture(t)
+	f.updateOperatorSpec(t, func(spec *leaderworkersetoperatorv1.LeaderWorkerSetOperatorSpec) {
+		spec.NodePlacement = &leaderworkersetoperatorv1.NodePlacement{NodeSelector: map[string]string{"node-role.kubernetes.io/infra": ""}}
+		spec.UnsupportedConfigOverrides = runtime.RawExtension{Raw: []byte(`{"patches":[{"target":{"kind":"Service","name":"lws-webhook-service"},"type":"StrategicMerge","patch":{"metadata":{"labels":{"example.com/team":"ml"}}}}]}`)}
+	})
+	f.converge(t)
+
+	operator, err := f.reconciler.operatorLister.Get(operatorclient.OperatorConfigName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := t.Context()
+	live := func(manifest RenderedManifest) *unstructured.Unstructured {
+		resource := findStaticResource(t, f.reconciler.staticResources, manifest.Object.GetKind(), manifest.Object.GetName())
+		obj, err := f.dynamicClient.Resource(resource.gvr()).Namespace(manifest.Object.GetNamespace()).Get(ctx, manifest.Object.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return nil
+		}
+		return obj
+	}
+	// the reconciler records the resource versions of the secrets and ConfigMaps the pods use
+	rolloutAnnotations := map[string]string{}
+	for key, value := range f.operandDeployment(t).Spec.Template.Annotations {
+		if strings.HasPrefix(key, "secrets/") || strings.HasPrefix(key, "configmaps/") {
+			rolloutAnnotations[key] = value
+		}
+	}
+
+	manifests, err := RenderManifests(RenderOptions{
+		Operator:               operator,
+		OperatorNamespace:      testNamespace,
+		OperandImage:           f.reconciler.targetImage,
+		Now:                    time.Now(),
+		PodTemplateAnnotations: rolloutAnnotations,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifests[0].Object.GetKind() != "Namespace" || manifests[len(manifests)-1].Object.GetKind() != "Deployment" {
+		t.Errorf("expected the manifests in apply order, got %s first and %s last", manifests[0].Object.GetKind(), manifests[len(manifests)-1].Object.GetKind())
+	}
+
+	for _, manifest := range manifests[1:] {
+		// the monitoring group is not rendered, as the fixture does not serve the Prometheus Operator API
+		actual := live(manifest)
+		if actual == nil {
+			t.Errorf("%s %s was rendered but not applied", manifest.Object.GetKind(), manifest.Object.GetName())
+			continue
+		}
+		actual = actual.DeepCopy()
+		actual.SetUID("")
+		actual.SetResourceVersion("")
+		labels := manifest.Object.GetLabels()
+		labels[operatorVersionLabel] = f.reconciler.operatorVersion
+		manifest.Object.SetLabels(labels)
+		// the applied objects went through JSON, which turns the integers into float64
+		content, err := json.Marshal(manifest.Object)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := &unstructured.Unstructured{}
+		if err := json.Unmarshal(content, &expected.Object); err != nil {
+			t.Fatal(err)
+		}
+		if !equality.Semantic.DeepEqual(expected.Object, actual.Object) {
+			t.Errorf("rendered %s %s differs from the applied object:\nrendered: %v\napplied:  %v", manifest.Object.GetKind(), manifest.Object.GetName(), expected.Object, actual.Object)
+		}
+	}
+}
+
+// TestRenderManifestsOperandNamespace checks that the RoleBinding the reconciler applies in a dedicated
+// operand namespace is rendered after the Namespace.
+func TestRenderManifestsOperandNamespace(t *testing.T) {
+	f := newTargetConfigReconcilerFixture(t)
+	ctx := t.Context()
+	rc := testRenderContext()
+	rc.namespace = "lws-operand"
+	rc.operatorNamespace = testNamespace
+	rc.generations = newAppliedGenerations(nil)
+	if err := f.reconciler.manageOperandNamespace(ctx, rc); err != nil {
+		t.Fatal(err)
+	}
+
+	operator := &leaderworkersetoperatorv1.LeaderWorkerSetOperator{}
+	operator.Name = operatorclient.OperatorConfigName
+	operator.Spec.OperandNamespace = rc.namespace
+	manifests, err := RenderManifests(RenderOptions{
+		Operator:          operator,
+		OperatorNamespace: testNamespace,
+		OperandImage:      f.reconciler.targetImage,
+		Now:               time.Now(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rendered := manifests[1].Object
+	if manifests[0].Object.GetKind() != "Namespace" || rendered.GetKind() != "RoleBinding" || rendered.GetNamespace() != rc.namespace || manifests[1].Group != namespaceResourceGroup {
+		t.Fatalf("expected the operand namespace RoleBinding after the Namespace, got %s %s/%s", rendered.GetKind(), rendered.GetNamespace(), rendered.GetName())
+	}
+	applied, err := f.dynamicClient.Resource(roleBindingsGVR).Namespace(rc.namespace).Get(ctx, rendered.GetName(), metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rendered.GetLabels()[managedByLabel] != managedByLabelValue || rendered.GetLabels()[managedByLabel] != applied.GetLabels()[managedByLabel] {
+		t.Errorf("expected the rendered and applied RoleBinding to be labeled as managed, got %v and %v", rendered.GetLabels(), applied.GetLabels())
+	}
+	for _, field := range []string{"roleRef", "subjects"} {
+		if !equality.Semantic.DeepEqual(rendered.Object[field], applied.Object[field]) {
+			t.Errorf("rendered %s differs from the applied one:\nrendered: %v\napplied:  %v", field, rendered.Object[field], applied.Object[field])
+		}
+	}
+
+	// the Role of the operator covers its own namespace
+	operator.Spec.OperandNamespace = ""
+	manifests, err = RenderManifests(RenderOptions{Operator: operator, OperatorNamespace: testNamespace, OperandImage: f.reconciler.targetImage})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, manifest := range manifests {
+		if manifest.Object.GetKind() == "RoleBinding" && manifest.Object.GetName() == operandNamespaceRoleBinding && manifest.Group == namespaceResourceGroup {
+			t.Errorf("expected no operand namespace RoleBinding in the operator namespace")
+		}
+	}
+}
+
+func TestRenderManifests(t *testing.T) {
+	operator := &leaderworkersetoperatorv1.LeaderWorkerSetOperator{}
+	operator.Name = operatorclient.OperatorConfigName
+	operator.Spec.OperandNamespace = "lws-system"
+	operator.Spec.Overrides = []leaderworkersetoperatorv1.ComponentOverride{
+		{Group: "admissionregistration.k8s.io", Kind: "ValidatingWebhookConfiguration", Name: "lws-validating-webhook-configuration", Unmanaged: true},
+	}
+
+	manifests, err := RenderManifests(RenderOptions{
+		Operator:            operator,
+		OperatorNamespace:   DefaultOperatorNamespace,
+		OperandImage:        "quay.io/example/lws:v1",
+		Kubernetes:          true,
+		MonitoringAvailable: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	serviceMonitors := 0
+	for _, manifest := range manifests {
+		obj := manifest.Object
+		switch {
+		case obj.GetKind() == "ServiceMonitor":
+			serviceMonitors++
+		case obj.GetKind() == "ValidatingWebhookConfiguration":
+			t.Errorf("expected the unmanaged webhook configuration to be omitted")
+		case obj.GetName() == "lws-prometheus-k8s":
+			t.Errorf("expected the OpenShift monitoring RBAC to be omitted on Kubernetes")
+		case obj.GetKind() == "Namespace" && obj.GetName() != "lws-system":
+			t.Errorf("expected the operand namespace, got %s", obj.GetName())
+		case obj.GetKind() == "Deployment" && obj.GetNamespace() != "lws-system":
+			t.Errorf("expected the Deployment in the operand namespace, got %s", obj.GetNamespace())
+		}
+	}
+
+	if serviceMonitors != 1 {
+		t.Errorf("expected the ServiceMonitor to be rendered when the monitoring API is served, got %d", serviceMonitors)
+	}
+
+	// the reconciler skips the monitoring group without the Prometheus Operator API
+	manifests, err = RenderManifests(RenderOptions{
+		Operator:          operator,
+		OperatorNamespace: DefaultOperatorNamespace,
+		OperandImage:      "quay.io/example/lws:v1",
+		Kubernetes:        true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, manifest := range manifests {
+		if manifest.Group == monitoringResourceGroup {
+			t.Errorf("expected the monitoring group to be omitted, got %s %s", manifest.Object.GetKind(), manifest.Object.GetName())
+		}
+	}
+
+	operator.Spec.Operand = &leaderworkersetoperatorv1.OperandSpec{Image: "quay.io/example/lws:v2"}
+	if _, err := RenderManifests(RenderOptions{Operator: operator, OperatorNamespace: DefaultOperatorNamespace}); err == nil || !strings.Contains(err.Error(), "spec.operand.image") {
+		t.Errorf("expected the unpinned operand image to be rejected, got %v", err)
+	}
+}
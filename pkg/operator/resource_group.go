@@ -0,0 +1,110 @@
+package operator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+// resourceGroup is a set of operand resources that are reconciled together. Every group is applied
+// on each sync regardless of failures in the other groups and reports its own <name>Degraded
+// condition, so that e.g. missing monitoring CRDs do not prevent the Deployment from being updated.
+type resourceGroup struct {
+	name  string
+	apply func(ctx context.Context) error
+}
+
+func (g resourceGroup) conditionType() string {
+	return g.name + operatorv1.OperatorStatusTypeDegraded
+}
+
+func (g resourceGroup) degradedCondition(err error) operatorv1.OperatorCondition {
+	if err == nil {
+		return operatorv1.OperatorCondition{
+			Type:   g.conditionType(),
+			Status: operatorv1.ConditionFalse,
+			Reason: "AsExpected",
+		}
+	}
+
+	reason := "SyncError"
+	if degradedErr := findDegradedError(err); degradedErr != nil {
+		reason = degradedErr.reason
+	}
+	return operatorv1.OperatorCondition{
+		Type:    g.conditionType(),
+		Status:  operatorv1.ConditionTrue,
+		Reason:  reason,
+		Message: err.Error(),
+	}
+}
+
+// degradedError carries a specific reason for the degraded condition of a resource group.
+type degradedError struct {
+	reason string
+	err    error
+}
+
+func (e *degradedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *degradedError) Unwrap() error {
+	return e.err
+}
+
+// findDegradedError returns the first degradedError in the error chain, looking into aggregated errors.
+func findDegradedError(err error) *degradedError {
+	var degradedErr *degradedError
+	if errors.As(err, &degradedErr) {
+		return degradedErr
+	}
+	var aggregate utilerrors.Aggregate
+	if errors.As(err, &aggregate) {
+		for _, err := range aggregate.Errors() {
+			if degradedErr := findDegradedError(err); degradedErr != nil {
+				return degradedErr
+			}
+		}
+	}
+	return nil
+}
+
+// constructDegradedCondition summarizes the degraded resource group conditions into the operator Degraded condition.
+func constructDegradedCondition(degradedGroups []operatorv1.OperatorCondition) operatorv1.OperatorCondition {
+	if len(degradedGroups) == 0 {
+		return operatorv1.OperatorCondition{
+			Type:   operatorv1.OperatorStatusTypeDegraded,
+			Status: operatorv1.ConditionFalse,
+			Reason: "AsExpected",
+		}
+	}
+
+	reason := "MultipleResourceGroupsDegraded"
+	if len(degradedGroups) == 1 {
+		reason = degradedGroups[0].Type
+	}
+	messages := make([]string, 0, len(degradedGroups))
+	for _, condition := range degradedGroups {
+		messages = append(messages, fmt.Sprintf("%s: %s", condition.Type, condition.Message))
+	}
+	return operatorv1.OperatorCondition{
+		Type:    operatorv1.OperatorStatusTypeDegraded,
+		Status:  operatorv1.ConditionTrue,
+		Reason:  reason,
+		Message: strings.Join(messages, "\n"),
+	}
+}
+
+// removeSyncDegradedCondition removes the TargetConfigControllerDegraded condition that releases
+// before the resource groups set from the sync error, which repeated every group error.
+func removeSyncDegradedCondition(status *operatorv1.OperatorStatus) error {
+	v1helpers.RemoveOperatorCondition(&status.Conditions, "TargetConfigControllerDegraded")
+	return nil
+}
@@ -0,0 +1,60 @@
+package operator
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+)
+
+func TestResourceGroupDegradedCondition(t *testing.T) {
+	group := resourceGroup{name: "Webhooks"}
+
+	condition := group.degradedCondition(nil)
+	if condition.Type != "WebhooksDegraded" || condition.Status != operatorv1.ConditionFalse {
+		t.Fatalf("unexpected condition: %+v", condition)
+	}
+
+	condition = group.degradedCondition(errors.New("boom"))
+	if condition.Status != operatorv1.ConditionTrue || condition.Reason != "SyncError" || condition.Message != "boom" {
+		t.Fatalf("unexpected condition: %+v", condition)
+	}
+
+	condition = group.degradedCondition(fmt.Errorf("wrapped: %w", &degradedError{reason: "MissingDependency", err: errors.New("cert-manager missing")}))
+	if condition.Reason != "MissingDependency" {
+		t.Fatalf("expected MissingDependency reason, got %+v", condition)
+	}
+
+	condition = group.degradedCondition(utilerrors.NewAggregate([]error{
+		errors.New("boom"),
+		fmt.Errorf("unable to apply Deployment: %w", &degradedError{reason: "ApplyConflict", err: errors.New(".spec.replicas (kubectl-edit)")}),
+	}))
+	if condition.Reason != "ApplyConflict" {
+		t.Fatalf("expected ApplyConflict reason, got %+v", condition)
+	}
+}
+
+func TestConstructDegradedCondition(t *testing.T) {
+	condition := constructDegradedCondition(nil)
+	if condition.Status != operatorv1.ConditionFalse || condition.Reason != "AsExpected" {
+		t.Fatalf("unexpected condition: %+v", condition)
+	}
+
+	monitoring := resourceGroup{name: "Monitoring"}.degradedCondition(errors.New("no matches for kind ServiceMonitor"))
+	condition = constructDegradedCondition([]operatorv1.OperatorCondition{monitoring})
+	if condition.Status != operatorv1.ConditionTrue || condition.Reason != "MonitoringDegraded" {
+		t.Fatalf("unexpected condition: %+v", condition)
+	}
+
+	crds := resourceGroup{name: "CRDs"}.degradedCondition(errors.New("conflict"))
+	condition = constructDegradedCondition([]operatorv1.OperatorCondition{monitoring, crds})
+	if condition.Reason != "MultipleResourceGroupsDegraded" {
+		t.Fatalf("unexpected condition: %+v", condition)
+	}
+	if condition.Message != "MonitoringDegraded: no matches for kind ServiceMonitor\nCRDsDegraded: conflict" {
+		t.Fatalf("unexpected message: %q", condition.Message)
+	}
+}
@@ -6,18 +6,26 @@ import (
 	"time"
 
 	apiextclientv1 "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apiextensionsinformers "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
 
 	"github.com/openshift/library-go/pkg/controller/controllercmd"
+	"github.com/openshift/library-go/pkg/controller/factory"
 	"github.com/openshift/library-go/pkg/operator/loglevel"
 	"github.com/openshift/library-go/pkg/operator/v1helpers"
 
 	operatorconfigclient "github.com/openshift/lws-operator/pkg/generated/clientset/versioned"
 	operatorclientinformers "github.com/openshift/lws-operator/pkg/generated/informers/externalversions"
 	"github.com/openshift/lws-operator/pkg/operator/operatorclient"
+	"github.com/openshift/lws-operator/pkg/version"
 )
 
 const (
@@ -25,7 +33,21 @@ const (
 	operandName       = "lws-controller-manager"
 )
 
-func RunOperator(ctx context.Context, cc *controllercmd.ControllerContext) error {
+// restartOperator exits the process so the operator is restarted with new informers, the same way
+// library-go operators react to feature gate changes.
+func restartOperator(reason string) {
+	klog.Warningf("Restarting the operator: %s", reason)
+	klog.Flush()
+	os.Exit(0)
+}
+
+// Options configures the controllers started by RunOperator.
+type Options struct {
+	// CreateDefaultConfig creates the cluster LeaderWorkerSetOperator when it does not exist.
+	CreateDefaultConfig bool
+}
+
+func RunOperator(ctx context.Context, cc *controllercmd.ControllerContext, options Options) error {
 	kubeClient, err := kubernetes.NewForConfig(cc.ProtoKubeConfig)
 	if err != nil {
 		return err
@@ -45,6 +67,15 @@ func RunOperator(ctx context.Context, cc *controllercmd.ControllerContext) error
 	if err != nil {
 		return err
 	}
+	// discovery results are cached and invalidated by the target config reconciler on CRD changes
+	cachedDiscoveryClient := memory.NewMemCacheClient(discoveryClient)
+
+	// OpenShift specific integrations are disabled on clusters without the OpenShift APIs, e.g. kind
+	platform, err := detectPlatform(cachedDiscoveryClient)
+	if err != nil {
+		return err
+	}
+	klog.Infof("Detected platform %s", platform)
 
 	operatorConfigClient, err := operatorconfigclient.NewForConfig(cc.KubeConfig)
 	if err != nil {
@@ -54,14 +85,33 @@ func RunOperator(ctx context.Context, cc *controllercmd.ControllerContext) error
 
 	namespace := cc.OperatorNamespace
 	if namespace == "openshift-config-managed" {
-		// we need to fall back to our default namespace rather than library-go's when running outside the cluster
+		// we need to fall back to our default namespace rather than library-go's when running outside the
+		// cluster, on any platform; use --namespace to run against another one
 		namespace = operatorNamespace
 	}
 
+	// the operand namespace is read once, the operator restarts when it changes
+	leaderWorkerSetOperator, err := operatorConfigClient.OpenShiftOperatorV1().LeaderWorkerSetOperators().Get(ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		leaderWorkerSetOperator = nil
+	case err != nil:
+		return err
+	}
+	operandNamespace := operandNamespace(leaderWorkerSetOperator, namespace)
+	klog.Infof("Deploying the operand to namespace %s", operandNamespace)
+
 	kubeInformersForNamespaces := v1helpers.NewKubeInformersForNamespaces(kubeClient,
 		"",
 		namespace,
+		operandNamespace,
 	)
+	apiextensionInformers := apiextensionsinformers.NewSharedInformerFactory(apiextensionClient, 10*time.Minute)
+	// started by the workload inventory and LeaderWorkerSet metrics controllers once the operand CRDs are served
+	workloadInformers := dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, 10*time.Minute)
+	managedObjectInformers := dynamicinformer.NewFilteredDynamicSharedInformerFactory(dynamicClient, 10*time.Minute, metav1.NamespaceAll, func(options *metav1.ListOptions) {
+		options.LabelSelector = ManagedBySelector
+	})
 
 	leaderWorkerSetOperatorClient := &operatorclient.LeaderWorkerSetClient{
 		Ctx:            ctx,
@@ -70,30 +120,108 @@ func RunOperator(ctx context.Context, cc *controllercmd.ControllerContext) error
 		OperatorClient: operatorConfigClient.OpenShiftOperatorV1(),
 	}
 
-	targetConfigReconciler := NewTargetConfigReconciler(
+	targetConfigReconciler, err := NewTargetConfigReconciler(
 		os.Getenv("RELATED_IMAGE_OPERAND_IMAGE"),
 		namespace,
-		operatorConfigClient.OpenShiftOperatorV1().LeaderWorkerSetOperators(),
+		operandNamespace,
+		platform,
+		restartOperator,
 		operatorConfigInformers.OpenShiftOperator().V1().LeaderWorkerSetOperators(),
 		kubeInformersForNamespaces,
+		apiextensionInformers.Apiextensions().V1().CustomResourceDefinitions(),
+		managedObjectInformers,
+		leaderWorkerSetOperatorClient,
+		dynamicClient,
+		cachedDiscoveryClient,
+		kubeClient,
+		cc.EventRecorder,
+	)
+	if err != nil {
+		return err
+	}
+
+	webhookProbeController := NewWebhookProbeController(
+		namespace,
 		leaderWorkerSetOperatorClient,
 		dynamicClient,
-		discoveryClient,
+		apiextensionInformers.Apiextensions().V1().CustomResourceDefinitions(),
+		cc.EventRecorder,
+	)
+
+	var overridesValidator *configOverridesValidator
+	if webhookServingCertExists(webhookServingCertDir) {
+		staticResources, err := loadStaticResources()
+		if err != nil {
+			return err
+		}
+		overridesValidator = &configOverridesValidator{
+			staticResources:   staticResourcesForPlatform(staticResources, platform),
+			operatorNamespace: namespace,
+			releaseImage:      os.Getenv("RELATED_IMAGE_OPERAND_IMAGE"),
+			operatorVersion:   operatorVersionLabelValue(version.Get().GitVersion),
+			platform:          platform,
+			clock:             clock.RealClock{},
+		}
+	}
+
+	workloadInventoryController := NewWorkloadInventoryController(
+		leaderWorkerSetOperatorClient,
+		apiextensionInformers.Apiextensions().V1().CustomResourceDefinitions(),
+		workloadInformers,
+		cc.EventRecorder,
+	)
+
+	leaderWorkerSetMetricsController := NewLeaderWorkerSetMetricsController(
+		operatorConfigInformers.OpenShiftOperator().V1().LeaderWorkerSetOperators(),
+		apiextensionInformers.Apiextensions().V1().CustomResourceDefinitions(),
+		workloadInformers,
 		kubeClient,
-		apiextensionClient,
 		cc.EventRecorder,
 	)
 
-	logLevelController := loglevel.NewClusterOperatorLoggingController(leaderWorkerSetOperatorClient, cc.EventRecorder)
+	var defaultConfigController factory.Controller
+	if options.CreateDefaultConfig {
+		defaultConfigController = NewDefaultConfigController(
+			operatorConfigInformers.OpenShiftOperator().V1().LeaderWorkerSetOperators(),
+			operatorConfigClient,
+			cc.EventRecorder,
+		)
+	}
+
+	logLevelController := loglevel.NewClusterOperatorLoggingController(&debugOperatorClient{LeaderWorkerSetClient: leaderWorkerSetOperatorClient, clock: clock.RealClock{}}, cc.EventRecorder)
 
 	klog.Infof("Starting informers")
 	operatorConfigInformers.Start(ctx.Done())
 	kubeInformersForNamespaces.Start(ctx.Done())
-
+	apiextensionInformers.Start(ctx.Done())
+	managedObjectInformers.Start(ctx.Done())
+
+	if defaultConfigController != nil {
+		klog.Infof("Starting default config controller")
+		go defaultConfigController.Run(ctx, 1)
+	} else {
+		klog.Infof("Creating the default %s LeaderWorkerSetOperator is disabled", operatorclient.OperatorConfigName)
+	}
 	klog.Infof("Starting log level controller")
 	go logLevelController.Run(ctx, 1)
 	klog.Infof("Starting target config reconciler")
 	go targetConfigReconciler.Run(ctx, 1)
+	klog.Infof("Starting webhook probe controller")
+	go webhookProbeController.Run(ctx, 1)
+	klog.Infof("Starting workload inventory controller")
+	go workloadInventoryController.Run(ctx, 1)
+	klog.Infof("Starting LeaderWorkerSet metrics controller")
+	go leaderWorkerSetMetricsController.Run(ctx, 1)
+	if overridesValidator != nil {
+		klog.Infof("Starting unsupportedConfigOverrides admission webhook")
+		go func() {
+			if err := runConfigOverridesWebhook(ctx, overridesValidator, webhookServingCertDir); err != nil {
+				klog.Errorf("Unsupported config overrides webhook failed: %v", err)
+			}
+		}()
+	} else {
+		klog.Infof("No webhook serving certificate in %s, spec.unsupportedConfigOverrides is only validated by the reconciler", webhookServingCertDir)
+	}
 
 	<-ctx.Done()
 	return nil
@@ -0,0 +1,201 @@
+package operator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/csaupgrade"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// fieldManagerPrefix prefixes the server-side apply field manager of every resource group.
+	fieldManagerPrefix = "lws-operator"
+	// legacyFieldManager owns the fields written by the client-side library-go apply functions used by
+	// previous operator versions. Conflicts with it are forced and its remaining fields are migrated to
+	// the group field manager on first apply.
+	legacyFieldManager = "lws-operator"
+)
+
+// fieldManagerConflictPattern extracts the manager from the message of a FieldManagerConflict cause,
+// e.g. `conflict with "kubectl-edit" using apps/v1`.
+var fieldManagerConflictPattern = regexp.MustCompile(`conflict with "([^"]*)"`)
+
+// fieldManager returns the server-side apply field manager of a resource group, e.g. lws-operator-rbac.
+func fieldManager(group string) string {
+	return fieldManagerPrefix + "-" + strings.ToLower(group)
+}
+
+// IsOperatorFieldManager reports whether a field manager is the one of a resource group or the
+// client-side apply manager of previous operator versions.
+func IsOperatorFieldManager(manager string) bool {
+	return manager == legacyFieldManager || strings.HasPrefix(manager, fieldManagerPrefix+"-")
+}
+
+// applyStaticResources renders and applies the given manifests in order, skipping the objects that
+// are unmanaged through spec.overrides. Every manifest is applied even if a previous one failed; the
+// applied objects are returned along with the aggregated errors. Objects that did not change since
+// the previous sync are not applied again and are returned from the informer cache.
+func (c *TargetConfigReconciler) applyStaticResources(ctx context.Context, resources []staticResource, rc *renderContext) ([]*unstructured.Unstructured, error) {
+	var applied []*unstructured.Unstructured
+	var errs []error
+	for _, resource := range resources {
+		if rc.unmanaged(resource.gvk.GroupKind(), resource.renderedNamespace(rc), resource.name) {
+			klog.V(4).Infof("Skipping unmanaged %s %s", resource.gvk.Kind, resource.name)
+			continue
+		}
+		required, err := resource.render(rc)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		desired, err := toAppliedObject(required)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		hash, err := contentHash(desired)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		gvr := resource.gvr()
+		if cached := cachedObject(c.managedObjectListers, gvr, desired.GetNamespace(), desired.GetName()); rc.generations.unchanged(gvr.GroupResource(), desired, cached, hash) {
+			klog.V(4).Infof("Skipping unchanged %s %s", resource.gvk.Kind, resource.name)
+			rc.generations.record(gvr.GroupResource(), cached, hash)
+			applied = append(applied, cached)
+			continue
+		}
+		obj, err := c.applyStaticResource(ctx, desired, resource)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to apply %s %s: %w", resource.gvk.Kind, resource.name, err))
+			continue
+		}
+		rc.generations.record(gvr.GroupResource(), obj, hash)
+		applied = append(applied, obj)
+	}
+	return applied, utilerrors.NewAggregate(errs)
+}
+
+// applyStaticResource server-side applies a rendered manifest with the field manager of its resource
+// group, so the operator only owns the fields it renders and fields set by other controllers, e.g. the
+// CA bundles injected by cert-manager, are left alone. Conflicts with managers outside the operator are
+// not forced and are returned as ApplyConflict errors.
+func (c *TargetConfigReconciler) applyStaticResource(ctx context.Context, required runtime.Object, resource staticResource) (*unstructured.Unstructured, error) {
+	obj, err := toAppliedObject(required)
+	if err != nil {
+		return nil, err
+	}
+
+	var client dynamic.ResourceInterface = c.dynamicClient.Resource(resource.gvr())
+	if obj.GetNamespace() != "" {
+		client = c.dynamicClient.Resource(resource.gvr()).Namespace(obj.GetNamespace())
+	}
+	manager := fieldManager(resource.group)
+
+	actual, err := client.Apply(ctx, obj.GetName(), obj, metav1.ApplyOptions{FieldManager: manager})
+	if apierrors.IsConflict(err) {
+		conflicts, operatorOwned := fieldManagerConflicts(err)
+		if !operatorOwned {
+			return nil, &degradedError{
+				reason: "ApplyConflict",
+				err:    fmt.Errorf("fields are managed by other controllers: %s", strings.Join(conflicts, ", ")),
+			}
+		}
+		// the fields were applied by another resource group of the operator, e.g. after the object
+		// moved between groups, or by a previous operator version, e.g. the image of the Deployment
+		// before an upgrade, so take them over
+		klog.V(2).Infof("Forcing apply of %s %s, conflicts with operator field managers: %s", resource.gvk.Kind, obj.GetName(), strings.Join(conflicts, ", "))
+		actual, err = client.Apply(ctx, obj.GetName(), obj, metav1.ApplyOptions{FieldManager: manager, Force: true})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return c.upgradeLegacyManagedFields(ctx, client, actual, manager, resource)
+}
+
+// toAppliedObject converts a rendered manifest to the object sent to the API server.
+func toAppliedObject(required runtime.Object) (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(required)
+	if err != nil {
+		return nil, err
+	}
+	// typed objects carry an empty creation timestamp and status which must not be applied
+	unstructured.RemoveNestedField(content, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(content, "status")
+	return &unstructured.Unstructured{Object: content}, nil
+}
+
+// upgradeLegacyManagedFields moves the fields owned by the client-side apply of previous operator
+// versions to the server-side apply field manager, so fields dropped from the manifests are removed.
+func (c *TargetConfigReconciler) upgradeLegacyManagedFields(ctx context.Context, client dynamic.ResourceInterface, actual *unstructured.Unstructured, manager string, resource staticResource) (*unstructured.Unstructured, error) {
+	patch, err := csaupgrade.UpgradeManagedFieldsPatch(actual, sets.New(legacyFieldManager), manager)
+	if err != nil {
+		return nil, err
+	}
+	if patch == nil {
+		return actual, nil
+	}
+	upgraded, err := client.Patch(ctx, actual.GetName(), types.JSONPatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to upgrade managed fields: %w", err)
+	}
+	c.eventRecorder.Eventf("ManagedFieldsUpgraded", "Migrated the fields of %s %s to server-side apply field manager %s",
+		resource.gvk.Kind, resourceName(actual.GetNamespace(), actual.GetName()), manager)
+	return upgraded, nil
+}
+
+// fieldManagerConflicts returns the conflicting fields of a server-side apply conflict, and whether
+// all of them are owned by field managers of the operator, including the client-side apply manager of
+// previous versions.
+func fieldManagerConflicts(err error) ([]string, bool) {
+	var statusErr apierrors.APIStatus
+	if !errors.As(err, &statusErr) || statusErr.Status().Details == nil {
+		return []string{err.Error()}, false
+	}
+
+	var conflicts []string
+	operatorOwned := true
+	for _, cause := range statusErr.Status().Details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+		manager := "unknown"
+		if match := fieldManagerConflictPattern.FindStringSubmatch(cause.Message); match != nil {
+			manager = match[1]
+		}
+		if !IsOperatorFieldManager(manager) {
+			operatorOwned = false
+		}
+		conflicts = append(conflicts, fmt.Sprintf("%s (%s)", cause.Field, manager))
+	}
+	if len(conflicts) == 0 {
+		return []string{statusErr.Status().Message}, false
+	}
+	sort.Strings(conflicts)
+	return conflicts, operatorOwned
+}
+
+// gvr returns the resource of the manifest, guessing the plural for kinds without a rule.
+func (r staticResource) gvr() schema.GroupVersionResource {
+	if rule, ok := staticResourceRules[r.gvk.GroupKind()]; ok {
+		return r.gvk.GroupVersion().WithResource(rule.resource.Resource)
+	}
+	gvr, _ := meta.UnsafeGuessKindToResource(r.gvk)
+	return gvr
+}
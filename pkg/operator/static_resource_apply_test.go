@@ -0,0 +1,143 @@
+package operator
+
+import (
+	"context"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/utils/ptr"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+func newApplyConflict(managers ...string) error {
+	causes := make([]metav1.StatusCause, 0, len(managers))
+	for _, manager := range managers {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldManagerConflict,
+			Message: `conflict with "` + manager + `" using apps/v1`,
+			Field:   ".spec.replicas",
+		})
+	}
+	return apierrors.NewApplyConflict(causes, "Apply failed")
+}
+
+func TestFieldManagerConflicts(t *testing.T) {
+	conflicts, operatorOwned := fieldManagerConflicts(newApplyConflict("lws-operator-deployment"))
+	if !operatorOwned || len(conflicts) != 1 || conflicts[0] != ".spec.replicas (lws-operator-deployment)" {
+		t.Errorf("unexpected conflicts %v, operator owned %v", conflicts, operatorOwned)
+	}
+
+	conflicts, operatorOwned = fieldManagerConflicts(newApplyConflict("lws-operator-rbac", "kubectl-edit"))
+	if operatorOwned || len(conflicts) != 2 {
+		t.Errorf("unexpected conflicts %v, operator owned %v", conflicts, operatorOwned)
+	}
+
+	// the values set by the client-side apply of previous versions are taken over
+	if _, operatorOwned = fieldManagerConflicts(newApplyConflict(legacyFieldManager)); !operatorOwned {
+		t.Errorf("expected conflicts with %s to be forced", legacyFieldManager)
+	}
+}
+
+func TestApplyConflictWithLegacyFieldManager(t *testing.T) {
+	f := newTargetConfigReconcilerFixture(t)
+	// the Deployment of a previous version, whose image differs from the rendered one
+	forced := 0
+	f.dynamicClient.PrependReactor("patch", "deployments", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		patchAction := action.(clienttesting.PatchActionImpl)
+		if patchAction.GetPatchType() != types.ApplyPatchType {
+			return false, nil, nil
+		}
+		if ptr.Deref(patchAction.PatchOptions.Force, false) {
+			forced++
+			return false, nil, nil
+		}
+		return true, nil, newApplyConflict(legacyFieldManager)
+	})
+
+	f.converge(t)
+	if forced == 0 {
+		t.Errorf("expected the conflicts with %s to be forced", legacyFieldManager)
+	}
+	if condition := f.condition(t, "DeploymentDegraded"); condition == nil || condition.Status != operatorv1.ConditionFalse {
+		t.Errorf("expected DeploymentDegraded to be False, got %+v", condition)
+	}
+}
+
+func TestApplyConflictReportedInStatus(t *testing.T) {
+	f := newTargetConfigReconcilerFixture(t)
+	forced := 0
+	f.dynamicClient.PrependReactor("patch", "deployments", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		patchAction := action.(clienttesting.PatchActionImpl)
+		if patchAction.GetPatchType() != types.ApplyPatchType {
+			return false, nil, nil
+		}
+		if ptr.Deref(patchAction.PatchOptions.Force, false) {
+			forced++
+		}
+		return true, nil, newApplyConflict("kubectl-edit")
+	})
+
+	err := f.reconciler.sync(context.Background(), f.syncCtx)
+	if err == nil {
+		t.Fatal("expected the sync to fail")
+	}
+	if forced != 0 {
+		t.Errorf("expected conflicts with other managers not to be forced")
+	}
+
+	_, status, _, err := f.reconciler.leaderWorkerSetOperatorClient.GetOperatorStateWithQuorum(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	condition := v1helpers.FindOperatorCondition(status.Conditions, "DeploymentDegraded")
+	if condition == nil || condition.Status != operatorv1.ConditionTrue || condition.Reason != "ApplyConflict" {
+		t.Fatalf("expected DeploymentDegraded with reason ApplyConflict, got %+v", condition)
+	}
+}
+
+func TestUpgradeLegacyManagedFields(t *testing.T) {
+	f := newTargetConfigReconcilerFixture(t)
+	resource := findStaticResource(t, f.reconciler.staticResources, "ClusterRole", "lws-manager-role")
+
+	legacy := &unstructured.Unstructured{}
+	legacy.SetAPIVersion("rbac.authorization.k8s.io/v1")
+	legacy.SetKind("ClusterRole")
+	legacy.SetName(resource.name)
+	legacy.SetResourceVersion("1")
+	legacy.SetManagedFields([]metav1.ManagedFieldsEntry{{
+		Manager:    legacyFieldManager,
+		Operation:  metav1.ManagedFieldsOperationUpdate,
+		APIVersion: "rbac.authorization.k8s.io/v1",
+		FieldsType: "FieldsV1",
+		FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:rules":{}}`)},
+	}})
+	client := f.dynamicClient.Resource(resource.gvr())
+	if _, err := client.Create(context.Background(), legacy, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	f.clearActions()
+	upgraded, err := f.reconciler.upgradeLegacyManagedFields(context.Background(), client, legacy, fieldManager(rbacResourceGroup), resource)
+	if err != nil {
+		t.Fatal(err)
+	}
+	managedFields := upgraded.GetManagedFields()
+	if len(managedFields) != 1 || managedFields[0].Manager != "lws-operator-rbac" || managedFields[0].Operation != metav1.ManagedFieldsOperationApply {
+		t.Errorf("expected the fields to be owned by the apply manager, got %+v", managedFields)
+	}
+
+	f.clearActions()
+	if _, err := f.reconciler.upgradeLegacyManagedFields(context.Background(), client, upgraded, fieldManager(rbacResourceGroup), resource); err != nil {
+		t.Fatal(err)
+	}
+	if actions := f.actions(); len(actions) != 0 {
+		t.Errorf("expected already upgraded objects not to be patched, got %v", actions)
+	}
+}
@@ -0,0 +1,243 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/ptr"
+
+	leaderworkersetapiv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+	"github.com/openshift/lws-operator/pkg/operator/operatorclient"
+)
+
+const (
+	// garbageCollectionResourceGroup reports the failures to delete stale objects.
+	garbageCollectionResourceGroup = "GarbageCollection"
+
+	// managedByLabel marks every object applied from the embedded manifests. Objects carrying it
+	// that are no longer part of the manifests are garbage collected.
+	managedByLabel      = "leaderworkerset.operator.openshift.io/managed-by"
+	managedByLabelValue = "lws-operator"
+	// operatorVersionLabel records the version of the operator that last applied the object.
+	operatorVersionLabel = "leaderworkerset.operator.openshift.io/operator-version"
+
+	// certificateNameAnnotation is set by cert-manager on the secrets it issues.
+	certificateNameAnnotation = "cert-manager.io/certificate-name"
+
+	// ManagedBySelector selects the objects applied from the embedded manifests.
+	ManagedBySelector = managedByLabel + "=" + managedByLabelValue
+)
+
+// setManagedLabels labels a rendered manifest as managed by this version of the operator.
+func setManagedLabels(obj metav1.Object, operatorVersion string) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[managedByLabel] = managedByLabelValue
+	labels[operatorVersionLabel] = operatorVersion
+	obj.SetLabels(labels)
+}
+
+// operatorVersionLabelValue turns the operator git version into a valid label value, e.g.
+// v1.0.0+abc becomes v1.0.0-abc. Builds without a version are labeled unknown.
+func operatorVersionLabelValue(gitVersion string) string {
+	value := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		default:
+			return '-'
+		}
+	}, gitVersion)
+	if len(value) > validation.LabelValueMaxLength {
+		value = value[:validation.LabelValueMaxLength]
+	}
+	value = strings.Trim(value, "-_.")
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
+
+// collectGarbage deletes objects labeled as managed by the operator that are no longer part of the
+// embedded manifests, e.g. a ClusterRole that was dropped or renamed upstream. Namespaced objects are
+// looked up in the operator namespace, the operand namespace and the operand namespace recorded in the
+// status, so the objects left behind in a previous operand namespace are removed as well. Only kinds
+// with pruning enabled are considered, objects unmanaged through spec.overrides are kept, and every
+// deletion is reported as an event.
+func (c *TargetConfigReconciler) collectGarbage(ctx context.Context, resources []staticResource, rc *renderContext) error {
+	type objectKey struct {
+		namespace string
+		name      string
+	}
+
+	namespaces := sets.New(c.operatorNamespace, rc.namespace)
+	previousNamespace := rc.operator.Status.OperandNamespace
+	if previousNamespace != "" {
+		namespaces.Insert(previousNamespace)
+	}
+
+	listOptions := metav1.ListOptions{LabelSelector: managedByLabel + "=" + managedByLabelValue}
+	var errs []error
+	for gk, rule := range staticResourceRules {
+		if !rule.prune {
+			continue
+		}
+
+		wanted := sets.New[objectKey]()
+		for _, resource := range resources {
+			if resource.gvk.GroupKind() != gk {
+				continue
+			}
+			if rule.namespaced {
+				wanted.Insert(objectKey{namespace: rc.namespace, name: resource.name})
+			} else {
+				wanted.Insert(objectKey{name: resource.name})
+			}
+		}
+		if gk == (schema.GroupKind{Group: roleBindingsGVR.Group, Kind: "RoleBinding"}) {
+			// the binding of a dedicated operand namespace is kept until the objects of the previous
+			// operand namespace are collected, see below
+			for _, namespace := range []string{rc.namespace, previousNamespace} {
+				if namespace != "" && namespace != c.operatorNamespace {
+					wanted.Insert(objectKey{namespace: namespace, name: operandNamespaceRoleBinding})
+				}
+			}
+		}
+
+		client := c.dynamicClient.Resource(rule.resource)
+		var items []unstructured.Unstructured
+		var err error
+		if rule.namespaced {
+			for _, namespace := range sets.List(namespaces) {
+				var list *unstructured.UnstructuredList
+				if list, err = client.Namespace(namespace).List(ctx, listOptions); err != nil {
+					break
+				}
+				items = append(items, list.Items...)
+			}
+		} else {
+			var list *unstructured.UnstructuredList
+			if list, err = client.List(ctx, listOptions); err == nil {
+				items = list.Items
+			}
+		}
+		if apierrors.IsNotFound(err) {
+			// the API is not served, e.g. the ServiceMonitor CRD is not installed
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to list %s: %w", rule.resource.Resource, err))
+			continue
+		}
+
+		for _, item := range items {
+			if wanted.Has(objectKey{namespace: item.GetNamespace(), name: item.GetName()}) || rc.unmanaged(gk, item.GetNamespace(), item.GetName()) {
+				continue
+			}
+			// guard against deleting an object that was recreated since it was listed
+			deleteOptions := metav1.DeleteOptions{Preconditions: &metav1.Preconditions{UID: ptr.To(item.GetUID())}}
+			var deleteErr error
+			if rule.namespaced {
+				deleteErr = client.Namespace(item.GetNamespace()).Delete(ctx, item.GetName(), deleteOptions)
+			} else {
+				deleteErr = client.Delete(ctx, item.GetName(), deleteOptions)
+			}
+			if deleteErr != nil && !apierrors.IsNotFound(deleteErr) {
+				errs = append(errs, fmt.Errorf("unable to delete stale %s %s: %w", gk.Kind, resourceName(item.GetNamespace(), item.GetName()), deleteErr))
+				continue
+			}
+			c.eventRecorder.Eventf("StaleResourceDeleted", "Deleted %s %s applied by operator version %s that is no longer part of the operand manifests",
+				gk.Kind, resourceName(item.GetNamespace(), item.GetName()), item.GetLabels()[operatorVersionLabel])
+			if gk.Kind == "Certificate" {
+				if err := c.deleteCertificateSecret(ctx, item); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return utilerrors.NewAggregate(errs)
+	}
+
+	// the binding is only needed to collect the previous operand namespace
+	if previousNamespace != "" && previousNamespace != rc.namespace && previousNamespace != c.operatorNamespace {
+		err := c.dynamicClient.Resource(roleBindingsGVR).Namespace(previousNamespace).Delete(ctx, operandNamespaceRoleBinding, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("unable to delete RoleBinding %s/%s: %w", previousNamespace, operandNamespaceRoleBinding, err)
+		}
+	}
+	return nil
+}
+
+// setOperandNamespaceStatus records the operand namespace once the previous one has been collected.
+func setOperandNamespaceStatus(namespace string) operatorclient.UpdateStatusFunc {
+	return func(status *leaderworkersetapiv1.LeaderWorkerSetOperatorStatus) error {
+		status.OperandNamespace = namespace
+		return nil
+	}
+}
+
+// deleteCertificateSecret deletes the secret issued for a deleted Certificate, which cert-manager keeps
+// by default, so no key material is left behind in a previous operand namespace. Secrets that were
+// not applied by the operator or issued for this Certificate are kept, since spec.secretName may
+// name any secret of the namespace.
+func (c *TargetConfigReconciler) deleteCertificateSecret(ctx context.Context, certificate unstructured.Unstructured) error {
+	secretName, _, _ := unstructured.NestedString(certificate.Object, "spec", "secretName")
+	if secretName == "" {
+		return nil
+	}
+	secrets := c.kubeClient.CoreV1().Secrets(certificate.GetNamespace())
+	secret, err := secrets.Get(ctx, secretName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to get secret %s of stale Certificate %s: %w", secretName, certificate.GetName(), err)
+	}
+	if !issuedForCertificate(secret, certificate) {
+		return nil
+	}
+	err = secrets.Delete(ctx, secretName, metav1.DeleteOptions{Preconditions: &metav1.Preconditions{UID: ptr.To(secret.UID)}})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to delete secret %s of stale Certificate %s: %w", secretName, certificate.GetName(), err)
+	}
+	c.eventRecorder.Eventf("StaleResourceDeleted", "Deleted Secret %s issued for the stale Certificate %s",
+		resourceName(certificate.GetNamespace(), secretName), certificate.GetName())
+	return nil
+}
+
+// issuedForCertificate returns whether the secret is managed by the operator or was issued by
+// cert-manager for the Certificate, which records the Certificate in an annotation and, with
+// --enable-certificate-owner-ref, an owner reference.
+func issuedForCertificate(secret *corev1.Secret, certificate unstructured.Unstructured) bool {
+	if secret.Labels[managedByLabel] == managedByLabelValue {
+		return true
+	}
+	for _, ownerReference := range secret.OwnerReferences {
+		if ownerReference.Kind == "Certificate" && ownerReference.UID == certificate.GetUID() {
+			return true
+		}
+	}
+	return secret.Annotations[certificateNameAnnotation] == certificate.GetName()
+}
+
+func resourceName(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "/" + name
+}
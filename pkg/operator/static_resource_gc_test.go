@@ -0,0 +1,114 @@
+package operator
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+func TestOperatorVersionLabelValue(t *testing.T) {
+	tests := map[string]string{
+		"":                        "unknown",
+		"v1.0.0":                  "v1.0.0",
+		"v1.0.0+abc":              "v1.0.0-abc",
+		"v1.0.0-12-gabcdef-dirty": "v1.0.0-12-gabcdef-dirty",
+		"+++":                     "unknown",
+	}
+	for gitVersion, expected := range tests {
+		value := operatorVersionLabelValue(gitVersion)
+		if value != expected {
+			t.Errorf("%q: expected %q, got %q", gitVersion, expected, value)
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) != 0 {
+			t.Errorf("%q: invalid label value %q: %v", gitVersion, value, errs)
+		}
+	}
+
+	long := operatorVersionLabelValue("v1.0.0-" + string(make([]byte, 100)))
+	if errs := validation.IsValidLabelValue(long); len(errs) != 0 {
+		t.Errorf("invalid label value %q: %v", long, errs)
+	}
+}
+
+func testClusterRole(name string, labels map[string]string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("rbac.authorization.k8s.io/v1")
+	obj.SetKind("ClusterRole")
+	obj.SetName(name)
+	obj.SetUID(types.UID("uid-" + name))
+	obj.SetLabels(labels)
+	return obj
+}
+
+func TestCollectGarbage(t *testing.T) {
+	managed := map[string]string{managedByLabel: managedByLabelValue, operatorVersionLabel: "v1.1.0"}
+	f := newTargetConfigReconcilerFixture(t,
+		// dropped upstream in this version
+		testClusterRole("lws-removed-role", managed),
+		// not managed by the operator
+		testClusterRole("lws-user-role", nil),
+	)
+	f.converge(t)
+
+	clusterRoles := f.dynamicClient.Resource(schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"})
+	if _, err := clusterRoles.Get(context.Background(), "lws-removed-role", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the stale ClusterRole to be deleted, got %v", err)
+	}
+	if _, err := clusterRoles.Get(context.Background(), "lws-user-role", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected the unmanaged ClusterRole to be kept, got %v", err)
+	}
+
+	found := false
+	for _, event := range f.recorder.Events() {
+		if event.Reason == "StaleResourceDeleted" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected a StaleResourceDeleted event")
+	}
+}
+
+func testStaleCertificate(name, secretName string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{"secretName": secretName}}}
+	obj.SetAPIVersion("cert-manager.io/v1")
+	obj.SetKind("Certificate")
+	obj.SetNamespace(testNamespace)
+	obj.SetName(name)
+	obj.SetUID(types.UID("uid-" + name))
+	obj.SetLabels(map[string]string{managedByLabel: managedByLabelValue, operatorVersionLabel: "v1.1.0"})
+	return obj
+}
+
+func TestCollectGarbageCertificateSecrets(t *testing.T) {
+	f := newTargetConfigReconcilerFixture(t,
+		testStaleCertificate("lws-removed-cert", "lws-removed-cert"),
+		// a stale Certificate naming a secret it did not issue
+		testStaleCertificate("lws-renamed-cert", "user-secret"),
+	)
+	ctx := context.Background()
+	secrets := f.reconciler.kubeClient.CoreV1().Secrets(testNamespace)
+	for _, secret := range []*corev1.Secret{
+		{ObjectMeta: metav1.ObjectMeta{Name: "lws-removed-cert", Namespace: testNamespace, Annotations: map[string]string{certificateNameAnnotation: "lws-removed-cert"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "user-secret", Namespace: testNamespace}},
+	} {
+		if _, err := secrets.Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f.converge(t)
+
+	if _, err := secrets.Get(ctx, "lws-removed-cert", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the secret issued for the stale Certificate to be deleted, got %v", err)
+	}
+	if _, err := secrets.Get(ctx, "user-secret", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected the secret not issued for the stale Certificate to be kept, got %v", err)
+	}
+}
@@ -0,0 +1,228 @@
+package operator
+
+import (
+	"fmt"
+	"strings"
+
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+
+	"github.com/openshift/library-go/pkg/operator/resource/resourcemerge"
+
+	"github.com/openshift/lws-operator/bindata"
+)
+
+// substituteSubjectNamespaces moves the subjects that live in the manifest namespace to the operand namespace.
+// Subjects in other namespaces, e.g. the openshift-monitoring prometheus service accounts, are left as is.
+func substituteSubjectNamespaces(obj runtime.Object, rc *renderContext) error {
+	var subjects []rbacv1.Subject
+	switch t := obj.(type) {
+	case *rbacv1.ClusterRoleBinding:
+		subjects = t.Subjects
+	case *rbacv1.RoleBinding:
+		subjects = t.Subjects
+	default:
+		return fmt.Errorf("unexpected type %T", obj)
+	}
+	for i := range subjects {
+		if subjects[i].Namespace == manifestNamespace {
+			subjects[i].Namespace = rc.namespace
+		}
+	}
+	return nil
+}
+
+// substituteConversionWebhookNamespace points the CRD conversion webhook at the operand namespace.
+func substituteConversionWebhookNamespace(obj runtime.Object, rc *renderContext) error {
+	crd, ok := obj.(*apiextensionv1.CustomResourceDefinition)
+	if !ok {
+		return fmt.Errorf("unexpected type %T", obj)
+	}
+	if crd.Spec.Conversion != nil &&
+		crd.Spec.Conversion.Webhook != nil &&
+		crd.Spec.Conversion.Webhook.ClientConfig != nil &&
+		crd.Spec.Conversion.Webhook.ClientConfig.Service != nil {
+		crd.Spec.Conversion.Webhook.ClientConfig.Service.Namespace = rc.namespace
+	}
+	return nil
+}
+
+// substituteWebhookNamespaces points the admission webhooks at the operand namespace.
+func substituteWebhookNamespaces(obj runtime.Object, rc *renderContext) error {
+	switch t := obj.(type) {
+	case *admissionv1.MutatingWebhookConfiguration:
+		for i := range t.Webhooks {
+			if t.Webhooks[i].ClientConfig.Service != nil {
+				t.Webhooks[i].ClientConfig.Service.Namespace = rc.namespace
+			}
+		}
+	case *admissionv1.ValidatingWebhookConfiguration:
+		for i := range t.Webhooks {
+			if t.Webhooks[i].ClientConfig.Service != nil {
+				t.Webhooks[i].ClientConfig.Service.Namespace = rc.namespace
+			}
+		}
+	default:
+		return fmt.Errorf("unexpected type %T", obj)
+	}
+	return nil
+}
+
+// injectCertManagerCAIfAnnotated resolves the cert-manager CA injection annotation when the manifest carries it.
+func injectCertManagerCAIfAnnotated(obj runtime.Object, rc *renderContext) error {
+	metaObj := obj.(metav1.Object)
+	if _, ok := metaObj.GetAnnotations()[CertManagerInjectCaAnnotation]; !ok {
+		return nil
+	}
+	return injectCertManagerCA(metaObj, rc.namespace)
+}
+
+// substituteServiceName replaces the SERVICE_NAME and SERVICE_NAMESPACE placeholders in every string
+// of a custom resource, e.g. the DNS names of a Certificate or the server name of a ServiceMonitor.
+func substituteServiceName(obj runtime.Object, serviceName, namespace string) error {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("service name substitution is only supported for custom resources, got %T", obj)
+	}
+	replacer := strings.NewReplacer("SERVICE_NAMESPACE", namespace, "SERVICE_NAME", serviceName)
+	u.Object = replaceStrings(u.Object, replacer).(map[string]interface{})
+	return nil
+}
+
+func replaceStrings(value interface{}, replacer *strings.Replacer) interface{} {
+	switch t := value.(type) {
+	case string:
+		return replacer.Replace(t)
+	case map[string]interface{}:
+		for k, v := range t {
+			t[k] = replaceStrings(v, replacer)
+		}
+		return t
+	case []interface{}:
+		for i := range t {
+			t[i] = replaceStrings(t[i], replacer)
+		}
+		return t
+	default:
+		return value
+	}
+}
+
+// usePrometheusClientCerts replaces the TLS client secret references of the ServiceMonitor with the
+// client certificates mounted in the openshift-monitoring prometheus. On other platforms the secret
+// references are kept, so any Prometheus Operator can read the cert-manager issued metrics certificate
+// from the operand namespace.
+func usePrometheusClientCerts(obj runtime.Object, rc *renderContext) error {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("unexpected type %T", obj)
+	}
+	if rc.platform != platformOpenShift {
+		return nil
+	}
+	serviceMonitor := &monitoringv1.ServiceMonitor{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, serviceMonitor); err != nil {
+		return err
+	}
+
+	for i, endpoint := range serviceMonitor.Spec.Endpoints {
+		if endpoint.TLSConfig == nil {
+			continue
+		}
+		// clear out the references
+		endpoint.TLSConfig.Cert.Secret = nil
+		endpoint.TLSConfig.Cert.ConfigMap = nil
+		endpoint.TLSConfig.KeySecret = nil
+		// set mounted secret in the openshift-monitoring prometheus
+		endpoint.TLSConfig.CertFile = fmt.Sprintf("%s/%s", PrometheusClientCertsPath, "tls.crt")
+		endpoint.TLSConfig.KeyFile = fmt.Sprintf("%s/%s", PrometheusClientCertsPath, "tls.key")
+		serviceMonitor.Spec.Endpoints[i] = endpoint
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(serviceMonitor)
+	if err != nil {
+		return err
+	}
+	// the typed ServiceMonitor does not keep the creation timestamp and status empty
+	unstructured.RemoveNestedField(content, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(content, "status")
+	u.Object = content
+	return nil
+}
+
+// setControllerManagerConfig embeds the operand controller configuration into its ConfigMap.
+func setControllerManagerConfig(obj runtime.Object, rc *renderContext) error {
+	configMap, ok := obj.(*corev1.ConfigMap)
+	if !ok {
+		return fmt.Errorf("unexpected type %T", obj)
+	}
+	config, err := setOperandPprof(bindata.MustAsset("assets/lws-controller-config/config.yaml"), rc.debug)
+	if err != nil {
+		return err
+	}
+	configMap.Data = map[string]string{
+		"controller_manager_config.yaml": string(config),
+	}
+	return nil
+}
+
+// mutateOperandDeployment sets the operand image, rollout annotations, logging arguments, node placement and
+// the pull secrets and trust bundle referenced by the operator CR.
+func mutateOperandDeployment(obj runtime.Object, rc *renderContext) error {
+	required, ok := obj.(*appsv1.Deployment)
+	if !ok {
+		return fmt.Errorf("unexpected type %T", obj)
+	}
+	required.Name = operandName
+
+	if rc.targetImage != "" {
+		images := map[string]string{
+			"${CONTROLLER_IMAGE}:latest": rc.targetImage,
+		}
+
+		for i := range required.Spec.Template.Spec.Containers {
+			for env, img := range images {
+				if required.Spec.Template.Spec.Containers[i].Image == env {
+					required.Spec.Template.Spec.Containers[i].Image = img
+					break
+				}
+			}
+		}
+	}
+
+	resourcemerge.MergeMap(ptr.To(false), &required.Spec.Template.Annotations, rc.specAnnotations)
+
+	newArgs := []string{
+		"--config=/controller_manager_config.yaml",
+	}
+
+	logArgs, err := operandLogArgs(operandLogLevel(rc), rc.operator.Spec.OperandLogging)
+	if err != nil {
+		return err
+	}
+	newArgs = append(newArgs, logArgs...)
+
+	// replace the default arg values from upstream
+	required.Spec.Template.Spec.Containers[0].Args = newArgs
+
+	// the operand namespace enforces the restricted pod security level
+	if required.Spec.Template.Spec.SecurityContext == nil {
+		required.Spec.Template.Spec.SecurityContext = &corev1.PodSecurityContext{}
+	}
+	if required.Spec.Template.Spec.SecurityContext.SeccompProfile == nil {
+		required.Spec.Template.Spec.SecurityContext.SeccompProfile = &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}
+	}
+
+	applyNodePlacement(&required.Spec.Template.Spec, rc.operator.Spec.NodePlacement)
+	applyOperandReferences(&required.Spec.Template.Spec, rc.operator.Spec.Operand)
+	return nil
+}
@@ -0,0 +1,372 @@
+package operator
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
+
+	"github.com/openshift/lws-operator/bindata"
+	leaderworkersetapiv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+)
+
+const (
+	// operandAssetsDir holds the operand manifests generated by `make generate-controller-manifests`.
+	operandAssetsDir = "assets/lws-controller-generated"
+	// manifestNamespace is the namespace the upstream operand manifests are generated for. It is
+	// substituted with the namespace the operand is deployed to.
+	manifestNamespace = "openshift-lws-operator"
+
+	rbacResourceGroup         = "RBAC"
+	certificatesResourceGroup = "Certificates"
+	crdsResourceGroup         = "CRDs"
+	webhooksResourceGroup     = "Webhooks"
+	monitoringResourceGroup   = "Monitoring"
+	deploymentResourceGroup   = "Deployment"
+)
+
+// renderContext carries the inputs the mutators need to render the operand manifests.
+type renderContext struct {
+	namespace       string
+	targetImage     string
+	operatorVersion string
+	operator        *leaderworkersetapiv1.LeaderWorkerSetOperator
+	ownerReference  metav1.OwnerReference
+	specAnnotations map[string]string
+	platform        platform
+	// debug is the open debug window of the operator CR, if any.
+	debug *leaderworkersetapiv1.DebugSpec
+	// configPatches are the patches of spec.unsupportedConfigOverrides, applied after all mutators.
+	configPatches *configPatches
+	// generations records the objects applied during a sync, see appliedGenerations.
+	generations *appliedGenerations
+	// operatorNamespace is the namespace of the operator. An operand namespace that differs from it is
+	// dedicated to the operand.
+	operatorNamespace string
+}
+
+// resourceMutator modifies a decoded manifest before it is applied.
+type resourceMutator func(obj runtime.Object, rc *renderContext) error
+
+// staticResourceRule describes how embedded manifests of a given kind are reconciled.
+type staticResourceRule struct {
+	// group is the resource group the manifests are applied in.
+	group string
+	// resource is used to find stale objects of this kind.
+	resource schema.GroupVersionResource
+	// namespaced is set for kinds that live in the operand namespace.
+	namespaced bool
+	// prune enables deletion of objects of this kind that are no longer part of the embedded manifests.
+	prune bool
+	// mutators are applied in order after the namespace substitution, owner reference and labels.
+	mutators []resourceMutator
+}
+
+// staticResourceRules maps the kinds of the embedded manifests to the way they are reconciled.
+// New upstream manifests of these kinds are picked up without any code changes; manifests of
+// other kinds are applied in the Deployment group with the common mutators only.
+var staticResourceRules = map[schema.GroupKind]staticResourceRule{
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"}: {
+		group:    rbacResourceGroup,
+		resource: schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"},
+		prune:    true,
+	},
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"}: {
+		group:    rbacResourceGroup,
+		resource: schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterrolebindings"},
+		prune:    true,
+		mutators: []resourceMutator{substituteSubjectNamespaces},
+	},
+	{Group: "rbac.authorization.k8s.io", Kind: "Role"}: {
+		group:      rbacResourceGroup,
+		resource:   schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "roles"},
+		namespaced: true,
+		prune:      true,
+	},
+	{Group: "rbac.authorization.k8s.io", Kind: "RoleBinding"}: {
+		group:      rbacResourceGroup,
+		resource:   schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "rolebindings"},
+		namespaced: true,
+		prune:      true,
+		mutators:   []resourceMutator{substituteSubjectNamespaces},
+	},
+	{Group: "cert-manager.io", Kind: "Issuer"}: {
+		group:      certificatesResourceGroup,
+		resource:   schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "issuers"},
+		namespaced: true,
+		prune:      true,
+	},
+	{Group: "cert-manager.io", Kind: "Certificate"}: {
+		group:      certificatesResourceGroup,
+		resource:   schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"},
+		namespaced: true,
+		prune:      true,
+	},
+	// CRDs are never pruned: deleting a CRD deletes every custom resource of that type.
+	{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}: {
+		group:    crdsResourceGroup,
+		resource: schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"},
+		mutators: []resourceMutator{substituteConversionWebhookNamespace, injectCertManagerCAIfAnnotated},
+	},
+	{Group: "admissionregistration.k8s.io", Kind: "MutatingWebhookConfiguration"}: {
+		group:    webhooksResourceGroup,
+		resource: schema.GroupVersionResource{Group: "admissionregistration.k8s.io", Version: "v1", Resource: "mutatingwebhookconfigurations"},
+		prune:    true,
+		mutators: []resourceMutator{substituteWebhookNamespaces, injectCertManagerCAIfAnnotated},
+	},
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingWebhookConfiguration"}: {
+		group:    webhooksResourceGroup,
+		resource: schema.GroupVersionResource{Group: "admissionregistration.k8s.io", Version: "v1", Resource: "validatingwebhookconfigurations"},
+		prune:    true,
+		mutators: []resourceMutator{substituteWebhookNamespaces, injectCertManagerCAIfAnnotated},
+	},
+	{Group: "monitoring.coreos.com", Kind: "ServiceMonitor"}: {
+		group:      monitoringResourceGroup,
+		resource:   schema.GroupVersionResource{Group: "monitoring.coreos.com", Version: "v1", Resource: "servicemonitors"},
+		namespaced: true,
+		prune:      true,
+		mutators:   []resourceMutator{usePrometheusClientCerts},
+	},
+	{Group: "", Kind: "Service"}: {
+		group:      deploymentResourceGroup,
+		resource:   schema.GroupVersionResource{Group: "", Version: "v1", Resource: "services"},
+		namespaced: true,
+		prune:      true,
+	},
+	{Group: "", Kind: "ServiceAccount"}: {
+		group:      deploymentResourceGroup,
+		resource:   schema.GroupVersionResource{Group: "", Version: "v1", Resource: "serviceaccounts"},
+		namespaced: true,
+		prune:      true,
+	},
+	{Group: "", Kind: "ConfigMap"}: {
+		group:      deploymentResourceGroup,
+		resource:   schema.GroupVersionResource{Group: "", Version: "v1", Resource: "configmaps"},
+		namespaced: true,
+		prune:      true,
+	},
+	{Group: "apps", Kind: "Deployment"}: {
+		group:      deploymentResourceGroup,
+		resource:   schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
+		namespaced: true,
+		prune:      true,
+		mutators:   []resourceMutator{mutateOperandDeployment},
+	},
+}
+
+// staticResourceOverride customizes a single manifest, identified by kind and name.
+type staticResourceOverride struct {
+	// group overrides the resource group of the kind rule.
+	group string
+	// serviceName is substituted for SERVICE_NAME placeholders in the manifest.
+	serviceName string
+	// mutators are applied after the mutators of the kind rule.
+	mutators []resourceMutator
+	// openShiftOnly skips the manifest on clusters without the OpenShift APIs.
+	openShiftOnly bool
+}
+
+// staticResourceOverrides holds the manifests that need more than the rules of their kind.
+var staticResourceOverrides = map[string]staticResourceOverride{
+	"Service/lws-webhook-service":                    {group: webhooksResourceGroup},
+	"Service/lws-controller-manager-metrics-service": {group: monitoringResourceGroup},
+	// grant the openshift-monitoring prometheus access to the metrics endpoint
+	"Role/lws-prometheus-k8s":                               {group: monitoringResourceGroup, openShiftOnly: true},
+	"RoleBinding/lws-prometheus-k8s":                        {group: monitoringResourceGroup, openShiftOnly: true},
+	"Certificate/lws-serving-cert":                          {serviceName: "lws-webhook-service"},
+	"Certificate/lws-metrics-cert":                          {serviceName: "lws-controller-manager-metrics-service"},
+	"ServiceMonitor/lws-controller-manager-metrics-monitor": {serviceName: "lws-controller-manager-metrics-service"},
+	"ConfigMap/lws-manager-config":                          {mutators: []resourceMutator{setControllerManagerConfig}},
+	"ServiceAccount/lws-controller-manager":                 {mutators: []resourceMutator{setOperandImagePullSecrets}},
+}
+
+// additionalStaticAssets are operator-owned manifests applied alongside the generated operand manifests.
+var additionalStaticAssets = []string{
+	"assets/lws-controller/configmap.yaml",
+}
+
+// staticApplyOrder orders the manifests within a resource group so that dependencies are created first.
+var staticApplyOrder = []string{
+	"CustomResourceDefinition",
+	"ServiceAccount",
+	"ClusterRole",
+	"ClusterRoleBinding",
+	"Role",
+	"RoleBinding",
+	"ConfigMap",
+	"Service",
+	"Issuer",
+	"Certificate",
+	"MutatingWebhookConfiguration",
+	"ValidatingWebhookConfiguration",
+	"ServiceMonitor",
+	"Deployment",
+}
+
+// staticResource is an embedded manifest together with the rules used to reconcile it. The manifest
+// is decoded once when the resources are loaded; render works on a deep copy.
+type staticResource struct {
+	file      string
+	obj       runtime.Object
+	gvk       schema.GroupVersionKind
+	name      string
+	namespace string
+
+	group         string
+	prune         bool
+	serviceName   string
+	mutators      []resourceMutator
+	openShiftOnly bool
+}
+
+// loadStaticResources reads the embedded manifests and resolves the rules for each of them.
+func loadStaticResources() ([]staticResource, error) {
+	files, err := bindata.AssetNames(operandAssetsDir)
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, additionalStaticAssets...)
+
+	resources := make([]staticResource, 0, len(files))
+	for _, file := range files {
+		if path.Ext(file) != ".yaml" {
+			continue
+		}
+		obj, err := resourceread.ReadGenericWithUnstructured(bindata.MustAsset(file))
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode %s: %w", file, err)
+		}
+		resource, err := newStaticResource(file, obj)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, resource)
+	}
+
+	sort.SliceStable(resources, func(i, j int) bool {
+		oi, oj := applyOrder(resources[i].gvk.Kind), applyOrder(resources[j].gvk.Kind)
+		if oi != oj {
+			return oi < oj
+		}
+		return resources[i].name < resources[j].name
+	})
+	return resources, nil
+}
+
+func newStaticResource(file string, obj runtime.Object) (staticResource, error) {
+	metaObj, ok := obj.(metav1.Object)
+	if !ok {
+		return staticResource{}, fmt.Errorf("%s does not contain an object with metadata", file)
+	}
+	gvk, err := objectGroupVersionKind(obj)
+	if err != nil {
+		return staticResource{}, fmt.Errorf("%s: %w", file, err)
+	}
+
+	resource := staticResource{
+		file:      file,
+		obj:       obj,
+		gvk:       gvk,
+		name:      metaObj.GetName(),
+		namespace: metaObj.GetNamespace(),
+		group:     deploymentResourceGroup,
+	}
+	if rule, ok := staticResourceRules[gvk.GroupKind()]; ok {
+		resource.group = rule.group
+		resource.prune = rule.prune
+		resource.mutators = append(resource.mutators, rule.mutators...)
+		// the operator-owned manifests do not set the namespace
+		if rule.namespaced && resource.namespace == "" {
+			resource.namespace = manifestNamespace
+		}
+	}
+	if override, ok := staticResourceOverrides[gvk.Kind+"/"+resource.name]; ok {
+		if override.group != "" {
+			resource.group = override.group
+		}
+		resource.serviceName = override.serviceName
+		resource.openShiftOnly = override.openShiftOnly
+		resource.mutators = append(resource.mutators, override.mutators...)
+	}
+	return resource, nil
+}
+
+// renderedNamespace returns the namespace the manifest is applied to, empty for cluster-scoped kinds.
+func (r staticResource) renderedNamespace(rc *renderContext) string {
+	if r.namespace == "" {
+		return ""
+	}
+	return rc.namespace
+}
+
+// render copies the decoded manifest and applies the common and configured mutators.
+func (r staticResource) render(rc *renderContext) (runtime.Object, error) {
+	obj := r.obj.DeepCopyObject()
+	obj.GetObjectKind().SetGroupVersionKind(r.gvk)
+
+	metaObj := obj.(metav1.Object)
+	if r.namespace != "" {
+		metaObj.SetNamespace(rc.namespace)
+	}
+	metaObj.SetOwnerReferences([]metav1.OwnerReference{rc.ownerReference})
+	setManagedLabels(metaObj, rc.operatorVersion)
+
+	for _, mutate := range r.mutators {
+		if err := mutate(obj, rc); err != nil {
+			return nil, fmt.Errorf("unable to render %s %s: %w", r.gvk.Kind, r.name, err)
+		}
+	}
+	if r.serviceName != "" {
+		if err := substituteServiceName(obj, r.serviceName, rc.namespace); err != nil {
+			return nil, fmt.Errorf("unable to render %s %s: %w", r.gvk.Kind, r.name, err)
+		}
+	}
+	return rc.configPatches.apply(obj, r.gvk.Kind, r.name)
+}
+
+func staticResourcesInGroup(resources []staticResource, group string) []staticResource {
+	var ret []staticResource
+	for _, resource := range resources {
+		if resource.group == group {
+			ret = append(ret, resource)
+		}
+	}
+	return ret
+}
+
+// staticResourcesForPlatform drops the manifests that only apply to OpenShift on other platforms.
+func staticResourcesForPlatform(resources []staticResource, p platform) []staticResource {
+	if p == platformOpenShift {
+		return resources
+	}
+	var ret []staticResource
+	for _, resource := range resources {
+		if !resource.openShiftOnly {
+			ret = append(ret, resource)
+		}
+	}
+	return ret
+}
+
+func applyOrder(kind string) int {
+	for i, k := range staticApplyOrder {
+		if k == kind {
+			return i
+		}
+	}
+	// unknown kinds are applied right before the Deployment
+	return len(staticApplyOrder) - 1
+}
+
+func objectGroupVersionKind(obj runtime.Object) (schema.GroupVersionKind, error) {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	if gvk.Empty() {
+		return schema.GroupVersionKind{}, fmt.Errorf("manifest does not specify apiVersion and kind")
+	}
+	return gvk, nil
+}
@@ -0,0 +1,162 @@
+package operator
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	leaderworkersetoperatorv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+)
+
+func testRenderContext() *renderContext {
+	return &renderContext{
+		namespace:       "lws-test",
+		targetImage:     "quay.io/example/lws:v1",
+		operatorVersion: "v1.2.3",
+		operator: &leaderworkersetoperatorv1.LeaderWorkerSetOperator{
+			Spec: leaderworkersetoperatorv1.LeaderWorkerSetOperatorSpec{
+				OperatorSpec: operatorv1.OperatorSpec{LogLevel: operatorv1.Debug},
+			},
+		},
+		ownerReference:  metav1.OwnerReference{Kind: "LeaderWorkerSetOperator", Name: "cluster", UID: "uid"},
+		specAnnotations: map[string]string{"secrets/webhook-server-cert": "1"},
+		platform:        platformOpenShift,
+	}
+}
+
+func findStaticResource(t *testing.T, resources []staticResource, kind, name string) staticResource {
+	t.Helper()
+	for _, resource := range resources {
+		if resource.gvk.Kind == kind && resource.name == name {
+			return resource
+		}
+	}
+	t.Fatalf("%s %s is not part of the static resources", kind, name)
+	return staticResource{}
+}
+
+func TestLoadStaticResources(t *testing.T) {
+	resources, err := loadStaticResources()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedGroups := map[string]string{
+		"ClusterRole/lws-manager-role":                                       rbacResourceGroup,
+		"RoleBinding/lws-leader-election-rolebinding":                        rbacResourceGroup,
+		"Issuer/lws-selfsigned-issuer":                                       certificatesResourceGroup,
+		"Certificate/lws-serving-cert":                                       certificatesResourceGroup,
+		"CustomResourceDefinition/leaderworkersets.leaderworkerset.x-k8s.io": crdsResourceGroup,
+		"Service/lws-webhook-service":                                        webhooksResourceGroup,
+		"MutatingWebhookConfiguration/lws-mutating-webhook-configuration":    webhooksResourceGroup,
+		"Service/lws-controller-manager-metrics-service":                     monitoringResourceGroup,
+		"Role/lws-prometheus-k8s":                                            monitoringResourceGroup,
+		"ServiceMonitor/lws-controller-manager-metrics-monitor":              monitoringResourceGroup,
+		"ConfigMap/lws-manager-config":                                       deploymentResourceGroup,
+		"ServiceAccount/lws-controller-manager":                              deploymentResourceGroup,
+		"Deployment/lws-controller-manager":                                  deploymentResourceGroup,
+	}
+	for key, group := range expectedGroups {
+		found := false
+		for _, resource := range resources {
+			if resource.gvk.Kind+"/"+resource.name == key {
+				found = true
+				if resource.group != group {
+					t.Errorf("expected %s in group %s, got %s", key, group, resource.group)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("%s is not part of the static resources", key)
+		}
+	}
+
+	if last := resources[len(resources)-1]; last.gvk.Kind != "Deployment" {
+		t.Errorf("expected the Deployment to be applied last, got %s %s", last.gvk.Kind, last.name)
+	}
+	crd := findStaticResource(t, resources, "CustomResourceDefinition", "leaderworkersets.leaderworkerset.x-k8s.io")
+	if crd.prune {
+		t.Errorf("CRDs must never be pruned")
+	}
+}
+
+func TestRenderStaticResources(t *testing.T) {
+	resources, err := loadStaticResources()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc := testRenderContext()
+
+	for _, resource := range resources {
+		obj, err := resource.render(rc)
+		if err != nil {
+			t.Fatalf("unable to render %s %s: %v", resource.gvk.Kind, resource.name, err)
+		}
+		metaObj := obj.(metav1.Object)
+		namespaced := staticResourceRules[resource.gvk.GroupKind()].namespaced
+		if namespaced && metaObj.GetNamespace() != rc.namespace {
+			t.Errorf("expected %s %s in namespace %s, got %q", resource.gvk.Kind, resource.name, rc.namespace, metaObj.GetNamespace())
+		}
+		if labels := metaObj.GetLabels(); labels[managedByLabel] != managedByLabelValue || labels[operatorVersionLabel] != rc.operatorVersion {
+			t.Errorf("expected %s %s to carry the managed labels, got %v", resource.gvk.Kind, resource.name, labels)
+		}
+		if owners := metaObj.GetOwnerReferences(); len(owners) != 1 || owners[0].UID != rc.ownerReference.UID {
+			t.Errorf("expected %s %s to be owned by the operator, got %v", resource.gvk.Kind, resource.name, owners)
+		}
+	}
+
+	t.Run("cluster role binding subjects", func(t *testing.T) {
+		obj, err := findStaticResource(t, resources, "ClusterRoleBinding", "lws-manager-rolebinding").render(rc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, subject := range obj.(*rbacv1.ClusterRoleBinding).Subjects {
+			if subject.Namespace != rc.namespace {
+				t.Errorf("expected subject %s in namespace %s, got %q", subject.Name, rc.namespace, subject.Namespace)
+			}
+		}
+	})
+
+	t.Run("certificate dns names", func(t *testing.T) {
+		obj, err := findStaticResource(t, resources, "Certificate", "lws-serving-cert").render(rc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dnsNames, _, err := unstructured.NestedStringSlice(obj.(*unstructured.Unstructured).Object, "spec", "dnsNames")
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := []string{"lws-webhook-service.lws-test.svc", "lws-webhook-service.lws-test.svc.cluster.local"}
+		if len(dnsNames) != len(expected) || dnsNames[0] != expected[0] || dnsNames[1] != expected[1] {
+			t.Errorf("expected dns names %v, got %v", expected, dnsNames)
+		}
+	})
+
+	t.Run("operand deployment", func(t *testing.T) {
+		obj, err := findStaticResource(t, resources, "Deployment", operandName).render(rc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		deployment := obj.(*appsv1.Deployment)
+		container := deployment.Spec.Template.Spec.Containers[0]
+		if container.Image != rc.targetImage {
+			t.Errorf("expected image %s, got %s", rc.targetImage, container.Image)
+		}
+		if len(container.Args) != 2 || container.Args[1] != "--zap-log-level=4" {
+			t.Errorf("unexpected args %v", container.Args)
+		}
+		if deployment.Spec.Template.Annotations["secrets/webhook-server-cert"] != "1" {
+			t.Errorf("expected spec annotations on the pod template, got %v", deployment.Spec.Template.Annotations)
+		}
+		if securityContext := deployment.Spec.Template.Spec.SecurityContext; securityContext == nil || securityContext.SeccompProfile == nil ||
+			securityContext.SeccompProfile.Type != corev1.SeccompProfileTypeRuntimeDefault {
+			t.Errorf("expected the RuntimeDefault seccomp profile required by the restricted pod security level, got %v", securityContext)
+		}
+	})
+}
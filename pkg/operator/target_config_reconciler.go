@@ -2,40 +2,40 @@ package operator
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"strings"
 	"time"
 
-	admissionv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	rbacv1 "k8s.io/api/rbac/v1"
-	apiextensionv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
-	apiextclientv1 "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apiextensionsv1informer "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/kubernetes"
 	appsv1lister "k8s.io/client-go/listers/apps/v1"
 	v1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
 	"k8s.io/utils/ptr"
 
 	operatorv1 "github.com/openshift/api/operator/v1"
 	"github.com/openshift/library-go/pkg/controller/factory"
 	"github.com/openshift/library-go/pkg/operator/events"
-	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
-	"github.com/openshift/library-go/pkg/operator/resource/resourcemerge"
-	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
 	"github.com/openshift/library-go/pkg/operator/v1helpers"
 
-	"github.com/openshift/lws-operator/bindata"
-	leaderworkersetapiv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
-	leaderworkersetoperatorv1clientset "github.com/openshift/lws-operator/pkg/generated/clientset/versioned/typed/leaderworkersetoperator/v1"
 	operatorclientinformers "github.com/openshift/lws-operator/pkg/generated/informers/externalversions/leaderworkersetoperator/v1"
+	leaderworkersetoperatorv1lister "github.com/openshift/lws-operator/pkg/generated/listers/leaderworkersetoperator/v1"
 	"github.com/openshift/lws-operator/pkg/operator/operatorclient"
+	"github.com/openshift/lws-operator/pkg/version"
 )
 
 const (
@@ -49,60 +49,108 @@ const (
 
 type TargetConfigReconciler struct {
 	targetImage                   string
-	operatorClient                leaderworkersetoperatorv1clientset.LeaderWorkerSetOperatorInterface
+	operatorLister                leaderworkersetoperatorv1lister.LeaderWorkerSetOperatorLister
 	dynamicClient                 dynamic.Interface
-	discoveryClient               discovery.DiscoveryInterface
+	discoveryClient               discovery.CachedDiscoveryInterface
 	leaderWorkerSetOperatorClient *operatorclient.LeaderWorkerSetClient
 	kubeClient                    kubernetes.Interface
-	apiextensionClient            *apiextclientv1.Clientset
 	eventRecorder                 events.Recorder
 	kubeInformersForNamespaces    v1helpers.KubeInformersForNamespaces
 	secretLister                  v1.SecretLister
+	configMapLister               v1.ConfigMapLister
 	deploymentsLister             appsv1lister.DeploymentLister
-	namespace                     string
-	resourceCache                 resourceapply.ResourceCache
+	podLister                     v1.PodLister
+	// managedObjectListers cache the objects of the embedded manifests carrying the managed-by label.
+	managedObjectListers map[schema.GroupVersionResource]cache.GenericLister
+	// namespace is the operand namespace the informers were started for.
+	namespace         string
+	operatorNamespace string
+	platform          platform
+	// restart exits the operator so the informers are recreated for a new operand namespace.
+	restart func(reason string)
+	// clock ends the debug windows of the operator CR.
+	clock clock.PassiveClock
+	// staticResources are decoded once from the embedded manifests.
+	staticResources []staticResource
+	// operatorVersion labels the applied objects, see operatorVersionLabel.
+	operatorVersion string
+	// garbageCollected is set once stale objects have been deleted after a successful sync.
+	garbageCollected bool
+	// monitoringResourcesRemoved is set once the monitoring resources have been deleted while the
+	// Prometheus Operator API is not served.
+	monitoringResourcesRemoved bool
 }
 
 func NewTargetConfigReconciler(
 	targetImage string,
+	operatorNamespace string,
 	namespace string,
-	operatorConfigClient leaderworkersetoperatorv1clientset.LeaderWorkerSetOperatorInterface,
+	platform platform,
+	restart func(reason string),
 	operatorClientInformer operatorclientinformers.LeaderWorkerSetOperatorInformer,
 	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	crdInformer apiextensionsv1informer.CustomResourceDefinitionInformer,
+	managedObjectInformers dynamicinformer.DynamicSharedInformerFactory,
 	leaderWorkerSetOperatorClient *operatorclient.LeaderWorkerSetClient,
 	dynamicClient dynamic.Interface,
-	discoveryClient discovery.DiscoveryInterface,
+	discoveryClient discovery.CachedDiscoveryInterface,
 	kubeClient kubernetes.Interface,
-	apiExtensionClient *apiextclientv1.Clientset,
 	eventRecorder events.Recorder,
-) factory.Controller {
+) (factory.Controller, error) {
+	staticResources, err := loadStaticResources()
+	if err != nil {
+		return nil, err
+	}
+
 	c := &TargetConfigReconciler{
-		operatorClient:                operatorConfigClient,
+		operatorLister:                operatorClientInformer.Lister(),
 		dynamicClient:                 dynamicClient,
 		leaderWorkerSetOperatorClient: leaderWorkerSetOperatorClient,
 		kubeClient:                    kubeClient,
 		discoveryClient:               discoveryClient,
-		apiextensionClient:            apiExtensionClient,
 		eventRecorder:                 eventRecorder,
 		kubeInformersForNamespaces:    kubeInformersForNamespaces,
 		secretLister:                  kubeInformersForNamespaces.SecretLister(),
+		configMapLister:               kubeInformersForNamespaces.ConfigMapLister(),
 		deploymentsLister:             kubeInformersForNamespaces.InformersFor(namespace).Apps().V1().Deployments().Lister(),
+		podLister:                     kubeInformersForNamespaces.InformersFor(namespace).Core().V1().Pods().Lister(),
 		targetImage:                   targetImage,
+		operatorVersion:               operatorVersionLabelValue(version.Get().GitVersion),
 		namespace:                     namespace,
-		resourceCache:                 resourceapply.NewResourceCache(),
+		operatorNamespace:             operatorNamespace,
+		platform:                      platform,
+		restart:                       restart,
+		clock:                         clock.RealClock{},
+		staticResources:               staticResourcesForPlatform(staticResources, platform),
+	}
+
+	// the discovery cache only needs to be refreshed when a CRD adds or removes served resources,
+	// e.g. when cert-manager or the prometheus operator gets installed
+	if _, err := crdInformer.Informer().AddEventHandler(newDiscoveryInvalidationHandler(discoveryClient)); err != nil {
+		return nil, err
 	}
 
-	return factory.New().WithInformers(
+	informers := []factory.Informer{
 		// for the operator changes
-		operatorClientInformer.Informer(),
+		specChangesInformer{operatorClientInformer.Informer()},
 		// for the deployment and its configmap and secret
 		kubeInformersForNamespaces.InformersFor(namespace).Apps().V1().Deployments().Informer(),
 		kubeInformersForNamespaces.InformersFor(namespace).Core().V1().ConfigMaps().Informer(),
 		kubeInformersForNamespaces.InformersFor(namespace).Core().V1().Secrets().Informer(),
-	).ResyncEvery(time.Minute*5).
+		// for the operand CRDs and the dependencies registered through CRDs
+		crdInformer.Informer(),
+	}
+	// for the edits of the applied objects, which are only applied again when they drift
+	c.managedObjectListers = make(map[schema.GroupVersionResource]cache.GenericLister)
+	for _, gvr := range cachedManagedResources(c.staticResources) {
+		informer := managedObjectInformers.ForResource(gvr)
+		c.managedObjectListers[gvr] = informer.Lister()
+		informers = append(informers, informer.Informer())
+	}
+
+	return factory.New().WithInformers(informers...).ResyncEvery(time.Minute*5).
 		WithSync(c.sync).
-		WithSyncDegradedOnError(leaderWorkerSetOperatorClient).
-		ToController("TargetConfigController", eventRecorder)
+		ToController("TargetConfigController", eventRecorder), nil
 }
 
 func (c *TargetConfigReconciler) sync(ctx context.Context, syncCtx factory.SyncContext) error {
@@ -113,37 +161,17 @@ func (c *TargetConfigReconciler) sync(ctx context.Context, syncCtx factory.SyncC
 	if spec.ManagementState != "" && spec.ManagementState != operatorv1.Managed {
 		return nil
 	}
-	{
-		deployment, getDeploymentErr := c.deploymentsLister.Deployments(c.namespace).Get(operandName)
-		_, _, err := v1helpers.UpdateStatus(ctx, c.leaderWorkerSetOperatorClient, v1helpers.UpdateConditionFn(constructAvailableCondition(getDeploymentErr, deployment)))
-		if err != nil {
-			return fmt.Errorf("failed to update status condition: %w", err)
-		}
-	}
-	found, err := isResourceRegistered(c.discoveryClient, schema.GroupVersionKind{
-		Group:   "cert-manager.io",
-		Version: "v1",
-		Kind:    "Issuer",
-	})
+
+	leaderWorkerSetOperator, err := c.operatorLister.Get(operatorclient.OperatorConfigName)
 	if err != nil {
-		return fmt.Errorf("unable to check cert-manager is installed: %w", err)
-	}
-	if !found {
-		_, _, err = v1helpers.UpdateStatus(ctx, c.leaderWorkerSetOperatorClient, v1helpers.UpdateConditionFn(operatorv1.OperatorCondition{
-			Type:    operatorv1.OperatorStatusTypeDegraded,
-			Status:  operatorv1.ConditionTrue,
-			Reason:  "MissingDependency",
-			Message: "please make sure that cert-manager is installed on your cluster",
-		}))
-		if err != nil {
-			return fmt.Errorf("failed to update status for cert-manager operator: %w", err)
-		}
-		return fmt.Errorf("please make sure that cert-manager is installed on your cluster")
+		return fmt.Errorf("unable to get operator configuration %s: %w", operatorclient.OperatorConfigName, err)
 	}
 
-	leaderWorkerSetOperator, err := c.operatorClient.Get(ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
-	if err != nil {
-		return fmt.Errorf("unable to get operator configuration %s/%s: %w", c.namespace, operatorclient.OperatorConfigName, err)
+	// the informers only cover the operand namespace the operator was started with
+	if namespace := operandNamespace(leaderWorkerSetOperator, c.operatorNamespace); namespace != c.namespace {
+		c.eventRecorder.Eventf("OperandNamespaceChanged", "Operand namespace changed from %s to %s, restarting the operator", c.namespace, namespace)
+		c.restart(fmt.Sprintf("operand namespace changed from %s to %s", c.namespace, namespace))
+		return nil
 	}
 
 	ownerReference := metav1.OwnerReference{
@@ -153,157 +181,243 @@ func (c *TargetConfigReconciler) sync(ctx context.Context, syncCtx factory.SyncC
 		UID:        leaderWorkerSetOperator.UID,
 	}
 
-	specAnnotations := make(map[string]string)
-
-	_, _, err = c.manageClusterRoleManager(ctx, ownerReference)
-	if err != nil {
-		return err
-	}
-
-	_, _, err = c.manageClusterRoleMetrics(ctx, ownerReference)
-	if err != nil {
-		return err
-	}
-
-	_, _, err = c.manageClusterRoleProxy(ctx, ownerReference)
-	if err != nil {
-		return err
-	}
-
-	_, _, err = c.manageClusterRoleBindingManager(ctx, ownerReference)
-	if err != nil {
-		return err
-	}
-
-	_, _, err = c.manageClusterRoleBindingMetrics(ctx, ownerReference)
-	if err != nil {
-		return err
-	}
-
-	_, _, err = c.manageClusterRoleBindingProxy(ctx, ownerReference)
-	if err != nil {
-		return err
-	}
-
-	_, _, err = c.manageRole(ctx, ownerReference)
-	if err != nil {
-		return err
+	resources := c.staticResources
+	rc := &renderContext{
+		namespace:         c.namespace,
+		operatorNamespace: c.operatorNamespace,
+		targetImage:       c.targetImage,
+		operatorVersion:   c.operatorVersion,
+		operator:          leaderWorkerSetOperator,
+		ownerReference:    ownerReference,
+		specAnnotations:   make(map[string]string),
+		platform:          c.platform,
+		debug:             activeDebug(leaderWorkerSetOperator.Spec.Debug, c.clock.Now()),
+		configPatches:     parseConfigPatches(leaderWorkerSetOperator.Spec.UnsupportedConfigOverrides),
+		generations:       newAppliedGenerations(leaderWorkerSetOperator.Status.Generations),
+	}
+	rc.configPatches.dryRun(resources, rc)
+
+	var errs []error
+	var deployment *appsv1.Deployment
+	var monitoringAvailable operatorv1.OperatorCondition
+	groups := []resourceGroup{
+		{
+			name:  namespaceResourceGroup,
+			apply: func(ctx context.Context) error { return c.manageOperandNamespace(ctx, rc) },
+		},
+		{
+			name: rbacResourceGroup,
+			apply: func(ctx context.Context) error {
+				return c.manageStaticResourceGroup(ctx, resources, rbacResourceGroup, rc)
+			},
+		},
+		{
+			name:  certificatesResourceGroup,
+			apply: func(ctx context.Context) error { return c.manageCertificates(ctx, resources, rc) },
+		},
+		{
+			name: crdsResourceGroup,
+			apply: func(ctx context.Context) error {
+				return c.manageStaticResourceGroup(ctx, resources, crdsResourceGroup, rc)
+			},
+		},
+		{
+			name: webhooksResourceGroup,
+			apply: func(ctx context.Context) error {
+				return c.manageStaticResourceGroup(ctx, resources, webhooksResourceGroup, rc)
+			},
+		},
+		{
+			name: monitoringResourceGroup,
+			apply: func(ctx context.Context) error {
+				var err error
+				monitoringAvailable, err = c.manageMonitoring(ctx, resources, rc)
+				return err
+			},
+		},
+		{
+			name: deploymentResourceGroup,
+			apply: func(ctx context.Context) error {
+				var err error
+				deployment, err = c.manageOperand(ctx, resources, rc)
+				return err
+			},
+		},
+		{
+			name: garbageCollectionResourceGroup,
+			// the embedded manifests only change with the operator binary, so stale objects are collected
+			// once after the first successful sync of this operator version
+			apply: func(ctx context.Context) error {
+				if len(errs) > 0 || c.garbageCollected {
+					return nil
+				}
+				if err := c.collectGarbage(ctx, resources, rc); err != nil {
+					return err
+				}
+				c.garbageCollected = true
+				return nil
+			},
+		},
+	}
+
+	statusUpdates := make([]v1helpers.UpdateStatusFunc, 0, len(groups)+4)
+	degradedGroups := make([]operatorv1.OperatorCondition, 0, len(groups))
+	for _, group := range groups {
+		groupErr := group.apply(ctx)
+		condition := group.degradedCondition(groupErr)
+		statusUpdates = append(statusUpdates, v1helpers.UpdateConditionFn(condition))
+		if groupErr != nil {
+			degradedGroups = append(degradedGroups, condition)
+			errs = append(errs, fmt.Errorf("%s: %w", group.name, groupErr))
+		}
 	}
-
-	_, _, err = c.manageRoleMonitoring(ctx, ownerReference)
-	if err != nil {
-		return err
+	statusUpdates = append(statusUpdates,
+		v1helpers.UpdateConditionFn(constructDegradedCondition(degradedGroups)),
+		v1helpers.UpdateConditionFn(monitoringAvailable),
+		v1helpers.UpdateConditionFn(unsupportedOverridesCondition(leaderWorkerSetOperator.Spec.Overrides)),
+		v1helpers.UpdateConditionFn(configPatchesCondition(rc.configPatches)),
+		rc.generations.setGenerations(),
+		removeSyncDegradedCondition,
+	)
+	debugStatus, recordDebugEvent := c.manageDebug(syncCtx, leaderWorkerSetOperator, rc.debug)
+
+	var operandStatusUpdates []operatorclient.UpdateStatusFunc
+	if deployment != nil {
+		statusUpdates = append(statusUpdates, func(status *operatorv1.OperatorStatus) error {
+			status.ReadyReplicas = deployment.Status.AvailableReplicas
+			return nil
+		}, v1helpers.UpdateConditionFn(constructAvailableCondition(nil, deployment)))
+
+		digest := imageDigest(rc.targetImage)
+		if digest == "" {
+			digest = runningImageDigest(c.podLister, deployment, rc.targetImage)
+		}
+		operandStatusUpdates = append(operandStatusUpdates, setOperandImageStatus(rc.targetImage, digest))
+	} else {
+		current, getDeploymentErr := c.deploymentsLister.Deployments(c.namespace).Get(operandName)
+		statusUpdates = append(statusUpdates, v1helpers.UpdateConditionFn(constructAvailableCondition(getDeploymentErr, current)))
 	}
 
-	_, _, err = c.manageRoleBinding(ctx, ownerReference)
-	if err != nil {
-		return err
+	if c.garbageCollected {
+		operandStatusUpdates = append(operandStatusUpdates, setOperandNamespaceStatus(rc.namespace))
 	}
-
-	_, _, err = c.manageRoleBindingMonitoring(ctx, ownerReference)
-	if err != nil {
-		return err
+	operandStatusUpdates = append(operandStatusUpdates,
+		debugStatus,
+		setRelatedObjects(relatedObjects(resources, rc, c.operatorNamespace, monitoringAvailable.Status == operatorv1.ConditionTrue)),
+		operatorclient.OperatorStatusFuncs(statusUpdates...),
+	)
+	if _, err := c.leaderWorkerSetOperatorClient.UpdateStatus(ctx, operandStatusUpdates...); err != nil {
+		errs = append(errs, fmt.Errorf("failed to update status: %w", err))
+	} else {
+		recordDebugEvent()
 	}
 
-	_, _, err = c.manageServiceWebhook(ctx, ownerReference)
-	if err != nil {
-		return err
-	}
+	return utilerrors.NewAggregate(errs)
+}
 
-	_, _, err = c.manageIssuerCR(ctx, ownerReference)
-	if err != nil {
-		return err
-	}
+// manageStaticResourceGroup applies the embedded manifests of a resource group.
+func (c *TargetConfigReconciler) manageStaticResourceGroup(ctx context.Context, resources []staticResource, group string, rc *renderContext) error {
+	_, err := c.applyStaticResources(ctx, staticResourcesInGroup(resources, group), rc)
+	return err
+}
 
-	_, _, err = c.manageCertificateWebhookCR(ctx, ownerReference)
+// manageCertificates applies the cert-manager Issuer and Certificates and checks that
+// cert-manager has populated the resulting secrets.
+func (c *TargetConfigReconciler) manageCertificates(ctx context.Context, resources []staticResource, rc *renderContext) error {
+	found, err := isResourceRegistered(c.discoveryClient, schema.GroupVersionKind{
+		Group:   "cert-manager.io",
+		Version: "v1",
+		Kind:    "Issuer",
+	})
 	if err != nil {
-		return err
+		return fmt.Errorf("unable to check cert-manager is installed: %w", err)
 	}
-
-	webhookSecret, _, err := c.checkSecretReady(WebhookCertificateSecretName)
-	if err != nil {
-		return err
+	if !found {
+		return &degradedError{
+			reason: "MissingDependency",
+			err:    fmt.Errorf("please make sure that cert-manager is installed on your cluster"),
+		}
 	}
-	specAnnotations["secrets/"+webhookSecret.Name] = webhookSecret.ResourceVersion
 
-	_, _, err = c.manageCertificateMetricsCR(ctx, ownerReference)
-	if err != nil {
-		return err
+	var errs []error
+	if _, err := c.applyStaticResources(ctx, staticResourcesInGroup(resources, certificatesResourceGroup), rc); err != nil {
+		errs = append(errs, err)
 	}
-
-	metricsSecret, _, err := c.checkSecretReady(MetricsCertificateSecretName)
-	if err != nil {
-		return err
+	for _, secretName := range []string{WebhookCertificateSecretName, MetricsCertificateSecretName} {
+		if _, _, err := c.checkSecretReady(secretName); err != nil {
+			errs = append(errs, err)
+		}
 	}
-	specAnnotations["secrets/"+metricsSecret.Name] = metricsSecret.ResourceVersion
+	return utilerrors.NewAggregate(errs)
+}
 
-	configMap, _, err := c.manageConfigmap(ctx, ownerReference)
+// manageOperand applies the operand ConfigMap, ServiceAccount and Deployment. The Deployment is only
+// applied once the certificate secrets it mounts and the objects referenced by spec.operand are ready,
+// since their resource versions or content hashes are recorded in the pod template annotations to roll
+// the pods on rotation.
+func (c *TargetConfigReconciler) manageOperand(ctx context.Context, resources []staticResource, rc *renderContext) (*appsv1.Deployment, error) {
+	// the running Deployment is left alone until a valid image and logging configuration are set
+	image, err := resolveOperandImage(rc.operator.Spec.Operand, c.targetImage)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	specAnnotations["configmaps/"+configMap.Name] = configMap.ResourceVersion
-
-	_, _, err = c.manageCustomResourceDefinition(ctx, ownerReference)
-	if err != nil {
-		return err
+	rc.targetImage = image
+	if _, err := operandLogArgs(operandLogLevel(rc), rc.operator.Spec.OperandLogging); err != nil {
+		return nil, err
 	}
 
-	_, _, err = c.manageServiceAccount(ctx, ownerReference)
-	if err != nil {
-		return err
+	for _, secretName := range []string{WebhookCertificateSecretName, MetricsCertificateSecretName} {
+		secret, _, err := c.checkSecretReady(secretName)
+		if err != nil {
+			return nil, fmt.Errorf("waiting for certificates: %w", err)
+		}
+		rc.specAnnotations["secrets/"+secret.Name] = secret.ResourceVersion
 	}
 
-	_, _, err = c.manageServiceController(ctx, ownerReference)
+	referenceAnnotations, err := c.operandReferenceAnnotations(rc.namespace, rc.operator.Spec.Operand)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	maps.Copy(rc.specAnnotations, referenceAnnotations)
 
-	_, _, err = c.manageMutatingWebhook(ctx, ownerReference)
-	if err != nil {
-		return err
+	var dependencies, deployments []staticResource
+	for _, resource := range staticResourcesInGroup(resources, deploymentResourceGroup) {
+		if resource.gvk.Kind == "Deployment" {
+			deployments = append(deployments, resource)
+		} else {
+			dependencies = append(dependencies, resource)
+		}
 	}
 
-	_, _, err = c.manageValidatingWebhook(ctx, ownerReference)
+	applied, err := c.applyStaticResources(ctx, dependencies, rc)
 	if err != nil {
-		return err
+		return nil, err
 	}
-
-	_, _, err = c.manageServiceMonitor(ctx, ownerReference)
-	if err != nil {
-		return err
+	for _, obj := range applied {
+		if obj.GetKind() == "ConfigMap" {
+			rc.specAnnotations["configmaps/"+obj.GetName()] = obj.GetResourceVersion()
+		}
 	}
 
-	deployment, _, err := c.manageDeployments(ctx, leaderWorkerSetOperator, ownerReference, specAnnotations)
+	applied, err = c.applyStaticResources(ctx, deployments, rc)
 	if err != nil {
-		return err
+		return nil, err
 	}
-
-	_, _, err = v1helpers.UpdateStatus(ctx, c.leaderWorkerSetOperatorClient, func(status *operatorv1.OperatorStatus) error {
-		resourcemerge.SetDeploymentGeneration(&status.Generations, deployment)
-		status.ReadyReplicas = deployment.Status.AvailableReplicas
-		return nil
-	}, v1helpers.UpdateConditionFn(constructAvailableCondition(nil, deployment)),
-		v1helpers.UpdateConditionFn(operatorv1.OperatorCondition{
-			Type:   operatorv1.OperatorStatusTypeDegraded,
-			Status: operatorv1.ConditionFalse,
-			Reason: "AsExpected",
-		}))
-
-	return err
-}
-
-func (c *TargetConfigReconciler) manageConfigmap(ctx context.Context, ownerReference metav1.OwnerReference) (*corev1.ConfigMap, bool, error) {
-	configData := bindata.MustAsset("assets/lws-controller-config/config.yaml")
-	required := resourceread.ReadConfigMapV1OrDie(bindata.MustAsset("assets/lws-controller/configmap.yaml"))
-	required.Namespace = c.namespace
-	required.OwnerReferences = []metav1.OwnerReference{
-		ownerReference,
+	for _, obj := range applied {
+		if obj.GetKind() != "Deployment" || obj.GetName() != operandName {
+			continue
+		}
+		deployment := &appsv1.Deployment{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, deployment); err != nil {
+			return nil, err
+		}
+		return deployment, nil
 	}
-	required.Data = map[string]string{
-		"controller_manager_config.yaml": string(configData),
+	if rc.unmanaged(schema.GroupKind{Group: "apps", Kind: "Deployment"}, rc.namespace, operandName) {
+		// the status is reported from the Deployment in the lister
+		return nil, nil
 	}
-
-	return resourceapply.ApplyConfigMap(ctx, c.kubeClient.CoreV1(), c.eventRecorder, required)
+	return nil, fmt.Errorf("operand deployment %s is not part of the operand manifests", operandName)
 }
 
 func (c *TargetConfigReconciler) checkSecretReady(secretName string) (*corev1.Secret, bool, error) {
@@ -318,409 +432,6 @@ func (c *TargetConfigReconciler) checkSecretReady(secretName string) (*corev1.Se
 	return secret, false, nil
 }
 
-func (c *TargetConfigReconciler) manageRole(ctx context.Context, ownerReference metav1.OwnerReference) (*rbacv1.Role, bool, error) {
-	required := resourceread.ReadRoleV1OrDie(bindata.MustAsset("assets/lws-controller-generated/rbac.authorization.k8s.io_v1_role_lws-leader-election-role.yaml"))
-	required.Namespace = c.namespace
-	required.OwnerReferences = []metav1.OwnerReference{
-		ownerReference,
-	}
-
-	return resourceapply.ApplyRole(ctx, c.kubeClient.RbacV1(), c.eventRecorder, required)
-}
-
-func (c *TargetConfigReconciler) manageRoleMonitoring(ctx context.Context, ownerReference metav1.OwnerReference) (*rbacv1.Role, bool, error) {
-	required := resourceread.ReadRoleV1OrDie(bindata.MustAsset("assets/lws-controller-generated/rbac.authorization.k8s.io_v1_role_lws-prometheus-k8s.yaml"))
-	required.Namespace = c.namespace
-	required.OwnerReferences = []metav1.OwnerReference{
-		ownerReference,
-	}
-
-	return resourceapply.ApplyRole(ctx, c.kubeClient.RbacV1(), c.eventRecorder, required)
-}
-
-func (c *TargetConfigReconciler) manageRoleBinding(ctx context.Context, ownerReference metav1.OwnerReference) (*rbacv1.RoleBinding, bool, error) {
-	required := resourceread.ReadRoleBindingV1OrDie(bindata.MustAsset("assets/lws-controller-generated/rbac.authorization.k8s.io_v1_rolebinding_lws-leader-election-rolebinding.yaml"))
-	required.Namespace = c.namespace
-	required.OwnerReferences = []metav1.OwnerReference{
-		ownerReference,
-	}
-
-	for i := range required.Subjects {
-		required.Subjects[i].Namespace = c.namespace
-	}
-
-	return resourceapply.ApplyRoleBinding(ctx, c.kubeClient.RbacV1(), c.eventRecorder, required)
-}
-
-func (c *TargetConfigReconciler) manageRoleBindingMonitoring(ctx context.Context, ownerReference metav1.OwnerReference) (*rbacv1.RoleBinding, bool, error) {
-	required := resourceread.ReadRoleBindingV1OrDie(bindata.MustAsset("assets/lws-controller-generated/rbac.authorization.k8s.io_v1_rolebinding_lws-prometheus-k8s.yaml"))
-	required.Namespace = c.namespace
-	required.OwnerReferences = []metav1.OwnerReference{
-		ownerReference,
-	}
-
-	return resourceapply.ApplyRoleBinding(ctx, c.kubeClient.RbacV1(), c.eventRecorder, required)
-}
-
-func (c *TargetConfigReconciler) manageClusterRoleManager(ctx context.Context, ownerReference metav1.OwnerReference) (*rbacv1.ClusterRole, bool, error) {
-	required := resourceread.ReadClusterRoleV1OrDie(bindata.MustAsset("assets/lws-controller-generated/rbac.authorization.k8s.io_v1_clusterrole_lws-manager-role.yaml"))
-	required.OwnerReferences = []metav1.OwnerReference{
-		ownerReference,
-	}
-
-	return resourceapply.ApplyClusterRole(ctx, c.kubeClient.RbacV1(), c.eventRecorder, required)
-}
-
-func (c *TargetConfigReconciler) manageClusterRoleMetrics(ctx context.Context, ownerReference metav1.OwnerReference) (*rbacv1.ClusterRole, bool, error) {
-	required := resourceread.ReadClusterRoleV1OrDie(bindata.MustAsset("assets/lws-controller-generated/rbac.authorization.k8s.io_v1_clusterrole_lws-metrics-reader.yaml"))
-	required.OwnerReferences = []metav1.OwnerReference{
-		ownerReference,
-	}
-
-	return resourceapply.ApplyClusterRole(ctx, c.kubeClient.RbacV1(), c.eventRecorder, required)
-}
-
-func (c *TargetConfigReconciler) manageClusterRoleProxy(ctx context.Context, ownerReference metav1.OwnerReference) (*rbacv1.ClusterRole, bool, error) {
-	required := resourceread.ReadClusterRoleV1OrDie(bindata.MustAsset("assets/lws-controller-generated/rbac.authorization.k8s.io_v1_clusterrole_lws-proxy-role.yaml"))
-	required.OwnerReferences = []metav1.OwnerReference{
-		ownerReference,
-	}
-
-	return resourceapply.ApplyClusterRole(ctx, c.kubeClient.RbacV1(), c.eventRecorder, required)
-}
-
-func (c *TargetConfigReconciler) manageClusterRoleBindingManager(ctx context.Context, ownerReference metav1.OwnerReference) (*rbacv1.ClusterRoleBinding, bool, error) {
-	required := resourceread.ReadClusterRoleBindingV1OrDie(bindata.MustAsset("assets/lws-controller-generated/rbac.authorization.k8s.io_v1_clusterrolebinding_lws-manager-rolebinding.yaml"))
-	required.OwnerReferences = []metav1.OwnerReference{
-		ownerReference,
-	}
-
-	for i := range required.Subjects {
-		required.Subjects[i].Namespace = c.namespace
-	}
-
-	return resourceapply.ApplyClusterRoleBinding(ctx, c.kubeClient.RbacV1(), c.eventRecorder, required)
-}
-
-func (c *TargetConfigReconciler) manageClusterRoleBindingMetrics(ctx context.Context, ownerReference metav1.OwnerReference) (*rbacv1.ClusterRoleBinding, bool, error) {
-	required := resourceread.ReadClusterRoleBindingV1OrDie(bindata.MustAsset("assets/lws-controller-generated/rbac.authorization.k8s.io_v1_clusterrolebinding_lws-metrics-reader-rolebinding.yaml"))
-	required.OwnerReferences = []metav1.OwnerReference{
-		ownerReference,
-	}
-
-	for i := range required.Subjects {
-		required.Subjects[i].Namespace = c.namespace
-	}
-
-	return resourceapply.ApplyClusterRoleBinding(ctx, c.kubeClient.RbacV1(), c.eventRecorder, required)
-}
-
-func (c *TargetConfigReconciler) manageClusterRoleBindingProxy(ctx context.Context, ownerReference metav1.OwnerReference) (*rbacv1.ClusterRoleBinding, bool, error) {
-	required := resourceread.ReadClusterRoleBindingV1OrDie(bindata.MustAsset("assets/lws-controller-generated/rbac.authorization.k8s.io_v1_clusterrolebinding_lws-proxy-rolebinding.yaml"))
-	required.OwnerReferences = []metav1.OwnerReference{
-		ownerReference,
-	}
-
-	for i := range required.Subjects {
-		required.Subjects[i].Namespace = c.namespace
-	}
-
-	return resourceapply.ApplyClusterRoleBinding(ctx, c.kubeClient.RbacV1(), c.eventRecorder, required)
-}
-
-func (c *TargetConfigReconciler) manageIssuerCR(ctx context.Context, ownerReference metav1.OwnerReference) (*unstructured.Unstructured, bool, error) {
-	gvr := schema.GroupVersionResource{
-		Group:    "cert-manager.io",
-		Version:  "v1",
-		Resource: "issuers",
-	}
-
-	issuer, err := resourceread.ReadGenericWithUnstructured(bindata.MustAsset("assets/lws-controller-generated/cert-manager.io_v1_issuer_lws-selfsigned-issuer.yaml"))
-	if err != nil {
-		return nil, false, err
-	}
-	issuerAsUnstructured, ok := issuer.(*unstructured.Unstructured)
-	if !ok {
-		return nil, false, fmt.Errorf("issuer is not an Unstructured")
-	}
-	issuerAsUnstructured.SetNamespace(c.namespace)
-	ownerReferences := issuerAsUnstructured.GetOwnerReferences()
-	ownerReferences = append(ownerReferences, ownerReference)
-	issuerAsUnstructured.SetOwnerReferences(ownerReferences)
-
-	return resourceapply.ApplyUnstructuredResourceImproved(ctx, c.dynamicClient, c.eventRecorder, issuerAsUnstructured, c.resourceCache, gvr, nil, nil)
-}
-
-func (c *TargetConfigReconciler) manageCertificateWebhookCR(ctx context.Context, ownerReference metav1.OwnerReference) (*unstructured.Unstructured, bool, error) {
-	gvr := schema.GroupVersionResource{
-		Group:    "cert-manager.io",
-		Version:  "v1",
-		Resource: "certificates",
-	}
-
-	service := resourceread.ReadServiceV1OrDie(bindata.MustAsset("assets/lws-controller-generated/v1_service_lws-webhook-service.yaml"))
-	issuer, err := resourceread.ReadGenericWithUnstructured(bindata.MustAsset("assets/lws-controller-generated/cert-manager.io_v1_certificate_lws-serving-cert.yaml"))
-	if err != nil {
-		return nil, false, err
-	}
-	issuerAsUnstructured, ok := issuer.(*unstructured.Unstructured)
-	if !ok {
-		return nil, false, fmt.Errorf("issuer is not an Unstructured")
-	}
-	issuerAsUnstructured.SetNamespace(c.namespace)
-	ownerReferences := issuerAsUnstructured.GetOwnerReferences()
-	ownerReferences = append(ownerReferences, ownerReference)
-	issuerAsUnstructured.SetOwnerReferences(ownerReferences)
-	dnsNames, found, err := unstructured.NestedStringSlice(issuerAsUnstructured.Object, "spec", "dnsNames")
-	if !found || err != nil {
-		return nil, false, fmt.Errorf("%v: .spec.dnsNames not found: %v", issuerAsUnstructured.GetName(), err)
-	}
-	for i := range dnsNames {
-		dnsNames[i] = strings.Replace(dnsNames[i], "SERVICE_NAME", service.Name, 1)
-		dnsNames[i] = strings.Replace(dnsNames[i], "SERVICE_NAMESPACE", c.namespace, 1)
-	}
-	err = unstructured.SetNestedStringSlice(issuerAsUnstructured.Object, dnsNames, "spec", "dnsNames")
-	if err != nil {
-		return nil, false, err
-	}
-	return resourceapply.ApplyUnstructuredResourceImproved(ctx, c.dynamicClient, c.eventRecorder, issuerAsUnstructured, c.resourceCache, gvr, nil, nil)
-}
-
-func (c *TargetConfigReconciler) manageCertificateMetricsCR(ctx context.Context, ownerReference metav1.OwnerReference) (*unstructured.Unstructured, bool, error) {
-	gvr := schema.GroupVersionResource{
-		Group:    "cert-manager.io",
-		Version:  "v1",
-		Resource: "certificates",
-	}
-
-	service := resourceread.ReadServiceV1OrDie(bindata.MustAsset("assets/lws-controller-generated/v1_service_lws-controller-manager-metrics-service.yaml"))
-	issuer, err := resourceread.ReadGenericWithUnstructured(bindata.MustAsset("assets/lws-controller-generated/cert-manager.io_v1_certificate_lws-metrics-cert.yaml"))
-	if err != nil {
-		return nil, false, err
-	}
-	issuerAsUnstructured, ok := issuer.(*unstructured.Unstructured)
-	if !ok {
-		return nil, false, fmt.Errorf("issuer is not an Unstructured")
-	}
-	issuerAsUnstructured.SetNamespace(c.namespace)
-	ownerReferences := issuerAsUnstructured.GetOwnerReferences()
-	ownerReferences = append(ownerReferences, ownerReference)
-	issuerAsUnstructured.SetOwnerReferences(ownerReferences)
-	dnsNames, found, err := unstructured.NestedStringSlice(issuerAsUnstructured.Object, "spec", "dnsNames")
-	if !found || err != nil {
-		return nil, false, fmt.Errorf("%v: .spec.dnsNames not found: %v", issuerAsUnstructured.GetName(), err)
-	}
-	for i := range dnsNames {
-		dnsNames[i] = strings.Replace(dnsNames[i], "SERVICE_NAME", service.Name, 1)
-		dnsNames[i] = strings.Replace(dnsNames[i], "SERVICE_NAMESPACE", c.namespace, 1)
-	}
-	err = unstructured.SetNestedStringSlice(issuerAsUnstructured.Object, dnsNames, "spec", "dnsNames")
-	if err != nil {
-		return nil, false, err
-	}
-	return resourceapply.ApplyUnstructuredResourceImproved(ctx, c.dynamicClient, c.eventRecorder, issuerAsUnstructured, c.resourceCache, gvr, nil, nil)
-}
-
-func (c *TargetConfigReconciler) manageServiceController(ctx context.Context, ownerReference metav1.OwnerReference) (*corev1.Service, bool, error) {
-	required := resourceread.ReadServiceV1OrDie(bindata.MustAsset("assets/lws-controller-generated/v1_service_lws-controller-manager-metrics-service.yaml"))
-	required.Namespace = c.namespace
-	required.OwnerReferences = []metav1.OwnerReference{
-		ownerReference,
-	}
-
-	return resourceapply.ApplyService(ctx, c.kubeClient.CoreV1(), c.eventRecorder, required)
-}
-
-func (c *TargetConfigReconciler) manageServiceWebhook(ctx context.Context, ownerReference metav1.OwnerReference) (*corev1.Service, bool, error) {
-	required := resourceread.ReadServiceV1OrDie(bindata.MustAsset("assets/lws-controller-generated/v1_service_lws-webhook-service.yaml"))
-	required.Namespace = c.namespace
-	required.OwnerReferences = []metav1.OwnerReference{
-		ownerReference,
-	}
-
-	return resourceapply.ApplyService(ctx, c.kubeClient.CoreV1(), c.eventRecorder, required)
-}
-
-func (c *TargetConfigReconciler) manageServiceAccount(ctx context.Context, ownerReference metav1.OwnerReference) (*corev1.ServiceAccount, bool, error) {
-	required := resourceread.ReadServiceAccountV1OrDie(bindata.MustAsset("assets/lws-controller-generated/v1_serviceaccount_lws-controller-manager.yaml"))
-	required.Namespace = c.namespace
-	required.OwnerReferences = []metav1.OwnerReference{
-		ownerReference,
-	}
-
-	return resourceapply.ApplyServiceAccount(ctx, c.kubeClient.CoreV1(), c.eventRecorder, required)
-}
-
-func (c *TargetConfigReconciler) manageCustomResourceDefinition(ctx context.Context, ownerReference metav1.OwnerReference) (*apiextensionv1.CustomResourceDefinition, bool, error) {
-	crdFiles := []string{
-		"assets/lws-controller-generated/apiextensions.k8s.io_v1_customresourcedefinition_leaderworkersets.leaderworkerset.x-k8s.io.yaml",
-		"assets/lws-controller-generated/apiextensions.k8s.io_v1_customresourcedefinition_disaggregatedsets.disaggregatedset.x-k8s.io.yaml",
-		"assets/lws-controller-generated/apiextensions.k8s.io_v1_customresourcedefinition_disaggregatedsetrolescalers.disaggregatedset.x-k8s.io.yaml",
-	}
-
-	for _, crdFile := range crdFiles {
-		required := resourceread.ReadCustomResourceDefinitionV1OrDie(bindata.MustAsset(crdFile))
-		required.OwnerReferences = []metav1.OwnerReference{
-			ownerReference,
-		}
-
-		if required.Spec.Conversion != nil &&
-			required.Spec.Conversion.Webhook != nil &&
-			required.Spec.Conversion.Webhook.ClientConfig != nil &&
-			required.Spec.Conversion.Webhook.ClientConfig.Service != nil {
-			required.Spec.Conversion.Webhook.ClientConfig.Service.Namespace = c.namespace
-		}
-
-		if _, ok := required.Annotations[CertManagerInjectCaAnnotation]; ok {
-			err := injectCertManagerCA(required, c.namespace)
-			if err != nil {
-				return nil, false, err
-			}
-		}
-
-		currentCRD, err := c.apiextensionClient.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, required.Name, metav1.GetOptions{})
-		switch {
-		case apierrors.IsNotFound(err):
-			// no action needed
-		case err != nil && !apierrors.IsNotFound(err):
-			return nil, false, err
-		case err == nil:
-			if required.Spec.Conversion != nil && required.Spec.Conversion.Webhook != nil && required.Spec.Conversion.Webhook.ClientConfig != nil {
-				required.Spec.Conversion.Webhook.ClientConfig.CABundle = currentCRD.Spec.Conversion.Webhook.ClientConfig.CABundle
-			}
-		}
-
-		_, _, err = resourceapply.ApplyCustomResourceDefinitionV1(ctx, c.apiextensionClient.ApiextensionsV1(), c.eventRecorder, required)
-		if err != nil {
-			return nil, false, err
-		}
-	}
-
-	return nil, false, nil
-}
-
-func (c *TargetConfigReconciler) manageMutatingWebhook(ctx context.Context, ownerReference metav1.OwnerReference) (*admissionv1.MutatingWebhookConfiguration, bool, error) {
-	required := resourceread.ReadMutatingWebhookConfigurationV1OrDie(bindata.MustAsset("assets/lws-controller-generated/admissionregistration.k8s.io_v1_mutatingwebhookconfiguration_lws-mutating-webhook-configuration.yaml"))
-	required.OwnerReferences = []metav1.OwnerReference{
-		ownerReference,
-	}
-
-	for i := range required.Webhooks {
-		if required.Webhooks[i].ClientConfig.Service != nil {
-			required.Webhooks[i].ClientConfig.Service.Namespace = c.namespace
-		}
-	}
-
-	err := injectCertManagerCA(required, c.namespace)
-	if err != nil {
-		return nil, false, err
-	}
-
-	return resourceapply.ApplyMutatingWebhookConfigurationImproved(ctx, c.kubeClient.AdmissionregistrationV1(), c.eventRecorder, required, c.resourceCache)
-}
-
-func (c *TargetConfigReconciler) manageValidatingWebhook(ctx context.Context, ownerReference metav1.OwnerReference) (*admissionv1.ValidatingWebhookConfiguration, bool, error) {
-	required := resourceread.ReadValidatingWebhookConfigurationV1OrDie(bindata.MustAsset("assets/lws-controller-generated/admissionregistration.k8s.io_v1_validatingwebhookconfiguration_lws-validating-webhook-configuration.yaml"))
-	required.OwnerReferences = []metav1.OwnerReference{
-		ownerReference,
-	}
-
-	for i := range required.Webhooks {
-		if required.Webhooks[i].ClientConfig.Service != nil {
-			required.Webhooks[i].ClientConfig.Service.Namespace = c.namespace
-		}
-	}
-
-	err := injectCertManagerCA(required, c.namespace)
-	if err != nil {
-		return nil, false, err
-	}
-
-	return resourceapply.ApplyValidatingWebhookConfigurationImproved(ctx, c.kubeClient.AdmissionregistrationV1(), c.eventRecorder, required, c.resourceCache)
-}
-
-func (c *TargetConfigReconciler) manageServiceMonitor(ctx context.Context, ownerReference metav1.OwnerReference) (*unstructured.Unstructured, bool, error) {
-	service := resourceread.ReadServiceV1OrDie(bindata.MustAsset("assets/lws-controller-generated/v1_service_lws-controller-manager-metrics-service.yaml"))
-	serviceMonitor := ReadServiceMonitorV1OrDie(bindata.MustAsset("assets/lws-controller-generated/monitoring.coreos.com_v1_servicemonitor_lws-controller-manager-metrics-monitor.yaml"))
-	serviceMonitor.SetNamespace(c.namespace)
-	serviceMonitor.SetOwnerReferences([]metav1.OwnerReference{
-		ownerReference,
-	})
-
-	for i, endpoint := range serviceMonitor.Spec.Endpoints {
-		endpoint.TLSConfig.ServerName = ptr.To(strings.Replace(ptr.Deref(endpoint.TLSConfig.ServerName, ""), "SERVICE_NAME", service.Name, 1))
-		endpoint.TLSConfig.ServerName = ptr.To(strings.Replace(ptr.Deref(endpoint.TLSConfig.ServerName, ""), "SERVICE_NAMESPACE", service.Namespace, 1))
-		// clear out the references
-		endpoint.TLSConfig.Cert.Secret = nil
-		endpoint.TLSConfig.Cert.ConfigMap = nil
-		endpoint.TLSConfig.KeySecret = nil
-		// set mounted secret in the openshift-monitoring prometheus
-		endpoint.TLSConfig.CertFile = fmt.Sprintf("%s/%s", PrometheusClientCertsPath, "tls.crt")
-		endpoint.TLSConfig.KeyFile = fmt.Sprintf("%s/%s", PrometheusClientCertsPath, "tls.key")
-		serviceMonitor.Spec.Endpoints[i] = endpoint
-	}
-
-	return ApplyServiceMonitor(ctx, c.dynamicClient, c.eventRecorder, serviceMonitor, c.resourceCache)
-}
-
-func (c *TargetConfigReconciler) manageDeployments(ctx context.Context,
-	leaderWorkerSetOperator *leaderworkersetapiv1.LeaderWorkerSetOperator,
-	ownerReference metav1.OwnerReference,
-	specAnnotations map[string]string) (*appsv1.Deployment, bool, error) {
-	required := resourceread.ReadDeploymentV1OrDie(bindata.MustAsset("assets/lws-controller-generated/apps_v1_deployment_lws-controller-manager.yaml"))
-	required.Namespace = c.namespace
-	required.Name = operandName
-	required.OwnerReferences = []metav1.OwnerReference{
-		ownerReference,
-	}
-
-	if c.targetImage != "" {
-		images := map[string]string{
-			"${CONTROLLER_IMAGE}:latest": c.targetImage,
-		}
-
-		for i := range required.Spec.Template.Spec.Containers {
-			for env, img := range images {
-				if required.Spec.Template.Spec.Containers[i].Image == env {
-					required.Spec.Template.Spec.Containers[i].Image = img
-					break
-				}
-			}
-		}
-	}
-
-	resourcemerge.MergeMap(ptr.To(false), &required.Spec.Template.Annotations, specAnnotations)
-
-	newArgs := []string{
-		"--config=/controller_manager_config.yaml",
-	}
-
-	switch leaderWorkerSetOperator.Spec.LogLevel {
-	case operatorv1.Normal:
-		newArgs = append(newArgs, fmt.Sprintf("--zap-log-level=%d", 2))
-	case operatorv1.Debug:
-		newArgs = append(newArgs, fmt.Sprintf("--zap-log-level=%d", 4))
-	case operatorv1.Trace:
-		newArgs = append(newArgs, fmt.Sprintf("--zap-log-level=%d", 6))
-	case operatorv1.TraceAll:
-		newArgs = append(newArgs, fmt.Sprintf("--zap-log-level=%d", 9))
-	default:
-		newArgs = append(newArgs, fmt.Sprintf("--zap-log-level=%d", 2))
-	}
-
-	// replace the default arg values from upstream
-	required.Spec.Template.Spec.Containers[0].Args = newArgs
-
-	applyNodePlacement(&required.Spec.Template.Spec, leaderWorkerSetOperator.Spec.NodePlacement)
-
-	return resourceapply.ApplyDeployment(
-		ctx,
-		c.kubeClient.AppsV1(),
-		c.eventRecorder,
-		required,
-		resourcemerge.ExpectedDeploymentGeneration(required, leaderWorkerSetOperator.Status.Generations))
-}
-
 func constructAvailableCondition(getDeploymentErr error, deployment *appsv1.Deployment) operatorv1.OperatorCondition {
 	availableCondition := operatorv1.OperatorCondition{
 		Type:   operatorv1.OperatorStatusTypeAvailable,
@@ -742,7 +453,7 @@ func constructAvailableCondition(getDeploymentErr error, deployment *appsv1.Depl
 func isResourceRegistered(discoveryClient discovery.DiscoveryInterface, gvk schema.GroupVersionKind) (bool, error) {
 	apiResourceLists, err := discoveryClient.ServerResourcesForGroupVersion(gvk.GroupVersion().String())
 	if err != nil {
-		if apierrors.IsNotFound(err) {
+		if apierrors.IsNotFound(err) || errors.Is(err, memory.ErrCacheNotFound) {
 			return false, nil
 		}
 		return false, err
@@ -0,0 +1,350 @@
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	apiextensionsinformers "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/discovery/cached/memory"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	leaderworkersetoperatorv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+	operatorconfigfake "github.com/openshift/lws-operator/pkg/generated/clientset/versioned/fake"
+	operatorclientinformers "github.com/openshift/lws-operator/pkg/generated/informers/externalversions"
+	"github.com/openshift/lws-operator/pkg/operator/operatorclient"
+)
+
+const testNamespace = "openshift-lws-operator"
+
+// targetConfigReconcilerFixture wires a TargetConfigReconciler to fake clients and running informers.
+type targetConfigReconcilerFixture struct {
+	reconciler    *TargetConfigReconciler
+	syncCtx       factory.SyncContext
+	recorder      events.InMemoryRecorder
+	dynamicClient *dynamicfake.FakeDynamicClient
+	fakes         []*clienttesting.Fake
+	discovery     *fakediscovery.FakeDiscovery
+	clock         *clocktesting.FakeClock
+}
+
+// newTargetConfigReconcilerFixture creates the fixture; dynamicObjects seed the dynamic client.
+func newTargetConfigReconcilerFixture(tb testing.TB, dynamicObjects ...runtime.Object) *targetConfigReconcilerFixture {
+	tb.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	tb.Cleanup(cancel)
+
+	kubeClient := kubefake.NewClientset(
+		testCertificateSecret(WebhookCertificateSecretName),
+		testCertificateSecret(MetricsCertificateSecretName),
+	)
+	staticResources, err := loadStaticResources()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	listKinds := map[schema.GroupVersionResource]string{}
+	for gk, rule := range staticResourceRules {
+		listKinds[rule.resource] = gk.Kind + "List"
+	}
+	for _, resource := range staticResources {
+		listKinds[resource.gvr()] = resource.gvk.Kind + "List"
+	}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, dynamicObjects...)
+	dynamicClient.PrependReactor("patch", "*", serverSideApplyReactor(dynamicClient.Tracker()))
+	// the field managed tracker of NewClientset has no schema for CRDs
+	apiextensionClient := apiextensionsfake.NewSimpleClientset() //nolint:staticcheck
+	operatorClient := operatorconfigfake.NewClientset(&leaderworkersetoperatorv1.LeaderWorkerSetOperator{
+		// UpdateStatus falls back to a live GET for objects without a resource version
+		ObjectMeta: metav1.ObjectMeta{Name: operatorclient.OperatorConfigName, UID: "operator-uid", ResourceVersion: "1"},
+		Spec: leaderworkersetoperatorv1.LeaderWorkerSetOperatorSpec{
+			OperatorSpec: operatorv1.OperatorSpec{ManagementState: operatorv1.Managed},
+		},
+	})
+	discovery := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{
+		Resources: []*metav1.APIResourceList{{
+			GroupVersion: "cert-manager.io/v1",
+			APIResources: []metav1.APIResource{{Name: "issuers", Kind: "Issuer", Namespaced: true}},
+		}},
+	}}
+
+	operatorInformers := operatorclientinformers.NewSharedInformerFactory(operatorClient, 0)
+	kubeInformersForNamespaces := v1helpers.NewKubeInformersForNamespaces(kubeClient, "", testNamespace)
+	apiextensionInformers := apiextensionsinformers.NewSharedInformerFactory(apiextensionClient, 0)
+	operatorInformer := operatorInformers.OpenShiftOperator().V1().LeaderWorkerSetOperators()
+	crdInformer := apiextensionInformers.Apiextensions().V1().CustomResourceDefinitions()
+	managedObjectInformers := dynamicinformer.NewFilteredDynamicSharedInformerFactory(dynamicClient, 0, metav1.NamespaceAll, func(options *metav1.ListOptions) {
+		options.LabelSelector = ManagedBySelector
+	})
+	managedObjectListers := make(map[schema.GroupVersionResource]cache.GenericLister)
+	for _, gvr := range cachedManagedResources(staticResources) {
+		managedObjectListers[gvr] = managedObjectInformers.ForResource(gvr).Lister()
+	}
+	recorder := events.NewInMemoryRecorder("test", clock.RealClock{})
+	fakeClock := clocktesting.NewFakeClock(time.Now())
+
+	cachedDiscovery := memory.NewMemCacheClient(discovery)
+	if _, err := crdInformer.Informer().AddEventHandler(newDiscoveryInvalidationHandler(cachedDiscovery)); err != nil {
+		tb.Fatal(err)
+	}
+	reconciler := &TargetConfigReconciler{
+		targetImage:     "quay.io/example/lws:v1",
+		operatorVersion: "v1.2.3",
+		operatorLister:  operatorInformer.Lister(),
+		dynamicClient:   dynamicClient,
+		leaderWorkerSetOperatorClient: &operatorclient.LeaderWorkerSetClient{
+			Ctx:            ctx,
+			SharedInformer: operatorInformer.Informer(),
+			Lister:         operatorInformer.Lister(),
+			OperatorClient: operatorClient.OpenShiftOperatorV1(),
+		},
+		kubeClient:                 kubeClient,
+		discoveryClient:            cachedDiscovery,
+		eventRecorder:              recorder,
+		kubeInformersForNamespaces: kubeInformersForNamespaces,
+		secretLister:               kubeInformersForNamespaces.SecretLister(),
+		configMapLister:            kubeInformersForNamespaces.ConfigMapLister(),
+		deploymentsLister:          kubeInformersForNamespaces.InformersFor(testNamespace).Apps().V1().Deployments().Lister(),
+		podLister:                  kubeInformersForNamespaces.InformersFor(testNamespace).Core().V1().Pods().Lister(),
+		managedObjectListers:       managedObjectListers,
+		namespace:                  testNamespace,
+		operatorNamespace:          testNamespace,
+		platform:                   platformOpenShift,
+		restart:                    func(reason string) { tb.Fatalf("unexpected restart: %s", reason) },
+		clock:                      fakeClock,
+		staticResources:            staticResources,
+	}
+	// register the informers used by the reconciler before starting them
+	kubeInformersForNamespaces.InformersFor(testNamespace).Core().V1().Secrets().Informer()
+	kubeInformersForNamespaces.InformersFor(testNamespace).Core().V1().ConfigMaps().Informer()
+
+	operatorInformers.Start(ctx.Done())
+	kubeInformersForNamespaces.Start(ctx.Done())
+	apiextensionInformers.Start(ctx.Done())
+	managedObjectInformers.Start(ctx.Done())
+	operatorInformers.WaitForCacheSync(ctx.Done())
+	apiextensionInformers.WaitForCacheSync(ctx.Done())
+	managedObjectInformers.WaitForCacheSync(ctx.Done())
+	kubeInformersForNamespaces.InformersFor(testNamespace).WaitForCacheSync(ctx.Done())
+	kubeInformersForNamespaces.InformersFor("").WaitForCacheSync(ctx.Done())
+
+	return &targetConfigReconcilerFixture{
+		reconciler:    reconciler,
+		syncCtx:       factory.NewSyncContext("test", recorder),
+		recorder:      recorder,
+		dynamicClient: dynamicClient,
+		fakes:         []*clienttesting.Fake{&kubeClient.Fake, &dynamicClient.Fake, &apiextensionClient.Fake, &operatorClient.Fake},
+		discovery:     discovery,
+		clock:         fakeClock,
+	}
+}
+
+// updateOperatorSpec updates the operator CR and waits for the lister to observe the update.
+func (f *targetConfigReconcilerFixture) updateOperatorSpec(tb testing.TB, update func(spec *leaderworkersetoperatorv1.LeaderWorkerSetOperatorSpec)) {
+	tb.Helper()
+	ctx := context.Background()
+	operators := f.reconciler.leaderWorkerSetOperatorClient.OperatorClient.LeaderWorkerSetOperators()
+	operator, err := operators.Get(ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	update(&operator.Spec)
+	updated, err := operators.Update(ctx, operator, metav1.UpdateOptions{})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if err := wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, 5*time.Second, true, func(context.Context) (bool, error) {
+		cached, err := f.reconciler.operatorLister.Get(operatorclient.OperatorConfigName)
+		return err == nil && equality.Semantic.DeepEqual(cached.Spec, updated.Spec), nil
+	}); err != nil {
+		tb.Fatalf("operator update not observed: %v", err)
+	}
+}
+
+// serverSideApplyReactor approximates server-side apply for the fake dynamic client, whose object
+// tracker can only apply to existing objects: the applied object is created or replaces the current one.
+func serverSideApplyReactor(tracker clienttesting.ObjectTracker) clienttesting.ReactionFunc {
+	return func(action clienttesting.Action) (bool, runtime.Object, error) {
+		patchAction := action.(clienttesting.PatchAction)
+		if patchAction.GetPatchType() != types.ApplyPatchType {
+			return false, nil, nil
+		}
+		applied := &unstructured.Unstructured{}
+		if err := json.Unmarshal(patchAction.GetPatch(), &applied.Object); err != nil {
+			return true, nil, err
+		}
+		gvr, namespace := patchAction.GetResource(), patchAction.GetNamespace()
+
+		current, err := tracker.Get(gvr, namespace, patchAction.GetName())
+		if apierrors.IsNotFound(err) {
+			applied.SetUID(types.UID("uid-" + patchAction.GetName()))
+			applied.SetResourceVersion("1")
+			return true, applied, tracker.Create(gvr, applied, namespace)
+		}
+		if err != nil {
+			return true, nil, err
+		}
+		currentMeta, err := meta.Accessor(current)
+		if err != nil {
+			return true, nil, err
+		}
+		applied.SetUID(currentMeta.GetUID())
+		applied.SetResourceVersion(currentMeta.GetResourceVersion())
+		return true, applied, tracker.Update(gvr, applied, namespace)
+	}
+}
+
+func testCertificateSecret(name string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		Data: map[string][]byte{
+			"tls.crt": []byte("cert"),
+			"tls.key": []byte("key"),
+		},
+	}
+}
+
+func (f *targetConfigReconcilerFixture) clearActions() {
+	for _, fake := range f.fakes {
+		fake.ClearActions()
+	}
+	f.discovery.ClearActions()
+}
+
+func (f *targetConfigReconcilerFixture) actions() []clienttesting.Action {
+	var actions []clienttesting.Action
+	for _, fake := range f.fakes {
+		actions = append(actions, fake.Actions()...)
+	}
+	return append(actions, f.discovery.Actions()...)
+}
+
+// writeActions returns the verb and resource of the actions that modify objects. Server-side applies
+// are not included: the API server does not persist an apply that does not change the object.
+func writeActions(actions []clienttesting.Action) []string {
+	var writes []string
+	for _, action := range actions {
+		if patchAction, ok := action.(clienttesting.PatchAction); ok && patchAction.GetPatchType() == types.ApplyPatchType {
+			continue
+		}
+		switch action.GetVerb() {
+		case "get", "list", "watch":
+		default:
+			writes = append(writes, fmt.Sprintf("%s %s/%s", action.GetVerb(), action.GetResource().Resource, action.GetSubresource()))
+		}
+	}
+	return writes
+}
+
+// converge syncs until a sync does not write anything, giving the informers time to observe the
+// objects created by the previous sync.
+func (f *targetConfigReconcilerFixture) converge(tb testing.TB) {
+	tb.Helper()
+	var syncErr error
+	err := wait.PollUntilContextTimeout(context.Background(), 50*time.Millisecond, 10*time.Second, true, func(ctx context.Context) (bool, error) {
+		f.clearActions()
+		syncErr = f.reconciler.sync(ctx, f.syncCtx)
+		return syncErr == nil && len(writeActions(f.actions())) == 0, nil
+	})
+	if err != nil {
+		tb.Fatalf("sync did not converge: %v, last sync error: %v, last writes: %v", err, syncErr, writeActions(f.actions()))
+	}
+}
+
+func TestTargetConfigReconcilerNoOpSync(t *testing.T) {
+	f := newTargetConfigReconcilerFixture(t)
+	f.converge(t)
+
+	f.clearActions()
+	if err := f.reconciler.sync(context.Background(), f.syncCtx); err != nil {
+		t.Fatal(err)
+	}
+	actions := f.actions()
+	if writes := writeActions(actions); len(writes) != 0 {
+		t.Errorf("expected a no-op sync not to write, got %v", writes)
+	}
+	for _, action := range actions {
+		switch {
+		case action.GetVerb() == "get" && action.GetResource().Resource != "":
+			t.Errorf("expected %s to be read from an informer cache or applied without a GET", action.GetResource().Resource)
+		case action.GetResource().Resource == "leaderworkersetoperators":
+			t.Errorf("expected the operator configuration to be read from the informer cache, got %s", action.GetVerb())
+		case action.GetResource().Resource == "" && action.GetVerb() == "get":
+			t.Errorf("expected discovery to be cached, got %v", action)
+		}
+	}
+}
+
+func TestTargetConfigReconcilerRemovesSyncDegradedCondition(t *testing.T) {
+	f := newTargetConfigReconcilerFixture(t)
+	ctx := context.Background()
+
+	// the condition set from the sync error by releases before the resource groups
+	operators := f.reconciler.leaderWorkerSetOperatorClient.OperatorClient.LeaderWorkerSetOperators()
+	operator, err := operators.Get(ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	operator.Status.Conditions = []operatorv1.OperatorCondition{{
+		Type:    "TargetConfigControllerDegraded",
+		Status:  operatorv1.ConditionTrue,
+		Reason:  "SyncError",
+		Message: "Webhooks: conflict",
+	}}
+	if _, err := operators.UpdateStatus(ctx, operator, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	f.converge(t)
+	if condition := f.condition(t, "TargetConfigControllerDegraded"); condition != nil {
+		t.Errorf("expected TargetConfigControllerDegraded to be removed, got %+v", condition)
+	}
+	if condition := f.condition(t, "GarbageCollectionDegraded"); condition == nil || condition.Status != operatorv1.ConditionFalse {
+		t.Errorf("expected GarbageCollectionDegraded to be False, got %+v", condition)
+	}
+	if !f.reconciler.garbageCollected {
+		t.Errorf("expected the garbage to be collected after a successful sync")
+	}
+}
+
+func BenchmarkTargetConfigReconcilerNoOpSync(b *testing.B) {
+	f := newTargetConfigReconcilerFixture(b)
+	f.converge(b)
+
+	ctx := context.Background()
+	calls := 0
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		f.clearActions()
+		if err := f.reconciler.sync(ctx, f.syncCtx); err != nil {
+			b.Fatal(err)
+		}
+		calls += len(f.actions())
+	}
+	b.ReportMetric(float64(calls)/float64(b.N), "api-calls/op")
+}
@@ -0,0 +1,260 @@
+package operator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	apiextensionv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsv1informer "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions/apiextensions/v1"
+	apiextensionsv1lister "k8s.io/apiextensions-apiserver/pkg/client/listers/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/lws-operator/bindata"
+)
+
+const (
+	// WebhooksHealthyConditionType reports whether the operand admission and conversion
+	// webhooks are reachable from the API server.
+	WebhooksHealthyConditionType = "WebhooksHealthy"
+
+	leaderWorkerSetCRDName = "leaderworkersets.leaderworkerset.x-k8s.io"
+
+	webhookProbeAdmission  = "admission"
+	webhookProbeConversion = "conversion"
+
+	webhookProbeInterval = time.Minute
+	webhookProbeTimeout  = 15 * time.Second
+)
+
+var leaderWorkerSetGroupResource = schema.GroupResource{
+	Group:    "leaderworkerset.x-k8s.io",
+	Resource: "leaderworkersets",
+}
+
+// WebhookProbeController periodically sends dry-run requests for a canned LeaderWorkerSet
+// through the API server. A running operand Deployment does not prove that the API server can
+// reach the mutating, validating and conversion webhooks, so this exercises the full path.
+type WebhookProbeController struct {
+	namespace      string
+	operatorClient v1helpers.OperatorClient
+	dynamicClient  dynamic.Interface
+	crdLister      apiextensionsv1lister.CustomResourceDefinitionLister
+}
+
+// webhookProbeResult holds the outcome of a single probe.
+type webhookProbeResult struct {
+	probe string
+	// skipped is why the probe did not exercise its webhook, empty if it did.
+	skipped string
+	latency time.Duration
+	reason  string
+	err     error
+}
+
+func NewWebhookProbeController(
+	namespace string,
+	operatorClient v1helpers.OperatorClient,
+	dynamicClient dynamic.Interface,
+	crdInformer apiextensionsv1informer.CustomResourceDefinitionInformer,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &WebhookProbeController{
+		namespace:      namespace,
+		operatorClient: operatorClient,
+		dynamicClient:  dynamicClient,
+		crdLister:      crdInformer.Lister(),
+	}
+
+	return factory.New().
+		// the status writes of the other controllers do not trigger a probe
+		WithInformers(specChangesInformer{operatorClient.Informer()}).
+		// the CRD is read from the cache, its changes wait for the next probe
+		WithBareInformers(crdInformer.Informer()).
+		ResyncEvery(webhookProbeInterval).
+		WithSync(c.sync).
+		ToController("WebhookProbeController", eventRecorder)
+}
+
+func (c *WebhookProbeController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	spec, _, _, err := c.operatorClient.GetOperatorState()
+	if err != nil {
+		return err
+	}
+	if spec.ManagementState != "" && spec.ManagementState != operatorv1.Managed {
+		return nil
+	}
+
+	results := []webhookProbeResult{
+		c.probeAdmission(ctx),
+		c.probeConversion(ctx),
+	}
+	for _, result := range results {
+		recordWebhookProbeResult(result)
+	}
+
+	_, _, err = v1helpers.UpdateStatus(ctx, c.operatorClient, v1helpers.UpdateConditionFn(constructWebhooksHealthyCondition(results)))
+	return err
+}
+
+// probeAdmission performs a server-side dry-run create of the canned LeaderWorkerSet, which is
+// routed through the mutating and validating webhooks without persisting anything.
+func (c *WebhookProbeController) probeAdmission(ctx context.Context) webhookProbeResult {
+	required, err := readWebhookProbeLeaderWorkerSet()
+	if err != nil {
+		return webhookProbeResult{probe: webhookProbeAdmission, reason: "InvalidProbeObject", err: err}
+	}
+	required.SetNamespace(c.namespace)
+
+	ctx, cancel := context.WithTimeout(ctx, webhookProbeTimeout)
+	defer cancel()
+
+	gvr := required.GroupVersionKind().GroupVersion().WithResource(leaderWorkerSetGroupResource.Resource)
+	start := time.Now()
+	_, err = c.dynamicClient.Resource(gvr).Namespace(c.namespace).Create(ctx, required, metav1.CreateOptions{
+		DryRun: []string{metav1.DryRunAll},
+	})
+	return newWebhookProbeResult(webhookProbeAdmission, time.Since(start), err)
+}
+
+// probeConversion reads LeaderWorkerSets through a served non-storage version so that any stored
+// object has to go through the conversion webhook. The probe is skipped when the CRD serves a
+// single version or no LeaderWorkerSet is stored, as the API server does not call the conversion
+// webhook in these cases.
+func (c *WebhookProbeController) probeConversion(ctx context.Context) webhookProbeResult {
+	ctx, cancel := context.WithTimeout(ctx, webhookProbeTimeout)
+	defer cancel()
+
+	crd, err := c.crdLister.Get(leaderWorkerSetCRDName)
+	if err != nil {
+		// not a request through the webhook, classified like any other API error
+		return webhookProbeResult{probe: webhookProbeConversion, reason: classifyWebhookProbeError("", err), err: err}
+	}
+	version := nonStorageServedVersion(crd)
+	if version == "" {
+		return webhookProbeResult{probe: webhookProbeConversion, skipped: "the CRD serves a single version"}
+	}
+
+	gvr := leaderWorkerSetGroupResource.WithVersion(version)
+	start := time.Now()
+	list, err := c.dynamicClient.Resource(gvr).List(ctx, metav1.ListOptions{Limit: 1})
+	result := newWebhookProbeResult(webhookProbeConversion, time.Since(start), err)
+	if err == nil && len(list.Items) == 0 {
+		result.skipped = "no LeaderWorkerSet is stored"
+	}
+	return result
+}
+
+func readWebhookProbeLeaderWorkerSet() (*unstructured.Unstructured, error) {
+	obj, err := resourceread.ReadGenericWithUnstructured(bindata.MustAsset("assets/lws-controller/webhook-probe-leaderworkerset.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	objAsUnstructured, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("webhook probe object is not an Unstructured")
+	}
+	return objAsUnstructured, nil
+}
+
+func nonStorageServedVersion(crd *apiextensionv1.CustomResourceDefinition) string {
+	if crd.Spec.Conversion == nil || crd.Spec.Conversion.Strategy != apiextensionv1.WebhookConverter {
+		return ""
+	}
+	for _, version := range crd.Spec.Versions {
+		if version.Served && !version.Storage {
+			return version.Name
+		}
+	}
+	return ""
+}
+
+func newWebhookProbeResult(probe string, latency time.Duration, err error) webhookProbeResult {
+	result := webhookProbeResult{probe: probe, latency: latency, err: err}
+	if err != nil {
+		result.reason = classifyWebhookProbeError(probe, err)
+	}
+	return result
+}
+
+// classifyWebhookProbeError maps a probe error to a condition reason and metric label. The API
+// server reports webhooks it cannot call, or that fail a conversion, as internal errors, and
+// returns the status of a webhook denying the request, which defaults to a 400.
+func classifyWebhookProbeError(probe string, err error) string {
+	switch {
+	case apierrors.IsNotFound(err):
+		return "APINotServed"
+	case apierrors.IsTimeout(err), apierrors.IsServerTimeout(err), errors.Is(err, context.DeadlineExceeded):
+		return "Timeout"
+	case apierrors.IsForbidden(err):
+		return "Forbidden"
+	case apierrors.IsInternalError(err) && probe == webhookProbeConversion:
+		return "ConversionWebhookFailed"
+	case apierrors.IsInternalError(err) && probe == webhookProbeAdmission:
+		return "WebhookUnreachable"
+	}
+	var status apierrors.APIStatus
+	if probe == webhookProbeAdmission && errors.As(err, &status) {
+		if code := status.Status().Code; code >= 400 && code < 500 {
+			return "AdmissionDenied"
+		}
+	}
+	return "RequestFailed"
+}
+
+func recordWebhookProbeResult(result webhookProbeResult) {
+	if result.skipped != "" {
+		// nothing was tested, the health of the webhook is unknown
+		webhookProbeHealthy.DeleteLabelValues(result.probe)
+		return
+	}
+	if result.err != nil {
+		webhookProbeDuration.WithLabelValues(result.probe, "failure").Observe(result.latency.Seconds())
+		webhookProbeFailures.WithLabelValues(result.probe, result.reason).Inc()
+		webhookProbeHealthy.WithLabelValues(result.probe).Set(0)
+		return
+	}
+	webhookProbeDuration.WithLabelValues(result.probe, "success").Observe(result.latency.Seconds())
+	webhookProbeHealthy.WithLabelValues(result.probe).Set(1)
+}
+
+func constructWebhooksHealthyCondition(results []webhookProbeResult) operatorv1.OperatorCondition {
+	var reasons, messages, skipped []string
+	for _, result := range results {
+		if result.skipped != "" {
+			skipped = append(skipped, fmt.Sprintf("%s probe skipped: %s", result.probe, result.skipped))
+			continue
+		}
+		if result.err == nil {
+			continue
+		}
+		reasons = append(reasons, result.reason)
+		messages = append(messages, fmt.Sprintf("%s probe failed after %s: %v", result.probe, result.latency.Round(time.Millisecond), result.err))
+	}
+	if len(reasons) > 0 {
+		return operatorv1.OperatorCondition{
+			Type:    WebhooksHealthyConditionType,
+			Status:  operatorv1.ConditionFalse,
+			Reason:  reasons[0],
+			Message: strings.Join(messages, "\n"),
+		}
+	}
+	return operatorv1.OperatorCondition{
+		Type:    WebhooksHealthyConditionType,
+		Status:  operatorv1.ConditionTrue,
+		Reason:  "AsExpected",
+		Message: strings.Join(append([]string{"dry-run requests for LeaderWorkerSets were accepted by the operand webhooks"}, skipped...), "\n"),
+	}
+}
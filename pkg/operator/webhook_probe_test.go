@@ -0,0 +1,187 @@
+package operator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	apiextensionv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsv1lister "k8s.io/apiextensions-apiserver/pkg/client/listers/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+)
+
+func TestClassifyWebhookProbeError(t *testing.T) {
+	tests := []struct {
+		name  string
+		probe string
+		err   error
+		want  string
+	}{
+		{
+			name:  "unreachable webhook",
+			probe: webhookProbeAdmission,
+			err:   apierrors.NewInternalError(errors.New(`failed calling webhook "vleaderworkerset.kb.io": failed to call webhook: Post "https://lws-webhook-service.openshift-lws-operator.svc:443/validate": dial tcp: connection refused`)),
+			want:  "WebhookUnreachable",
+		},
+		{
+			name:  "conversion webhook failure",
+			probe: webhookProbeConversion,
+			err:   apierrors.NewInternalError(errors.New(`conversion webhook for leaderworkerset.x-k8s.io/v1alpha1, Kind=LeaderWorkerSet failed: Post "https://lws-webhook-service.openshift-lws-operator.svc:443/convert": EOF`)),
+			want:  "ConversionWebhookFailed",
+		},
+		{
+			name:  "denied by webhook",
+			probe: webhookProbeAdmission,
+			err: &apierrors.StatusError{ErrStatus: metav1.Status{
+				Status:  metav1.StatusFailure,
+				Code:    http.StatusBadRequest,
+				Message: `admission webhook "vleaderworkerset.kb.io" denied the request: spec.replicas: Invalid value`,
+			}},
+			want: "AdmissionDenied",
+		},
+		{
+			name:  "CRD missing",
+			probe: webhookProbeAdmission,
+			err:   apierrors.NewNotFound(leaderWorkerSetGroupResource, "lws-operator-webhook-probe"),
+			want:  "APINotServed",
+		},
+		{
+			name:  "timeout",
+			probe: webhookProbeAdmission,
+			err:   apierrors.NewTimeoutError("request timed out", 1),
+			want:  "Timeout",
+		},
+		{
+			name:  "client timeout",
+			probe: webhookProbeConversion,
+			err:   fmt.Errorf("Get \"https://172.30.0.1:443/apis\": %w", context.DeadlineExceeded),
+			want:  "Timeout",
+		},
+		{
+			name: "internal error outside the probes",
+			err:  apierrors.NewInternalError(errors.New("etcdserver: leader changed")),
+			want: "RequestFailed",
+		},
+		{
+			name:  "other error",
+			probe: webhookProbeAdmission,
+			err:   fmt.Errorf("boom"),
+			want:  "RequestFailed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyWebhookProbeError(tt.probe, tt.err); got != tt.want {
+				t.Fatalf("expected reason %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestProbeConversion(t *testing.T) {
+	crd := &apiextensionv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: leaderWorkerSetCRDName},
+		Spec: apiextensionv1.CustomResourceDefinitionSpec{
+			Conversion: &apiextensionv1.CustomResourceConversion{Strategy: apiextensionv1.WebhookConverter},
+			Versions: []apiextensionv1.CustomResourceDefinitionVersion{
+				{Name: "v1", Served: true, Storage: true},
+				{Name: "v1beta1", Served: true},
+			},
+		},
+	}
+	gvr := leaderWorkerSetGroupResource.WithVersion("v1beta1")
+	newController := func(objects ...runtime.Object) *WebhookProbeController {
+		crds := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+		if err := crds.Add(crd); err != nil {
+			t.Fatal(err)
+		}
+		return &WebhookProbeController{
+			dynamicClient: dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{gvr: "LeaderWorkerSetList"}, objects...),
+			crdLister:     apiextensionsv1lister.NewCustomResourceDefinitionLister(crds),
+		}
+	}
+
+	// an empty list does not go through the webhook
+	result := newController().probeConversion(context.Background())
+	if result.skipped == "" || result.err != nil {
+		t.Errorf("expected the probe to be skipped without LeaderWorkerSets, got %+v", result)
+	}
+	if condition := constructWebhooksHealthyCondition([]webhookProbeResult{{probe: webhookProbeAdmission}, result}); !strings.Contains(condition.Message, "conversion probe skipped") {
+		t.Errorf("expected the skipped probe in the condition message, got %q", condition.Message)
+	}
+
+	lws := &unstructured.Unstructured{}
+	lws.SetGroupVersionKind(gvr.GroupVersion().WithKind("LeaderWorkerSet"))
+	lws.SetNamespace("default")
+	lws.SetName("vllm")
+	result = newController(lws).probeConversion(context.Background())
+	if result.skipped != "" || result.err != nil {
+		t.Errorf("expected the probe to convert the stored LeaderWorkerSet, got %+v", result)
+	}
+}
+
+func TestNonStorageServedVersion(t *testing.T) {
+	crd := &apiextensionv1.CustomResourceDefinition{
+		Spec: apiextensionv1.CustomResourceDefinitionSpec{
+			Conversion: &apiextensionv1.CustomResourceConversion{Strategy: apiextensionv1.WebhookConverter},
+			Versions: []apiextensionv1.CustomResourceDefinitionVersion{
+				{Name: "v1", Served: true, Storage: true},
+			},
+		},
+	}
+	if version := nonStorageServedVersion(crd); version != "" {
+		t.Fatalf("expected no non-storage version, got %q", version)
+	}
+
+	crd.Spec.Versions = append(crd.Spec.Versions,
+		apiextensionv1.CustomResourceDefinitionVersion{Name: "v1alpha1", Served: false},
+		apiextensionv1.CustomResourceDefinitionVersion{Name: "v1beta1", Served: true},
+	)
+	if version := nonStorageServedVersion(crd); version != "v1beta1" {
+		t.Fatalf("expected v1beta1, got %q", version)
+	}
+
+	crd.Spec.Conversion.Strategy = apiextensionv1.NoneConverter
+	if version := nonStorageServedVersion(crd); version != "" {
+		t.Fatalf("expected no version without a conversion webhook, got %q", version)
+	}
+}
+
+func TestConstructWebhooksHealthyCondition(t *testing.T) {
+	healthy := constructWebhooksHealthyCondition([]webhookProbeResult{
+		{probe: webhookProbeAdmission},
+		{probe: webhookProbeConversion, skipped: "the CRD serves a single version"},
+	})
+	if healthy.Status != operatorv1.ConditionTrue || healthy.Reason != "AsExpected" {
+		t.Fatalf("unexpected condition: %+v", healthy)
+	}
+
+	unhealthy := constructWebhooksHealthyCondition([]webhookProbeResult{
+		{probe: webhookProbeAdmission, reason: "WebhookUnreachable", err: errors.New("connection refused")},
+		{probe: webhookProbeConversion},
+	})
+	if unhealthy.Status != operatorv1.ConditionFalse || unhealthy.Reason != "WebhookUnreachable" {
+		t.Fatalf("unexpected condition: %+v", unhealthy)
+	}
+}
+
+func TestReadWebhookProbeLeaderWorkerSet(t *testing.T) {
+	obj, err := readWebhookProbeLeaderWorkerSet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.GroupVersionKind().Group != leaderWorkerSetGroupResource.Group || obj.GetKind() != "LeaderWorkerSet" {
+		t.Fatalf("unexpected probe object %s", obj.GroupVersionKind())
+	}
+}
@@ -0,0 +1,238 @@
+package operator
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	apiextensionsv1informer "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions/apiextensions/v1"
+	apiextensionsv1lister "k8s.io/apiextensions-apiserver/pkg/client/listers/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	leaderworkersetapiv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+	"github.com/openshift/lws-operator/pkg/operator/operatorclient"
+)
+
+const (
+	// workloadInventoryInterval batches the workload changes of busy clusters into one status update.
+	workloadInventoryInterval = 30 * time.Second
+	// maxInventoryNamespaces bounds the size of status.workloads.namespaces and the namespaces of the
+	// lws_operator_workloads metric.
+	maxInventoryNamespaces = 100
+
+	disaggregatedSetCRDName = "disaggregatedsets.disaggregatedset.x-k8s.io"
+)
+
+// workloadKind is an operand API whose objects are counted.
+type workloadKind struct {
+	kind    string
+	crdName string
+	gvr     schema.GroupVersionResource
+}
+
+var (
+	leaderWorkerSetWorkloads = workloadKind{
+		kind:    "LeaderWorkerSet",
+		crdName: leaderWorkerSetCRDName,
+		gvr:     leaderWorkerSetGroupResource.WithVersion("v1"),
+	}
+	disaggregatedSetWorkloads = workloadKind{
+		kind:    "DisaggregatedSet",
+		crdName: disaggregatedSetCRDName,
+		gvr:     schema.GroupVersionResource{Group: "disaggregatedset.x-k8s.io", Version: "v1", Resource: "disaggregatedsets"},
+	}
+)
+
+// WorkloadInventoryController counts the LeaderWorkerSets and DisaggregatedSets of all namespaces in
+// status.workloads and in the lws_operator_workload* metrics. The objects are watched through dynamic
+// informers, which are only started once the operand CRDs are served, since the operator installs
+// them itself.
+type WorkloadInventoryController struct {
+	operatorClient *operatorclient.LeaderWorkerSetClient
+	crdLister      apiextensionsv1lister.CustomResourceDefinitionLister
+	informers      dynamicinformer.DynamicSharedInformerFactory
+	// workloads are the informers started so far, by kind.
+	workloads map[string]informers.GenericInformer
+	// workloadCountSeries are the lws_operator_workloads series set by the last sync.
+	workloadCountSeries sets.Set[workloadCountLabels]
+}
+
+func NewWorkloadInventoryController(
+	operatorClient *operatorclient.LeaderWorkerSetClient,
+	crdInformer apiextensionsv1informer.CustomResourceDefinitionInformer,
+	workloadInformers dynamicinformer.DynamicSharedInformerFactory,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &WorkloadInventoryController{
+		operatorClient: operatorClient,
+		crdLister:      crdInformer.Lister(),
+		informers:      workloadInformers,
+		workloads:      make(map[string]informers.GenericInformer),
+	}
+
+	return factory.New().
+		// for the operand CRDs to become served
+		WithInformers(crdInformer.Informer()).
+		ResyncEvery(time.Minute*5).
+		WithSync(c.sync).
+		ToController("WorkloadInventoryController", eventRecorder)
+}
+
+func (c *WorkloadInventoryController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	started := false
+	for _, workload := range []workloadKind{leaderWorkerSetWorkloads, disaggregatedSetWorkloads} {
+		if _, ok := c.workloads[workload.kind]; ok || !workloadServed(c.crdLister, workload) {
+			continue
+		}
+		informer := c.informers.ForResource(workload.gvr)
+		if _, err := informer.Informer().AddEventHandler(newWorkloadEventHandler(syncCtx)); err != nil {
+			return err
+		}
+		c.workloads[workload.kind] = informer
+		started = true
+		klog.V(2).Infof("Watching %s objects for the workload inventory", workload.kind)
+	}
+	if started {
+		c.informers.Start(ctx.Done())
+	}
+	if len(c.workloads) == 0 {
+		return nil
+	}
+	for _, informer := range c.workloads {
+		if !informer.Informer().HasSynced() {
+			// the informer queues a sync for every listed object, count them once it has synced
+			syncCtx.Queue().AddAfter(syncCtx.QueueKey(), time.Second)
+			return nil
+		}
+	}
+
+	objects := map[string][]*unstructured.Unstructured{}
+	for kind, informer := range c.workloads {
+		list, err := informer.Lister().List(labels.Everything())
+		if err != nil {
+			return err
+		}
+		for _, obj := range list {
+			if u, ok := obj.(*unstructured.Unstructured); ok {
+				objects[kind] = append(objects[kind], u)
+			}
+		}
+	}
+	inventory, namespaces := workloadInventory(objects[leaderWorkerSetWorkloads.kind], objects[disaggregatedSetWorkloads.kind])
+	c.workloadCountSeries = recordWorkloadInventory(inventory, namespaces, c.workloadCountSeries)
+
+	_, err := c.operatorClient.UpdateStatus(ctx, setWorkloadInventory(inventory))
+	return err
+}
+
+// workloadServed reports whether the CRD of a workload is established and serves the watched version.
+func workloadServed(crdLister apiextensionsv1lister.CustomResourceDefinitionLister, workload workloadKind) bool {
+	crd, err := crdLister.Get(workload.crdName)
+	if err != nil {
+		return false
+	}
+	return crdServesResources(crd) && slices.Contains(servedVersions(crd), workload.gvr.Version)
+}
+
+// newWorkloadEventHandler queues a delayed sync for every workload change. Changes arriving while a
+// sync is pending are counted by that sync.
+func newWorkloadEventHandler(syncCtx factory.SyncContext) cache.ResourceEventHandler {
+	queue := func() { syncCtx.Queue().AddAfter(syncCtx.QueueKey(), workloadInventoryInterval) }
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { queue() },
+		UpdateFunc: func(interface{}, interface{}) { queue() },
+		DeleteFunc: func(interface{}) { queue() },
+	}
+}
+
+// workloadInventory counts the workloads. The counts of all namespaces are returned for the metrics,
+// sorted by their number of workloads; the inventory only lists the namespaces with the most workloads.
+func workloadInventory(leaderWorkerSets, disaggregatedSets []*unstructured.Unstructured) (*leaderworkersetapiv1.WorkloadInventory, []leaderworkersetapiv1.NamespaceWorkloadCounts) {
+	inventory := &leaderworkersetapiv1.WorkloadInventory{}
+	byNamespace := map[string]*leaderworkersetapiv1.NamespaceWorkloadCounts{}
+	namespaceCounts := func(namespace string) *leaderworkersetapiv1.NamespaceWorkloadCounts {
+		if byNamespace[namespace] == nil {
+			byNamespace[namespace] = &leaderworkersetapiv1.NamespaceWorkloadCounts{Namespace: namespace}
+		}
+		return byNamespace[namespace]
+	}
+
+	for _, lws := range leaderWorkerSets {
+		countWorkload(&inventory.LeaderWorkerSets, lws)
+		namespaceCounts(lws.GetNamespace()).LeaderWorkerSets++
+
+		// groups are only counted once created, the size defaults to a leader without workers
+		groups, _, _ := unstructured.NestedInt64(lws.Object, "status", "replicas")
+		size, found, _ := unstructured.NestedInt64(lws.Object, "spec", "leaderWorkerTemplate", "size")
+		if !found {
+			size = 1
+		}
+		inventory.Groups += int32(groups)
+		inventory.Pods += int32(groups * size)
+	}
+	for _, disaggregatedSet := range disaggregatedSets {
+		countWorkload(&inventory.DisaggregatedSets, disaggregatedSet)
+		namespaceCounts(disaggregatedSet.GetNamespace()).DisaggregatedSets++
+	}
+
+	namespaces := make([]leaderworkersetapiv1.NamespaceWorkloadCounts, 0, len(byNamespace))
+	for _, counts := range byNamespace {
+		namespaces = append(namespaces, *counts)
+	}
+	slices.SortFunc(namespaces, func(a, b leaderworkersetapiv1.NamespaceWorkloadCounts) int {
+		if totalA, totalB := a.LeaderWorkerSets+a.DisaggregatedSets, b.LeaderWorkerSets+b.DisaggregatedSets; totalA != totalB {
+			return int(totalB - totalA)
+		}
+		return strings.Compare(a.Namespace, b.Namespace)
+	})
+	inventory.Namespaces = slices.Clone(namespaces[:min(len(namespaces), maxInventoryNamespaces)])
+	slices.SortFunc(inventory.Namespaces, func(a, b leaderworkersetapiv1.NamespaceWorkloadCounts) int {
+		return strings.Compare(a.Namespace, b.Namespace)
+	})
+	if len(inventory.Namespaces) == 0 {
+		inventory.Namespaces = nil
+	}
+	return inventory, namespaces
+}
+
+// countWorkload adds a workload to the counts of its kind by its Available and Progressing conditions.
+func countWorkload(counts *leaderworkersetapiv1.WorkloadCounts, obj *unstructured.Unstructured) {
+	counts.Total++
+	if workloadCondition(obj, "Available") != "True" {
+		counts.NotAvailable++
+	}
+	if workloadCondition(obj, "Progressing") == "True" {
+		counts.Progressing++
+	}
+}
+
+func workloadCondition(obj *unstructured.Unstructured, conditionType string) string {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, condition := range conditions {
+		condition, _ := condition.(map[string]interface{})
+		if t, _, _ := unstructured.NestedString(condition, "type"); t == conditionType {
+			status, _, _ := unstructured.NestedString(condition, "status")
+			return status
+		}
+	}
+	return ""
+}
+
+// setWorkloadInventory replaces status.workloads.
+func setWorkloadInventory(inventory *leaderworkersetapiv1.WorkloadInventory) operatorclient.UpdateStatusFunc {
+	return func(status *leaderworkersetapiv1.LeaderWorkerSetOperatorStatus) error {
+		status.Workloads = inventory
+		return nil
+	}
+}
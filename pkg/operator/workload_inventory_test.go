@@ -0,0 +1,240 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	apiextensionv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	apiextensionsinformers "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/informers"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/component-base/metrics/testutil"
+	"k8s.io/utils/clock"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	leaderworkersetoperatorv1 "github.com/openshift/lws-operator/pkg/apis/leaderworkersetoperator/v1"
+	operatorconfigfake "github.com/openshift/lws-operator/pkg/generated/clientset/versioned/fake"
+	operatorclientinformers "github.com/openshift/lws-operator/pkg/generated/informers/externalversions"
+	"github.com/openshift/lws-operator/pkg/operator/operatorclient"
+)
+
+func newWorkload(workload workloadKind, namespace, name string, spec map[string]interface{}, status map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec, "status": status}}
+	obj.SetGroupVersionKind(workload.gvr.GroupVersion().WithKind(workload.kind))
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	return obj
+}
+
+func workloadConditions(conditions ...string) []interface{} {
+	var statusConditions []interface{}
+	for _, condition := range conditions {
+		conditionType, status, _ := strings.Cut(condition, "=")
+		statusConditions = append(statusConditions, map[string]interface{}{"type": conditionType, "status": status})
+	}
+	return statusConditions
+}
+
+func newEstablishedCRD(name, version string) *apiextensionv1.CustomResourceDefinition {
+	return &apiextensionv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: apiextensionv1.CustomResourceDefinitionSpec{
+			Versions: []apiextensionv1.CustomResourceDefinitionVersion{{Name: version, Served: true, Storage: true}},
+		},
+		Status: apiextensionv1.CustomResourceDefinitionStatus{
+			Conditions: []apiextensionv1.CustomResourceDefinitionCondition{{Type: apiextensionv1.Established, Status: apiextensionv1.ConditionTrue}},
+		},
+	}
+}
+
+func TestWorkloadInventory(t *testing.T) {
+	leaderWorkerSets := []*unstructured.Unstructured{
+		newWorkload(leaderWorkerSetWorkloads, "team-a", "vllm",
+			map[string]interface{}{"leaderWorkerTemplate": map[string]interface{}{"size": int64(4)}},
+			map[string]interface{}{"replicas": int64(2), "conditions": workloadConditions("Available=True", "Progressing=False")}),
+		newWorkload(leaderWorkerSetWorkloads, "team-a", "sglang", nil,
+			map[string]interface{}{"replicas": int64(3), "conditions": workloadConditions("Available=False", "Progressing=True")}),
+		newWorkload(leaderWorkerSetWorkloads, "team-b", "pending", nil, nil),
+	}
+	disaggregatedSets := []*unstructured.Unstructured{
+		newWorkload(disaggregatedSetWorkloads, "team-b", "llm-d", nil, map[string]interface{}{"conditions": workloadConditions("Available=True")}),
+	}
+
+	inventory, namespaces := workloadInventory(leaderWorkerSets, disaggregatedSets)
+	if expected := (leaderworkersetoperatorv1.WorkloadCounts{Total: 3, NotAvailable: 2, Progressing: 1}); inventory.LeaderWorkerSets != expected {
+		t.Errorf("expected LeaderWorkerSet counts %+v, got %+v", expected, inventory.LeaderWorkerSets)
+	}
+	if expected := (leaderworkersetoperatorv1.WorkloadCounts{Total: 1}); inventory.DisaggregatedSets != expected {
+		t.Errorf("expected DisaggregatedSet counts %+v, got %+v", expected, inventory.DisaggregatedSets)
+	}
+	if inventory.Groups != 5 || inventory.Pods != 11 {
+		t.Errorf("expected 5 groups and 11 pods, got %d groups and %d pods", inventory.Groups, inventory.Pods)
+	}
+	expectedNamespaces := []leaderworkersetoperatorv1.NamespaceWorkloadCounts{
+		{Namespace: "team-a", LeaderWorkerSets: 2},
+		{Namespace: "team-b", LeaderWorkerSets: 1, DisaggregatedSets: 1},
+	}
+	if fmt.Sprint(inventory.Namespaces) != fmt.Sprint(expectedNamespaces) || len(namespaces) != 2 {
+		t.Errorf("expected namespaces %v, got %v", expectedNamespaces, inventory.Namespaces)
+	}
+
+	// the status lists the namespaces with the most workloads, the metrics all of them
+	leaderWorkerSets = nil
+	for i := 0; i <= maxInventoryNamespaces; i++ {
+		leaderWorkerSets = append(leaderWorkerSets, newWorkload(leaderWorkerSetWorkloads, fmt.Sprintf("ns-%03d", i), "lws", nil, nil))
+	}
+	leaderWorkerSets = append(leaderWorkerSets, newWorkload(leaderWorkerSetWorkloads, "ns-100", "second", nil, nil))
+	inventory, namespaces = workloadInventory(leaderWorkerSets, nil)
+	if len(inventory.Namespaces) != maxInventoryNamespaces || len(namespaces) != maxInventoryNamespaces+1 {
+		t.Fatalf("expected %d namespaces in status and %d in total, got %d and %d", maxInventoryNamespaces, maxInventoryNamespaces+1, len(inventory.Namespaces), len(namespaces))
+	}
+	if inventory.Namespaces[0].Namespace != "ns-000" || inventory.Namespaces[maxInventoryNamespaces-1].Namespace != "ns-100" {
+		t.Errorf("expected the busiest namespaces sorted by name, got %v", inventory.Namespaces)
+	}
+}
+
+func TestWorkloadInventoryController(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// only the LeaderWorkerSet CRD is installed
+	apiextensionClient := apiextensionsfake.NewSimpleClientset(newEstablishedCRD(leaderWorkerSetCRDName, "v1")) //nolint:staticcheck
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			leaderWorkerSetWorkloads.gvr:  "LeaderWorkerSetList",
+			disaggregatedSetWorkloads.gvr: "DisaggregatedSetList",
+		},
+		newWorkload(leaderWorkerSetWorkloads, "team-a", "vllm",
+			map[string]interface{}{"leaderWorkerTemplate": map[string]interface{}{"size": int64(2)}},
+			map[string]interface{}{"replicas": int64(3), "conditions": workloadConditions("Available=True")}),
+	)
+	operatorClient := operatorconfigfake.NewClientset(&leaderworkersetoperatorv1.LeaderWorkerSetOperator{
+		ObjectMeta: metav1.ObjectMeta{Name: operatorclient.OperatorConfigName, ResourceVersion: "1"},
+		Spec: leaderworkersetoperatorv1.LeaderWorkerSetOperatorSpec{
+			OperatorSpec: operatorv1.OperatorSpec{ManagementState: operatorv1.Managed},
+		},
+	})
+
+	operatorInformers := operatorclientinformers.NewSharedInformerFactory(operatorClient, 0)
+	operatorInformer := operatorInformers.OpenShiftOperator().V1().LeaderWorkerSetOperators()
+	apiextensionInformers := apiextensionsinformers.NewSharedInformerFactory(apiextensionClient, 0)
+	crdInformer := apiextensionInformers.Apiextensions().V1().CustomResourceDefinitions()
+	recorder := events.NewInMemoryRecorder("test", clock.RealClock{})
+	c := &WorkloadInventoryController{
+		operatorClient: &operatorclient.LeaderWorkerSetClient{
+			Ctx:            ctx,
+			SharedInformer: operatorInformer.Informer(),
+			Lister:         operatorInformer.Lister(),
+			OperatorClient: operatorClient.OpenShiftOperatorV1(),
+		},
+		crdLister: crdInformer.Lister(),
+		informers: dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, 0),
+		workloads: map[string]informers.GenericInformer{},
+	}
+	operatorInformers.Start(ctx.Done())
+	apiextensionInformers.Start(ctx.Done())
+	operatorInformers.WaitForCacheSync(ctx.Done())
+	apiextensionInformers.WaitForCacheSync(ctx.Done())
+
+	syncCtx := factory.NewSyncContext("test", recorder)
+	var workloads *leaderworkersetoperatorv1.WorkloadInventory
+	if err := wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, 5*time.Second, true, func(ctx context.Context) (bool, error) {
+		if err := c.sync(ctx, syncCtx); err != nil {
+			return false, err
+		}
+		operator, err := operatorClient.OpenShiftOperatorV1().LeaderWorkerSetOperators().Get(ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		workloads = operator.Status.Workloads
+		return workloads != nil, nil
+	}); err != nil {
+		t.Fatalf("status.workloads was not set: %v", err)
+	}
+
+	if _, ok := c.workloads[disaggregatedSetWorkloads.kind]; ok {
+		t.Errorf("expected DisaggregatedSets not to be watched before their CRD is served")
+	}
+	if workloads.LeaderWorkerSets.Total != 1 || workloads.Groups != 3 || workloads.Pods != 6 {
+		t.Errorf("unexpected inventory %+v", workloads)
+	}
+
+	expected := `
+# HELP lws_operator_workloads_pods [ALPHA] Number of pods of all LeaderWorkerSet groups, the leaders included.
+# TYPE lws_operator_workloads_pods gauge
+lws_operator_workloads_pods 6
+`
+	if err := testutil.GatherAndCompare(legacyregistry.DefaultGatherer, strings.NewReader(expected), "lws_operator_workloads_pods"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRecordWorkloadInventory(t *testing.T) {
+	workloadCount.Reset()
+	series := func() map[string]float64 {
+		t.Helper()
+		families, err := legacyregistry.DefaultGatherer.Gather()
+		if err != nil {
+			t.Fatal(err)
+		}
+		values := map[string]float64{}
+		for _, family := range families {
+			if family.GetName() != "lws_operator_workloads" {
+				continue
+			}
+			for _, metric := range family.GetMetric() {
+				labels := map[string]string{}
+				for _, label := range metric.GetLabel() {
+					labels[label.GetName()] = label.GetValue()
+				}
+				values[labels["kind"]+"/"+labels["namespace"]] = metric.GetGauge().GetValue()
+			}
+		}
+		return values
+	}
+
+	// the namespaces beyond the busiest ones are summed up
+	var leaderWorkerSets []*unstructured.Unstructured
+	for i := 0; i < maxInventoryNamespaces+2; i++ {
+		leaderWorkerSets = append(leaderWorkerSets, newWorkload(leaderWorkerSetWorkloads, fmt.Sprintf("ns-%03d", i), "lws", nil, nil))
+	}
+	leaderWorkerSets = append(leaderWorkerSets, newWorkload(leaderWorkerSetWorkloads, "ns-101", "second", nil, nil))
+	disaggregatedSets := []*unstructured.Unstructured{newWorkload(disaggregatedSetWorkloads, "ns-000", "llm-d", nil, nil)}
+	inventory, namespaces := workloadInventory(leaderWorkerSets, disaggregatedSets)
+	recorded := recordWorkloadInventory(inventory, namespaces, nil)
+	values := series()
+	if len(values) != maxInventoryNamespaces+2 || len(recorded) != len(values) {
+		t.Fatalf("expected %d namespaces, the other namespaces and a DisaggregatedSet series, got %d: %v", maxInventoryNamespaces, len(values), values)
+	}
+	for name, expected := range map[string]float64{
+		"LeaderWorkerSet/ns-101":  2,
+		"LeaderWorkerSet/ns-000":  1,
+		"LeaderWorkerSet/other":   2,
+		"DisaggregatedSet/ns-000": 1,
+	} {
+		if values[name] != expected {
+			t.Errorf("expected %s to be %v, got %v", name, expected, values[name])
+		}
+	}
+
+	// the series of the namespaces without workloads are deleted
+	inventory, namespaces = workloadInventory(leaderWorkerSets[:1], nil)
+	recorded = recordWorkloadInventory(inventory, namespaces, recorded)
+	if values := series(); len(values) != 1 || values["LeaderWorkerSet/ns-000"] != 1 || len(recorded) != 1 {
+		t.Errorf("expected only the LeaderWorkerSet/ns-000 series, got %v", values)
+	}
+}